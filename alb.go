@@ -0,0 +1,116 @@
+package lambdabase
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/go-nacelle/nacelle/v2"
+)
+
+type albHandler struct {
+	Logger   nacelle.Logger            `service:"logger"`
+	Services *nacelle.ServiceContainer `service:"services"`
+	handler  http.Handler
+}
+
+func NewALBServer(handler http.Handler, configs ...ConfigFunc) *Server {
+	return NewServer(&albHandler{
+		handler: handler,
+	}, configs...)
+}
+
+func (h *albHandler) Init(ctx context.Context) error {
+	return doInit(ctx, h.Services, h.handler)
+}
+
+func (h *albHandler) Invoke(ctx context.Context, payload []byte) ([]byte, error) {
+	event := events.ALBTargetGroupRequest{}
+	if err := json.Unmarshal(payload, &event); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal event (%w)", err)
+	}
+
+	logger := h.Logger.WithFields(map[string]interface{}{
+		"requestId": GetRequestID(ctx),
+	})
+
+	logger.Debug("Received ALB request %s %s", event.HTTPMethod, event.Path)
+
+	// The target group sends either single-value or multi-value headers
+	// depending on its configuration, and expects the response in kind.
+	multiValue := event.MultiValueHeaders != nil
+
+	u, err := url.Parse(event.Path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse request path (%w)", err)
+	}
+	u.RawQuery = makeALBQuery(event.QueryStringParameters, event.MultiValueQueryStringParameters)
+
+	header := makeHeader(event.Headers, event.MultiValueHeaders)
+
+	r, err := newHTTPRequest(
+		withALBTargetGroupRequest(ctx, event),
+		event.HTTPMethod,
+		u,
+		header,
+		event.Body,
+		event.IsBase64Encoded,
+		strings.TrimSpace(strings.Split(header.Get("X-Forwarded-For"), ",")[0]),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	w := newHTTPResponseWriter()
+	h.handler.ServeHTTP(w, r)
+	body, isBase64Encoded := w.encodedBody()
+
+	logger.Debug("ALB request handled with status %d", w.status())
+
+	response := events.ALBTargetGroupResponse{
+		StatusCode:        w.status(),
+		StatusDescription: fmt.Sprintf("%d %s", w.status(), http.StatusText(w.status())),
+		Body:              body,
+		IsBase64Encoded:   isBase64Encoded,
+	}
+
+	if multiValue {
+		response.MultiValueHeaders = w.multiValueHeaders()
+	} else {
+		response.Headers = w.singleValueHeaders()
+	}
+
+	return json.Marshal(response)
+}
+
+// makeALBQuery reconstructs the raw query string. Unlike API Gateway, the
+// load balancer forwards the path and query parameters exactly as the client
+// sent them, so they are already URL-encoded and must not be encoded again.
+func makeALBQuery(parameters map[string]string, multiValueParameters map[string][]string) string {
+	if len(multiValueParameters) == 0 {
+		multiValueParameters = make(map[string][]string, len(parameters))
+		for name, value := range parameters {
+			multiValueParameters[name] = []string{value}
+		}
+	}
+
+	names := make([]string, 0, len(multiValueParameters))
+	for name := range multiValueParameters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	pairs := []string{}
+	for _, name := range names {
+		for _, value := range multiValueParameters[name] {
+			pairs = append(pairs, name+"="+value)
+		}
+	}
+
+	return strings.Join(pairs, "&")
+}
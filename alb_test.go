@@ -0,0 +1,93 @@
+package lambdabase
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/go-nacelle/config/v3"
+	"github.com/go-nacelle/nacelle/v2"
+	"github.com/stretchr/testify/require"
+)
+
+var testALBMultiValuePayload = `{
+	"requestContext": {"elb": {"targetGroupArn": "arn:aws:elasticloadbalancing:us-east-1:123456789012:targetgroup/tg/abc"}},
+	"httpMethod": "PUT",
+	"path": "/widgets/42",
+	"multiValueQueryStringParameters": {"q": ["a%20b", "c"]},
+	"multiValueHeaders": {"x-forwarded-for": ["10.0.0.1, 10.0.0.2"], "x-tag": ["a", "b"]},
+	"body": "hello",
+	"isBase64Encoded": false
+}`
+
+var testALBSingleValuePayload = `{
+	"requestContext": {"elb": {"targetGroupArn": "arn:aws:elasticloadbalancing:us-east-1:123456789012:targetgroup/tg/abc"}},
+	"httpMethod": "GET",
+	"path": "/widgets",
+	"queryStringParameters": {"q": "a%20b"},
+	"headers": {"x-forwarded-for": "10.0.0.1"},
+	"body": "",
+	"isBase64Encoded": false
+}`
+
+var testALBHandler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	event, _ := GetALBTargetGroupRequest(r.Context())
+
+	w.Header().Add("X-Echo", "x")
+	w.Header().Add("X-Echo", "y")
+	w.Header().Set("Content-Type", "text/plain")
+	w.WriteHeader(http.StatusAccepted)
+	fmt.Fprintf(w, "%s %s %v %v %s %s %s", r.Method, r.URL.Path, r.URL.Query()["q"], r.Header.Values("X-Tag"), r.RemoteAddr, event.RequestContext.ELB.TargetGroupArn, body)
+})
+
+func TestALBInit(t *testing.T) {
+	handler := &testInitHTTPHandler{}
+	outer := &albHandler{
+		handler:  handler,
+		Logger:   nacelle.NewNilLogger(),
+		Services: nacelle.NewServiceContainer(),
+	}
+
+	ctx := context.Background()
+
+	cfg := nacelle.NewConfig(nacelle.NewTestEnvSourcer(nil))
+	ctx = config.WithConfig(ctx, cfg)
+
+	err := outer.Init(ctx)
+	require.Nil(t, err)
+	require.True(t, handler.initialized)
+}
+
+func TestALBInvokeMultiValue(t *testing.T) {
+	outer := &albHandler{handler: testALBHandler, Logger: nacelle.NewNilLogger()}
+
+	payload, err := outer.Invoke(context.Background(), []byte(testALBMultiValuePayload))
+	require.Nil(t, err)
+
+	response := events.ALBTargetGroupResponse{}
+	require.Nil(t, json.Unmarshal(payload, &response))
+	require.Equal(t, http.StatusAccepted, response.StatusCode)
+	require.Equal(t, "202 Accepted", response.StatusDescription)
+	require.Nil(t, response.Headers)
+	require.Equal(t, []string{"x", "y"}, response.MultiValueHeaders["X-Echo"])
+	require.Equal(t, "PUT /widgets/42 [a b c] [a b] 10.0.0.1 arn:aws:elasticloadbalancing:us-east-1:123456789012:targetgroup/tg/abc hello", response.Body)
+}
+
+func TestALBInvokeSingleValue(t *testing.T) {
+	outer := &albHandler{handler: testALBHandler, Logger: nacelle.NewNilLogger()}
+
+	payload, err := outer.Invoke(context.Background(), []byte(testALBSingleValuePayload))
+	require.Nil(t, err)
+
+	response := events.ALBTargetGroupResponse{}
+	require.Nil(t, json.Unmarshal(payload, &response))
+	require.Equal(t, "202 Accepted", response.StatusDescription)
+	require.Nil(t, response.MultiValueHeaders)
+	require.Equal(t, "x, y", response.Headers["X-Echo"])
+	require.Equal(t, "GET /widgets [a b] [] 10.0.0.1 arn:aws:elasticloadbalancing:us-east-1:123456789012:targetgroup/tg/abc ", response.Body)
+}
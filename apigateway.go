@@ -0,0 +1,72 @@
+package lambdabase
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/go-nacelle/nacelle/v2"
+)
+
+type apiGatewayHandler struct {
+	Logger   nacelle.Logger            `service:"logger"`
+	Services *nacelle.ServiceContainer `service:"services"`
+	handler  http.Handler
+}
+
+func NewAPIGatewayServer(handler http.Handler, configs ...ConfigFunc) *Server {
+	return NewServer(&apiGatewayHandler{
+		handler: handler,
+	}, configs...)
+}
+
+func (h *apiGatewayHandler) Init(ctx context.Context) error {
+	return doInit(ctx, h.Services, h.handler)
+}
+
+func (h *apiGatewayHandler) Invoke(ctx context.Context, payload []byte) ([]byte, error) {
+	event := events.APIGatewayProxyRequest{}
+	if err := json.Unmarshal(payload, &event); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal event (%w)", err)
+	}
+
+	logger := h.Logger.WithFields(map[string]interface{}{
+		"requestId": GetRequestID(ctx),
+	})
+
+	logger.Debug("Received API Gateway request %s %s", event.HTTPMethod, event.Path)
+
+	u := &url.URL{
+		Path:     event.Path,
+		RawQuery: makeQuery(event.QueryStringParameters, event.MultiValueQueryStringParameters),
+	}
+
+	r, err := newHTTPRequest(
+		withAPIGatewayProxyRequest(ctx, event),
+		event.HTTPMethod,
+		u,
+		makeHeader(event.Headers, event.MultiValueHeaders),
+		event.Body,
+		event.IsBase64Encoded,
+		event.RequestContext.Identity.SourceIP,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	w := newHTTPResponseWriter()
+	h.handler.ServeHTTP(w, r)
+	body, isBase64Encoded := w.encodedBody()
+
+	logger.Debug("API Gateway request handled with status %d", w.status())
+
+	return json.Marshal(events.APIGatewayProxyResponse{
+		StatusCode:        w.status(),
+		MultiValueHeaders: w.multiValueHeaders(),
+		Body:              body,
+		IsBase64Encoded:   isBase64Encoded,
+	})
+}
@@ -0,0 +1,115 @@
+package lambdabase
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/go-nacelle/config/v3"
+	"github.com/go-nacelle/nacelle/v2"
+	"github.com/stretchr/testify/require"
+)
+
+var testAPIGatewayPayload = `{
+	"resource": "/widgets/{id}",
+	"path": "/widgets/42",
+	"httpMethod": "POST",
+	"headers": {"Content-Type": "text/plain", "Cookie": "session=abc"},
+	"multiValueHeaders": {"Content-Type": ["text/plain"], "Cookie": ["session=abc"], "X-Tag": ["a", "b"]},
+	"queryStringParameters": {"color": "blue"},
+	"multiValueQueryStringParameters": {"color": ["red", "blue"]},
+	"pathParameters": {"id": "42"},
+	"requestContext": {"requestId": "r1", "identity": {"sourceIp": "10.0.0.1"}},
+	"body": "aGVsbG8=",
+	"isBase64Encoded": true
+}`
+
+func TestAPIGatewayInit(t *testing.T) {
+	handler := &testInitHTTPHandler{}
+	outer := &apiGatewayHandler{
+		handler:  handler,
+		Logger:   nacelle.NewNilLogger(),
+		Services: nacelle.NewServiceContainer(),
+	}
+
+	ctx := context.Background()
+
+	cfg := nacelle.NewConfig(nacelle.NewTestEnvSourcer(nil))
+	ctx = config.WithConfig(ctx, cfg)
+
+	err := outer.Init(ctx)
+	require.Nil(t, err)
+	require.True(t, handler.initialized)
+}
+
+func TestAPIGatewayInvoke(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		event, ok := GetAPIGatewayProxyRequest(r.Context())
+		cookie, _ := r.Cookie("session")
+
+		w.Header().Set("Content-Type", "text/plain")
+		w.Header().Add("X-Echo", r.Header.Values("X-Tag")[0])
+		w.Header().Add("X-Echo", r.Header.Values("X-Tag")[1])
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprintf(w, "%s %s %s %v %s %s %s", r.Method, r.URL.Path, r.URL.Query()["color"], ok, event.PathParameters["id"], cookie.Value, body)
+		fmt.Fprintf(w, " %s", r.RemoteAddr)
+	})
+
+	outer := &apiGatewayHandler{handler: handler, Logger: nacelle.NewNilLogger()}
+
+	payload, err := outer.Invoke(context.Background(), []byte(testAPIGatewayPayload))
+	require.Nil(t, err)
+
+	response := events.APIGatewayProxyResponse{}
+	require.Nil(t, json.Unmarshal(payload, &response))
+	require.Equal(t, http.StatusCreated, response.StatusCode)
+	require.Equal(t, []string{"a", "b"}, response.MultiValueHeaders["X-Echo"])
+	require.False(t, response.IsBase64Encoded)
+	require.Equal(t, "POST /widgets/42 [red blue] true 42 abc hello 10.0.0.1", response.Body)
+}
+
+func TestAPIGatewayInvokeBinaryResponse(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/octet-stream")
+		_, _ = w.Write([]byte{0xff, 0xfe, 0x00})
+	})
+
+	outer := &apiGatewayHandler{handler: handler, Logger: nacelle.NewNilLogger()}
+
+	payload, err := outer.Invoke(context.Background(), []byte(`{"httpMethod": "GET", "path": "/"}`))
+	require.Nil(t, err)
+
+	response := events.APIGatewayProxyResponse{}
+	require.Nil(t, json.Unmarshal(payload, &response))
+	require.Equal(t, http.StatusOK, response.StatusCode)
+	require.True(t, response.IsBase64Encoded)
+	require.Equal(t, base64.StdEncoding.EncodeToString([]byte{0xff, 0xfe, 0x00}), response.Body)
+}
+
+func TestAPIGatewayInvokeMalformedBody(t *testing.T) {
+	outer := &apiGatewayHandler{handler: http.NotFoundHandler(), Logger: nacelle.NewNilLogger()}
+
+	_, err := outer.Invoke(context.Background(), []byte(`{"httpMethod": "GET", "path": "/", "body": "!!!", "isBase64Encoded": true}`))
+	require.NotNil(t, err)
+	require.Contains(t, err.Error(), "failed to decode request body")
+}
+
+//
+// Init
+
+type testInitHTTPHandler struct {
+	initialized bool
+}
+
+func (h *testInitHTTPHandler) Init(ctx context.Context) error {
+	h.initialized = true
+	return nil
+}
+
+func (h *testInitHTTPHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {}
@@ -0,0 +1,174 @@
+package lambdabase
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+	"sync"
+	"sync/atomic"
+
+	"github.com/go-nacelle/config/v3"
+)
+
+// batchPanic is raised by processBatch in place of a panic raised by handle
+// on a worker goroutine. It carries the stack of the worker, which would
+// otherwise be lost when the panic is raised again on the calling goroutine.
+type batchPanic struct {
+	value interface{}
+	stack []byte
+}
+
+var errRecordSkipped = fmt.Errorf("record skipped after an earlier failure")
+
+func (p *batchPanic) String() string {
+	return fmt.Sprintf("%v\n\n%s", p.value, p.stack)
+}
+
+// processBatch invokes handle once for each record index and returns the
+// per-record errors in batch order. Records that share a non-empty key are
+// handled sequentially in batch order, and a failure skips the remaining
+// records of the same key. Groups of records with distinct keys are handled
+// by up to concurrency workers. If haltOnError is true, no new records are
+// started after any failure. Records that are never started are assigned
+// errRecordSkipped. A panic raised by handle stops the batch and is raised
+// again on the calling goroutine as a *batchPanic.
+func processBatch(keys []string, concurrency int, haltOnError bool, handle func(i int) error) []error {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	errs := make([]error, len(keys))
+	groups := groupRecords(keys, concurrency)
+
+	var halted int32
+	var panicOnce sync.Once
+	var panicValue *batchPanic
+	ch := make(chan []int, len(groups))
+	for _, group := range groups {
+		ch <- group
+	}
+	close(ch)
+
+	workers := concurrency
+	if workers > len(groups) {
+		workers = len(groups)
+	}
+
+	wg := sync.WaitGroup{}
+	for n := 0; n < workers; n++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+			defer func() {
+				if value := recover(); value != nil {
+					atomic.StoreInt32(&halted, 1)
+					panicOnce.Do(func() { panicValue = &batchPanic{value: value, stack: debug.Stack()} })
+				}
+			}()
+
+			for group := range ch {
+				failedKeys := map[string]struct{}{}
+
+				for _, i := range group {
+					if _, ok := failedKeys[keys[i]]; ok || atomic.LoadInt32(&halted) != 0 {
+						errs[i] = errRecordSkipped
+						continue
+					}
+
+					if err := handle(i); err != nil {
+						errs[i] = err
+
+						if keys[i] != "" {
+							failedKeys[keys[i]] = struct{}{}
+						}
+
+						if haltOnError {
+							atomic.StoreInt32(&halted, 1)
+						}
+					}
+				}
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	if panicValue != nil {
+		panic(panicValue)
+	}
+
+	return errs
+}
+
+// groupRecords partitions the record indexes into groups that must be
+// handled sequentially. Without concurrency, the entire batch forms a
+// single group so that records are handled strictly in batch order.
+func groupRecords(keys []string, concurrency int) [][]int {
+	if concurrency <= 1 {
+		group := make([]int, 0, len(keys))
+		for i := range keys {
+			group = append(group, i)
+		}
+
+		return [][]int{group}
+	}
+
+	groups := [][]int{}
+	groupIndexes := map[string]int{}
+
+	for i, key := range keys {
+		if key == "" {
+			groups = append(groups, []int{i})
+			continue
+		}
+
+		if j, ok := groupIndexes[key]; ok {
+			groups[j] = append(groups[j], i)
+			continue
+		}
+
+		groupIndexes[key] = len(groups)
+		groups = append(groups, []int{i})
+	}
+
+	return groups
+}
+
+// firstFailure returns the index of the first record in batch order that
+// failed with an error other than errRecordSkipped, or -1 if there is none.
+func firstFailure(errs []error) int {
+	for i, err := range errs {
+		if err != nil && err != errRecordSkipped {
+			return i
+		}
+	}
+
+	return -1
+}
+
+func loadRecordConcurrency(ctx context.Context, concurrency int) (int, error) {
+	recordConfig := &RecordConfig{}
+	if err := config.LoadFromContext(ctx, recordConfig); err != nil {
+		return 0, err
+	}
+
+	if recordConfig.RecordConcurrency > 0 {
+		return recordConfig.RecordConcurrency, nil
+	}
+
+	return concurrency, nil
+}
+
+func loadReportBatchItemFailures(ctx context.Context, enabled bool) (bool, error) {
+	recordConfig := &RecordConfig{}
+	if err := config.LoadFromContext(ctx, recordConfig); err != nil {
+		return false, err
+	}
+
+	if recordConfig.ReportBatchItemFailures != nil {
+		return *recordConfig.ReportBatchItemFailures, nil
+	}
+
+	return enabled, nil
+}
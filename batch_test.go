@@ -0,0 +1,143 @@
+package lambdabase
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-nacelle/config/v3"
+	"github.com/go-nacelle/nacelle/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProcessBatchSequential(t *testing.T) {
+	order := []int{}
+	errs := processBatch([]string{"a", "b", "a", ""}, 1, false, func(i int) error {
+		order = append(order, i)
+		return nil
+	})
+
+	require.Equal(t, []error{nil, nil, nil, nil}, errs)
+	require.Equal(t, []int{0, 1, 2, 3}, order)
+}
+
+func TestProcessBatchHaltOnError(t *testing.T) {
+	errs := processBatch([]string{"", "", ""}, 1, true, func(i int) error {
+		if i == 1 {
+			return fmt.Errorf("oops")
+		}
+
+		return nil
+	})
+
+	require.Equal(t, []error{nil, fmt.Errorf("oops"), errRecordSkipped}, errs)
+	require.Equal(t, 1, firstFailure(errs))
+}
+
+func TestProcessBatchSkipsFailedKey(t *testing.T) {
+	errs := processBatch([]string{"a", "b", "a", "b"}, 1, false, func(i int) error {
+		if i == 0 {
+			return fmt.Errorf("oops")
+		}
+
+		return nil
+	})
+
+	require.Equal(t, []error{fmt.Errorf("oops"), nil, errRecordSkipped, nil}, errs)
+}
+
+func TestProcessBatchConcurrent(t *testing.T) {
+	var (
+		mu      sync.Mutex
+		order   = map[string][]int{}
+		running = 0
+		peak    = 0
+	)
+
+	keys := []string{"a", "b", "c", "a", "b", "c", "a", "b", "c"}
+
+	errs := processBatch(keys, 2, false, func(i int) error {
+		mu.Lock()
+		order[keys[i]] = append(order[keys[i]], i)
+		running++
+		if running > peak {
+			peak = running
+		}
+		mu.Unlock()
+
+		time.Sleep(time.Millisecond * 10)
+
+		mu.Lock()
+		running--
+		mu.Unlock()
+		return nil
+	})
+
+	require.Equal(t, make([]error, len(keys)), errs)
+	require.Equal(t, map[string][]int{"a": {0, 3, 6}, "b": {1, 4, 7}, "c": {2, 5, 8}}, order)
+	require.Equal(t, 2, peak)
+}
+
+func TestProcessBatchPanic(t *testing.T) {
+	defer func() {
+		value := recover()
+		p, ok := value.(*batchPanic)
+		require.True(t, ok)
+		require.Equal(t, "oops", p.value)
+
+		// The stack is that of the worker that panicked
+		require.Contains(t, string(p.stack), "testBatchPanic")
+		require.Contains(t, fmt.Sprintf("%v", p), "oops")
+	}()
+
+	processBatch([]string{"a", "b"}, 2, false, func(i int) error {
+		if i == 1 {
+			testBatchPanic()
+		}
+
+		return nil
+	})
+}
+
+func TestLoadRecordConcurrency(t *testing.T) {
+	ctx := context.Background()
+	ctx = config.WithConfig(ctx, nacelle.NewConfig(nacelle.NewTestEnvSourcer(nil)))
+
+	concurrency, err := loadRecordConcurrency(ctx, 4)
+	require.Nil(t, err)
+	require.Equal(t, 4, concurrency)
+
+	ctx = config.WithConfig(ctx, nacelle.NewConfig(nacelle.NewTestEnvSourcer(map[string]string{
+		"lambda_record_concurrency": "8",
+	})))
+
+	concurrency, err = loadRecordConcurrency(ctx, 4)
+	require.Nil(t, err)
+	require.Equal(t, 8, concurrency)
+}
+
+func TestLoadReportBatchItemFailures(t *testing.T) {
+	ctx := context.Background()
+	ctx = config.WithConfig(ctx, nacelle.NewConfig(nacelle.NewTestEnvSourcer(nil)))
+
+	enabled, err := loadReportBatchItemFailures(ctx, true)
+	require.Nil(t, err)
+	require.True(t, enabled)
+
+	ctx = config.WithConfig(ctx, nacelle.NewConfig(nacelle.NewTestEnvSourcer(map[string]string{
+		"lambda_report_batch_item_failures": "false",
+	})))
+
+	enabled, err = loadReportBatchItemFailures(ctx, true)
+	require.Nil(t, err)
+	require.False(t, enabled)
+}
+
+//
+// Helpers
+
+func testBatchPanic() {
+	panic("oops")
+}
@@ -0,0 +1,86 @@
+package lambdabase
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/go-nacelle/nacelle/v2"
+)
+
+type (
+	CloudWatchLogsHandler interface {
+		Handle(ctx context.Context, logEvent events.CloudwatchLogsLogEvent, logger nacelle.Logger) error
+	}
+
+	cloudWatchLogsHandlerInitializer interface {
+		nacelle.Initializer
+		CloudWatchLogsHandler
+	}
+
+	cloudWatchLogsHandler struct {
+		Logger           nacelle.Logger            `service:"logger"`
+		Services         *nacelle.ServiceContainer `service:"services"`
+		handler          CloudWatchLogsHandler
+		recordMiddleware []RecordMiddleware
+	}
+)
+
+// controlMessageType is the message type of the payloads CloudWatch Logs
+// sends to check that the destination of a subscription is reachable.
+const controlMessageType = "CONTROL_MESSAGE"
+
+func NewCloudWatchLogsServer(handler CloudWatchLogsHandler, configs ...ConfigFunc) *Server {
+	options := getOptions(configs)
+
+	return NewServer(&cloudWatchLogsHandler{
+		handler:          handler,
+		recordMiddleware: options.recordMiddleware,
+	}, configs...)
+}
+
+func (h *cloudWatchLogsHandler) Init(ctx context.Context) error {
+	return doInit(ctx, h.Services, h.handler)
+}
+
+func (h *cloudWatchLogsHandler) Invoke(ctx context.Context, payload []byte) ([]byte, error) {
+	event := events.CloudwatchLogsEvent{}
+	if err := json.Unmarshal(payload, &event); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal event (%w)", err)
+	}
+
+	data, err := event.AWSLogs.Parse()
+	if err != nil {
+		return nil, &DecodeError{Part: "CloudWatch Logs data", Err: err}
+	}
+
+	logger := h.Logger.WithFields(map[string]interface{}{
+		"requestId":           GetRequestID(ctx),
+		"logGroup":            data.LogGroup,
+		"logStream":           data.LogStream,
+		"subscriptionFilters": data.SubscriptionFilters,
+	})
+
+	if data.MessageType == controlMessageType {
+		logger.Debug("Dropping CloudWatch Logs control message")
+		return nil, nil
+	}
+
+	logger.Debug("Received %d CloudWatch Logs events", len(data.LogEvents))
+
+	for _, logEvent := range data.LogEvents {
+		logEventLogger := logger.WithFields(map[string]interface{}{
+			"logEventId": logEvent.ID,
+		})
+
+		logEventLogger.Debug("Handling log event")
+
+		if err := invokeRecordHandler(ctx, h.recordMiddleware, logEvent, logEventLogger, h.handler.Handle); err != nil {
+			return nil, newRecordError("CloudWatch Logs", "event", logEvent.ID, err)
+		}
+	}
+
+	logger.Debug("CloudWatch Logs events handled successfully")
+	return nil, nil
+}
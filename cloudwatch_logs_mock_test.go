@@ -0,0 +1,298 @@
+// Code generated by go-mockgen 1.3.5; DO NOT EDIT.
+
+package lambdabase
+
+import (
+	"context"
+	"sync"
+
+	events "github.com/aws/aws-lambda-go/events"
+	v2 "github.com/go-nacelle/log/v2"
+)
+
+// MockCloudWatchLogsHandlerInitializer is a mock implementation of the
+// cloudWatchLogsHandlerInitializer interface (from the package
+// github.com/go-nacelle/lambdabase) used for unit testing.
+type MockCloudWatchLogsHandlerInitializer struct {
+	// HandleFunc is an instance of a mock function object controlling the
+	// behavior of the method Handle.
+	HandleFunc *CloudWatchLogsHandlerInitializerHandleFunc
+	// InitFunc is an instance of a mock function object controlling the
+	// behavior of the method Init.
+	InitFunc *CloudWatchLogsHandlerInitializerInitFunc
+}
+
+// NewMockCloudWatchLogsHandlerInitializer creates a new mock of the
+// cloudWatchLogsHandlerInitializer interface. All methods return zero values
+// for all results, unless overwritten.
+func NewMockCloudWatchLogsHandlerInitializer() *MockCloudWatchLogsHandlerInitializer {
+	return &MockCloudWatchLogsHandlerInitializer{
+		HandleFunc: &CloudWatchLogsHandlerInitializerHandleFunc{
+			defaultHook: func(context.Context, events.CloudwatchLogsLogEvent, v2.Logger) (r0 error) {
+				return
+			},
+		},
+		InitFunc: &CloudWatchLogsHandlerInitializerInitFunc{
+			defaultHook: func(context.Context) (r0 error) {
+				return
+			},
+		},
+	}
+}
+
+// NewStrictMockCloudWatchLogsHandlerInitializer creates a new mock of the
+// cloudWatchLogsHandlerInitializer interface. All methods panic on invocation,
+// unless overwritten.
+func NewStrictMockCloudWatchLogsHandlerInitializer() *MockCloudWatchLogsHandlerInitializer {
+	return &MockCloudWatchLogsHandlerInitializer{
+		HandleFunc: &CloudWatchLogsHandlerInitializerHandleFunc{
+			defaultHook: func(context.Context, events.CloudwatchLogsLogEvent, v2.Logger) error {
+				panic("unexpected invocation of MockCloudWatchLogsHandlerInitializer.Handle")
+			},
+		},
+		InitFunc: &CloudWatchLogsHandlerInitializerInitFunc{
+			defaultHook: func(context.Context) error {
+				panic("unexpected invocation of MockCloudWatchLogsHandlerInitializer.Init")
+			},
+		},
+	}
+}
+
+// surrogateMockCloudWatchLogsHandlerInitializer is a copy of the
+// cloudWatchLogsHandlerInitializer interface (from the package
+// github.com/go-nacelle/lambdabase). It is redefined here as it is
+// unexported in the source package.
+type surrogateMockCloudWatchLogsHandlerInitializer interface {
+	Handle(context.Context, events.CloudwatchLogsLogEvent, v2.Logger) error
+	Init(context.Context) error
+}
+
+// NewMockCloudWatchLogsHandlerInitializerFrom creates a new mock of the
+// MockCloudWatchLogsHandlerInitializer interface. All methods delegate to the
+// given implementation, unless overwritten.
+func NewMockCloudWatchLogsHandlerInitializerFrom(i surrogateMockCloudWatchLogsHandlerInitializer) *MockCloudWatchLogsHandlerInitializer {
+	return &MockCloudWatchLogsHandlerInitializer{
+		HandleFunc: &CloudWatchLogsHandlerInitializerHandleFunc{
+			defaultHook: i.Handle,
+		},
+		InitFunc: &CloudWatchLogsHandlerInitializerInitFunc{
+			defaultHook: i.Init,
+		},
+	}
+}
+
+// CloudWatchLogsHandlerInitializerHandleFunc describes the behavior when the
+// Handle method of the parent MockCloudWatchLogsHandlerInitializer instance is
+// invoked.
+type CloudWatchLogsHandlerInitializerHandleFunc struct {
+	defaultHook func(context.Context, events.CloudwatchLogsLogEvent, v2.Logger) error
+	hooks       []func(context.Context, events.CloudwatchLogsLogEvent, v2.Logger) error
+	history     []CloudWatchLogsHandlerInitializerHandleFuncCall
+	mutex       sync.Mutex
+}
+
+// Handle delegates to the next hook function in the queue and stores the
+// parameter and result values of this invocation.
+func (m *MockCloudWatchLogsHandlerInitializer) Handle(v0 context.Context, v1 events.CloudwatchLogsLogEvent, v2 v2.Logger) error {
+	r0 := m.HandleFunc.nextHook()(v0, v1, v2)
+	m.HandleFunc.appendCall(CloudWatchLogsHandlerInitializerHandleFuncCall{v0, v1, v2, r0})
+	return r0
+}
+
+// SetDefaultHook sets function that is called when the Handle method of the
+// parent MockCloudWatchLogsHandlerInitializer instance is invoked and the hook
+// queue is empty.
+func (f *CloudWatchLogsHandlerInitializerHandleFunc) SetDefaultHook(hook func(context.Context, events.CloudwatchLogsLogEvent, v2.Logger) error) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// Handle method of the parent MockCloudWatchLogsHandlerInitializer instance
+// invokes the hook at the front of the queue and discards it. After the
+// queue is empty, the default hook function is invoked for any future
+// action.
+func (f *CloudWatchLogsHandlerInitializerHandleFunc) PushHook(hook func(context.Context, events.CloudwatchLogsLogEvent, v2.Logger) error) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultHook with a function that returns the
+// given values.
+func (f *CloudWatchLogsHandlerInitializerHandleFunc) SetDefaultReturn(r0 error) {
+	f.SetDefaultHook(func(context.Context, events.CloudwatchLogsLogEvent, v2.Logger) error {
+		return r0
+	})
+}
+
+// PushReturn calls PushHook with a function that returns the given values.
+func (f *CloudWatchLogsHandlerInitializerHandleFunc) PushReturn(r0 error) {
+	f.PushHook(func(context.Context, events.CloudwatchLogsLogEvent, v2.Logger) error {
+		return r0
+	})
+}
+
+func (f *CloudWatchLogsHandlerInitializerHandleFunc) nextHook() func(context.Context, events.CloudwatchLogsLogEvent, v2.Logger) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *CloudWatchLogsHandlerInitializerHandleFunc) appendCall(r0 CloudWatchLogsHandlerInitializerHandleFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of CloudWatchLogsHandlerInitializerHandleFuncCall
+// objects describing the invocations of this function.
+func (f *CloudWatchLogsHandlerInitializerHandleFunc) History() []CloudWatchLogsHandlerInitializerHandleFuncCall {
+	f.mutex.Lock()
+	history := make([]CloudWatchLogsHandlerInitializerHandleFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// CloudWatchLogsHandlerInitializerHandleFuncCall is an object that describes an
+// invocation of method Handle on an instance of
+// MockCloudWatchLogsHandlerInitializer.
+type CloudWatchLogsHandlerInitializerHandleFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 events.CloudwatchLogsLogEvent
+	// Arg2 is the value of the 3rd argument passed to this method
+	// invocation.
+	Arg2 v2.Logger
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c CloudWatchLogsHandlerInitializerHandleFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1, c.Arg2}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c CloudWatchLogsHandlerInitializerHandleFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0}
+}
+
+// CloudWatchLogsHandlerInitializerInitFunc describes the behavior when the Init
+// method of the parent MockCloudWatchLogsHandlerInitializer instance is
+// invoked.
+type CloudWatchLogsHandlerInitializerInitFunc struct {
+	defaultHook func(context.Context) error
+	hooks       []func(context.Context) error
+	history     []CloudWatchLogsHandlerInitializerInitFuncCall
+	mutex       sync.Mutex
+}
+
+// Init delegates to the next hook function in the queue and stores the
+// parameter and result values of this invocation.
+func (m *MockCloudWatchLogsHandlerInitializer) Init(v0 context.Context) error {
+	r0 := m.InitFunc.nextHook()(v0)
+	m.InitFunc.appendCall(CloudWatchLogsHandlerInitializerInitFuncCall{v0, r0})
+	return r0
+}
+
+// SetDefaultHook sets function that is called when the Init method of the
+// parent MockCloudWatchLogsHandlerInitializer instance is invoked and the hook
+// queue is empty.
+func (f *CloudWatchLogsHandlerInitializerInitFunc) SetDefaultHook(hook func(context.Context) error) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// Init method of the parent MockCloudWatchLogsHandlerInitializer instance
+// invokes the hook at the front of the queue and discards it. After the
+// queue is empty, the default hook function is invoked for any future
+// action.
+func (f *CloudWatchLogsHandlerInitializerInitFunc) PushHook(hook func(context.Context) error) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultHook with a function that returns the
+// given values.
+func (f *CloudWatchLogsHandlerInitializerInitFunc) SetDefaultReturn(r0 error) {
+	f.SetDefaultHook(func(context.Context) error {
+		return r0
+	})
+}
+
+// PushReturn calls PushHook with a function that returns the given values.
+func (f *CloudWatchLogsHandlerInitializerInitFunc) PushReturn(r0 error) {
+	f.PushHook(func(context.Context) error {
+		return r0
+	})
+}
+
+func (f *CloudWatchLogsHandlerInitializerInitFunc) nextHook() func(context.Context) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *CloudWatchLogsHandlerInitializerInitFunc) appendCall(r0 CloudWatchLogsHandlerInitializerInitFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of CloudWatchLogsHandlerInitializerInitFuncCall
+// objects describing the invocations of this function.
+func (f *CloudWatchLogsHandlerInitializerInitFunc) History() []CloudWatchLogsHandlerInitializerInitFuncCall {
+	f.mutex.Lock()
+	history := make([]CloudWatchLogsHandlerInitializerInitFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// CloudWatchLogsHandlerInitializerInitFuncCall is an object that describes an
+// invocation of method Init on an instance of
+// MockCloudWatchLogsHandlerInitializer.
+type CloudWatchLogsHandlerInitializerInitFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c CloudWatchLogsHandlerInitializerInitFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c CloudWatchLogsHandlerInitializerInitFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0}
+}
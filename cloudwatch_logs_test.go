@@ -0,0 +1,178 @@
+package lambdabase
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+	mockassert "github.com/derision-test/go-mockgen/testutil/assert"
+	"github.com/go-nacelle/config/v3"
+	"github.com/go-nacelle/nacelle/v2"
+	"github.com/stretchr/testify/require"
+)
+
+var testCloudWatchLogsData = `{
+	"messageType": "DATA_MESSAGE",
+	"owner": "123456789012",
+	"logGroup": "/aws/lambda/example",
+	"logStream": "2021/11/12/[$LATEST]abc",
+	"subscriptionFilters": ["filter"],
+	"logEvents": [
+		{"id": "l1", "timestamp": 1636675200000, "message": "foo"},
+		{"id": "l2", "timestamp": 1636675200001, "message": "bar"}
+	]
+}`
+
+var testCloudWatchLogsControlData = `{
+	"messageType": "CONTROL_MESSAGE",
+	"owner": "CloudwatchLogs",
+	"logGroup": "",
+	"logStream": "",
+	"subscriptionFilters": [],
+	"logEvents": [
+		{"id": "", "timestamp": 1636675200000, "message": "CWL CONTROL MESSAGE: Checking health of destination Kinesis stream."}
+	]
+}`
+
+var testCloudWatchLogsEvents = []events.CloudwatchLogsLogEvent{
+	{ID: "l1", Timestamp: 1636675200000, Message: "foo"},
+	{ID: "l2", Timestamp: 1636675200001, Message: "bar"},
+}
+
+func TestCloudWatchLogsInit(t *testing.T) {
+	handler := NewMockCloudWatchLogsHandlerInitializer()
+	outer := &cloudWatchLogsHandler{
+		handler:  handler,
+		Logger:   nacelle.NewNilLogger(),
+		Services: nacelle.NewServiceContainer(),
+	}
+
+	ctx := context.Background()
+
+	cfg := nacelle.NewConfig(nacelle.NewTestEnvSourcer(nil))
+	ctx = config.WithConfig(ctx, cfg)
+
+	err := outer.Init(ctx)
+	require.Nil(t, err)
+	mockassert.CalledOnceWith(t, handler.InitFunc, mockassert.Values(ctx))
+}
+
+func TestCloudWatchLogsBadInjection(t *testing.T) {
+	handler := &badInjectionCloudWatchLogsHandler{}
+	outer := &cloudWatchLogsHandler{
+		handler:  handler,
+		Logger:   nacelle.NewNilLogger(),
+		Services: makeBadContainer(),
+	}
+
+	ctx := context.Background()
+
+	cfg := nacelle.NewConfig(nacelle.NewTestEnvSourcer(nil))
+	ctx = config.WithConfig(ctx, cfg)
+
+	err := outer.Init(ctx)
+	require.NotNil(t, err)
+	require.Contains(t, err.Error(), "ServiceA")
+}
+
+func TestCloudWatchLogsInitError(t *testing.T) {
+	handler := NewMockCloudWatchLogsHandlerInitializer()
+	handler.InitFunc.SetDefaultReturn(fmt.Errorf("oops"))
+	outer := &cloudWatchLogsHandler{
+		handler:  handler,
+		Logger:   nacelle.NewNilLogger(),
+		Services: makeBadContainer(),
+	}
+
+	ctx := context.Background()
+
+	cfg := nacelle.NewConfig(nacelle.NewTestEnvSourcer(nil))
+	ctx = config.WithConfig(ctx, cfg)
+
+	err := outer.Init(ctx)
+	require.EqualError(t, err, "oops")
+}
+
+func TestCloudWatchLogsInvoke(t *testing.T) {
+	handler := NewMockCloudWatchLogsHandlerInitializer()
+	outer := &cloudWatchLogsHandler{
+		handler: handler,
+		Logger:  nacelle.NewNilLogger(),
+	}
+
+	response, err := outer.Invoke(context.Background(), makeCloudWatchLogsPayload(t, testCloudWatchLogsData))
+	require.Nil(t, err)
+	require.Nil(t, response)
+	mockassert.CalledN(t, handler.HandleFunc, 2)
+
+	for _, logEvent := range testCloudWatchLogsEvents {
+		mockassert.CalledOnceWith(t, handler.HandleFunc, mockassert.Values(mockassert.Skip, logEvent))
+	}
+}
+
+func TestCloudWatchLogsInvokeControlMessage(t *testing.T) {
+	handler := NewMockCloudWatchLogsHandlerInitializer()
+	outer := &cloudWatchLogsHandler{
+		handler: handler,
+		Logger:  nacelle.NewNilLogger(),
+	}
+
+	response, err := outer.Invoke(context.Background(), makeCloudWatchLogsPayload(t, testCloudWatchLogsControlData))
+	require.Nil(t, err)
+	require.Nil(t, response)
+	mockassert.NotCalled(t, handler.HandleFunc)
+}
+
+func TestCloudWatchLogsInvokeError(t *testing.T) {
+	handler := NewMockCloudWatchLogsHandlerInitializer()
+	handler.HandleFunc.PushReturn(fmt.Errorf("oops"))
+	outer := &cloudWatchLogsHandler{
+		handler: handler,
+		Logger:  nacelle.NewNilLogger(),
+	}
+
+	_, err := outer.Invoke(context.Background(), makeCloudWatchLogsPayload(t, testCloudWatchLogsData))
+	require.EqualError(t, err, "failed to process CloudWatch Logs event l1 (oops)")
+	mockassert.CalledN(t, handler.HandleFunc, 1)
+}
+
+func TestCloudWatchLogsInvokeDecodeError(t *testing.T) {
+	handler := NewMockCloudWatchLogsHandlerInitializer()
+	outer := &cloudWatchLogsHandler{
+		handler: handler,
+		Logger:  nacelle.NewNilLogger(),
+	}
+
+	_, err := outer.Invoke(context.Background(), []byte(`{"awslogs": {"data": "Zm9v"}}`))
+	require.NotNil(t, err)
+	require.Contains(t, err.Error(), "failed to decode CloudWatch Logs data")
+	mockassert.NotCalled(t, handler.HandleFunc)
+}
+
+//
+// Helpers
+
+func makeCloudWatchLogsPayload(t *testing.T, data string) []byte {
+	buf := bytes.Buffer{}
+	w := gzip.NewWriter(&buf)
+	_, err := w.Write([]byte(data))
+	require.Nil(t, err)
+	require.Nil(t, w.Close())
+
+	return []byte(fmt.Sprintf(`{"awslogs": {"data": %q}}`, base64.StdEncoding.EncodeToString(buf.Bytes())))
+}
+
+//
+// Bad Injection
+
+type badInjectionCloudWatchLogsHandler struct {
+	ServiceA *A `service:"A"`
+}
+
+func (i *badInjectionCloudWatchLogsHandler) Handle(ctx context.Context, logEvent events.CloudwatchLogsLogEvent, logger nacelle.Logger) error {
+	return nil
+}
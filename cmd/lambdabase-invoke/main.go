@@ -0,0 +1,166 @@
+// Command lambdabase-invoke sends a single invocation to a lambdabase server
+// listening for RPC commands and prints the response. The payload is read
+// from a file, from the -data flag, or from standard input.
+//
+//	_LAMBDA_SERVER_PORT=9001 ./my-function &
+//	lambdabase-invoke -port 9001 -request-id req-1 -deadline 5s < event.json
+//
+// The indented JSON payload of a successful invocation is written to standard
+// output. If the function returns an error, the error and its stack trace are
+// written to standard error and the command exits with status 1. The command
+// exits with status 2 if the server cannot be invoked.
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"time"
+
+	"github.com/aws/aws-lambda-go/lambda/messages"
+	"github.com/go-nacelle/lambdabase/rpcclient"
+)
+
+const (
+	exitFunctionError = 1
+	exitFailure       = 2
+)
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+// run invokes the server described by the given arguments and returns the
+// exit status of the command.
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("lambdabase-invoke", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+
+	var (
+		host        = flags.String("host", "localhost", "the host of the function")
+		port        = flags.String("port", os.Getenv("_LAMBDA_SERVER_PORT"), "the port on which the function listens for RPC commands (default $_LAMBDA_SERVER_PORT)")
+		payloadPath = flags.String("payload", "", "a file containing the payload, or - for standard input (default standard input)")
+		data        = flags.String("data", "", "the payload, instead of reading it from a file")
+		requestID   = flags.String("request-id", "", "the request ID of the invocation (default a random UUID)")
+		deadline    = flags.String("deadline", "", "the deadline of the invocation, as a duration from now or an RFC 3339 time (default one minute from now)")
+		functionARN = flags.String("function-arn", "arn:aws:lambda:us-east-1:123456789012:function:lambdabase-invoke", "the ARN of the invoked function")
+	)
+
+	if err := flags.Parse(args); err != nil {
+		return exitFailure
+	}
+
+	fail := func(format string, args ...interface{}) int {
+		fmt.Fprintf(stderr, "lambdabase-invoke: %s\n", fmt.Sprintf(format, args...))
+		return exitFailure
+	}
+
+	if *port == "" {
+		return fail("no port supplied (set -port or _LAMBDA_SERVER_PORT)")
+	}
+
+	if *data != "" && *payloadPath != "" {
+		return fail("only one of -data and -payload may be supplied")
+	}
+
+	payload := []byte(*data)
+	if *data == "" {
+		var err error
+		if payload, err = readPayload(*payloadPath, stdin); err != nil {
+			return fail("failed to read payload (%s)", err.Error())
+		}
+	}
+
+	configs := []rpcclient.InvokeConfigFunc{}
+	if *requestID != "" {
+		configs = append(configs, rpcclient.WithRequestID(*requestID))
+	}
+	if *deadline != "" {
+		t, err := parseDeadline(*deadline, time.Now())
+		if err != nil {
+			return fail("illegal deadline %q (%s)", *deadline, err.Error())
+		}
+
+		configs = append(configs, rpcclient.WithDeadline(t))
+	}
+
+	client, err := rpcclient.Dial(net.JoinHostPort(*host, *port), rpcclient.WithFunctionARN(*functionARN))
+	if err != nil {
+		return fail("failed to connect to function (%s)", err.Error())
+	}
+	defer client.Close()
+
+	response, err := client.Invoke(payload, configs...)
+	if err != nil {
+		return fail("failed to invoke function (%s)", err.Error())
+	}
+
+	if response.Error != nil {
+		writeError(stderr, response.Error)
+		return exitFunctionError
+	}
+
+	writePayload(stdout, response.Payload)
+	return 0
+}
+
+// readPayload reads the payload from the given file, or from stdin if the
+// path is empty or "-". An empty payload is replaced by an empty JSON object.
+func readPayload(path string, stdin io.Reader) ([]byte, error) {
+	var payload []byte
+	var err error
+	if path == "" || path == "-" {
+		payload, err = io.ReadAll(stdin)
+	} else {
+		payload, err = os.ReadFile(path)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if payload = bytes.TrimSpace(payload); len(payload) == 0 {
+		return []byte(`{}`), nil
+	}
+
+	return payload, nil
+}
+
+// parseDeadline parses a deadline given either as a duration relative to now
+// or as an absolute RFC 3339 time.
+func parseDeadline(value string, now time.Time) (time.Time, error) {
+	if d, err := time.ParseDuration(value); err == nil {
+		return now.Add(d), nil
+	}
+
+	return time.Parse(time.RFC3339Nano, value)
+}
+
+// writePayload writes the payload as indented JSON, or unchanged if it is not
+// valid JSON.
+func writePayload(w io.Writer, payload []byte) {
+	buf := &bytes.Buffer{}
+	if err := json.Indent(buf, payload, "", "  "); err != nil {
+		buf.Reset()
+		buf.Write(payload)
+	}
+
+	fmt.Fprintln(w, buf.String())
+}
+
+// writeError writes the type, message, and stack trace of an error returned
+// by the function.
+func writeError(w io.Writer, err *messages.InvokeResponse_Error) {
+	fmt.Fprintf(w, "%s: %s\n", err.Type, err.Message)
+
+	for _, frame := range err.StackTrace {
+		fmt.Fprintf(w, "    %s\n        %s:%d\n", frame.Label, frame.Path, frame.Line)
+	}
+
+	if err.ShouldExit {
+		fmt.Fprintln(w, "The function requested to exit after this error.")
+	}
+}
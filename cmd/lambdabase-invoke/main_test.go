@@ -0,0 +1,121 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-lambda-go/lambdacontext"
+	"github.com/go-nacelle/lambdabase"
+	"github.com/go-nacelle/lambdabase/lambdabasetest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInvoke(t *testing.T) {
+	port := startServer(t)
+
+	stdout, stderr, code := invoke(t, "", "-port", port, "-request-id", "bonk", "-data", `{"name":"foo"}`)
+	require.Equal(t, 0, code, stderr)
+	require.Equal(t, "{\n  \"name\": \"foo\",\n  \"requestId\": \"bonk\"\n}\n", stdout)
+}
+
+func TestInvokeStdin(t *testing.T) {
+	port := startServer(t)
+
+	stdout, _, code := invoke(t, `{"name": "bar"}`, "-port", port)
+	require.Equal(t, 0, code)
+	require.Contains(t, stdout, `"name": "bar"`)
+
+	path := filepath.Join(t.TempDir(), "payload.json")
+	require.Nil(t, os.WriteFile(path, []byte(`{"name": "baz"}`), 0644))
+
+	stdout, _, code = invoke(t, "", "-port", port, "-payload", path)
+	require.Equal(t, 0, code)
+	require.Contains(t, stdout, `"name": "baz"`)
+}
+
+func TestInvokeFunctionError(t *testing.T) {
+	port := startServer(t)
+
+	stdout, stderr, code := invoke(t, "", "-port", port, "-data", `{"name":"fail"}`)
+	require.Equal(t, exitFunctionError, code)
+	require.Empty(t, stdout)
+
+	lines := strings.Split(stderr, "\n")
+	require.Equal(t, "ValidationError: oops", lines[0])
+	require.Contains(t, lines[1], "lambdabase-invoke.startServer")
+	require.Contains(t, lines[2], "main_test.go:")
+}
+
+func TestInvokeUnreachable(t *testing.T) {
+	listener, err := net.Listen("tcp", "localhost:0")
+	require.Nil(t, err)
+	_, port, _ := net.SplitHostPort(listener.Addr().String())
+	listener.Close()
+
+	_, stderr, code := invoke(t, "", "-port", port)
+	require.Equal(t, exitFailure, code)
+	require.Contains(t, stderr, "failed to connect to function")
+}
+
+func TestParseDeadline(t *testing.T) {
+	now := time.Date(2024, time.January, 15, 12, 0, 0, 0, time.UTC)
+
+	deadline, err := parseDeadline("5s", now)
+	require.Nil(t, err)
+	require.Equal(t, now.Add(5*time.Second), deadline)
+
+	deadline, err = parseDeadline("2024-01-15T12:30:00Z", now)
+	require.Nil(t, err)
+	require.Equal(t, now.Add(30*time.Minute), deadline)
+
+	_, err = parseDeadline("soon", now)
+	require.NotNil(t, err)
+}
+
+// startServer runs a server that echoes its payload with the request ID
+// added, or fails if the name in the payload is "fail". It returns the port
+// on which the server listens.
+func startServer(t *testing.T) string {
+	handler := lambdabase.LambdaHandlerFunc(func(ctx context.Context, payload []byte) ([]byte, error) {
+		var value struct {
+			Name string `json:"name"`
+		}
+		if err := json.Unmarshal(payload, &value); err != nil {
+			return nil, err
+		}
+
+		if value.Name == "fail" {
+			return nil, lambdabase.NewInvokeError("ValidationError", fmt.Errorf("oops"))
+		}
+
+		lc, _ := lambdacontext.FromContext(ctx)
+		return []byte(fmt.Sprintf(`{"name":%q,"requestId":%q}`, value.Name, lc.AwsRequestID)), nil
+	})
+
+	harness := lambdabasetest.Start(t, lambdabase.NewServer(&wrappedHandler{LambdaHandlerFunc: handler}))
+	_, port, err := net.SplitHostPort(harness.Server().Addr().String())
+	require.Nil(t, err)
+	return port
+}
+
+func invoke(t *testing.T, stdin string, args ...string) (string, string, int) {
+	stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+	code := run(args, strings.NewReader(stdin), stdout, stderr)
+	return stdout.String(), stderr.String(), code
+}
+
+type wrappedHandler struct {
+	lambdabase.LambdaHandlerFunc
+}
+
+func (h *wrappedHandler) Init(ctx context.Context) error {
+	return nil
+}
@@ -0,0 +1,135 @@
+// Command lambdabase-local feeds messages to a lambdabase server listening
+// for RPC commands, as an SQS, Kinesis, or DynamoDB event source mapping
+// would. Messages are read from a directory of JSON files or from the lines
+// of standard input.
+//
+// Run the function with _LAMBDA_SERVER_PORT set, then point this command at
+// the same port:
+//
+//	_LAMBDA_SERVER_PORT=9001 ./my-function &
+//	lambdabase-local -port 9001 -event-source sqs -dir ./messages
+//
+// The command exits once every message has been processed or discarded. It
+// exits with status 1 if the function cannot be reached or the messages
+// cannot be read, and with status 2 if the flags are invalid.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/go-nacelle/lambdabase/lambdabaselocal"
+	"github.com/go-nacelle/lambdabase/rpcclient"
+	"github.com/go-nacelle/log/v2"
+)
+
+const (
+	exitFailure = 1
+	exitUsage   = 2
+)
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stderr))
+}
+
+// run feeds the messages described by the given arguments to the function
+// and returns the exit status of the command.
+func run(args []string, stdin io.Reader, stderr io.Writer) int {
+	flags := flag.NewFlagSet("lambdabase-local", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+
+	var (
+		host                    = flags.String("host", "localhost", "the host of the function")
+		port                    = flags.String("port", os.Getenv("_LAMBDA_SERVER_PORT"), "the port on which the function listens for RPC commands (default $_LAMBDA_SERVER_PORT)")
+		functionARN             = flags.String("function-arn", "arn:aws:lambda:us-east-1:123456789012:function:lambdabase-local", "the ARN of the invoked function")
+		timeout                 = flags.Duration("timeout", time.Minute, "the time between the start of an invocation and its deadline")
+		dir                     = flags.String("dir", "", "a directory of JSON files to read as messages (default standard input)")
+		pollInterval            = flags.Duration("poll-interval", 0, "the interval at which to scan the directory for new files (default read once)")
+		eventSource             = flags.String("event-source", "sqs", "the event source mapping to emulate (sqs, kinesis, or dynamodb)")
+		batchSize               = flags.Int("batch-size", 0, "the maximum number of records in an event (default 10 for sqs, 100 otherwise)")
+		batchWindow             = flags.Duration("batch-window", 0, "the maximum time to gather records for an event")
+		reportBatchItemFailures = flags.Bool("report-batch-item-failures", false, "read partial batch failures from the function response")
+		visibilityTimeout       = flags.Duration("visibility-timeout", 30*time.Second, "the time after which a failed SQS message is delivered again")
+		maxReceiveCount         = flags.Int("max-receive-count", 0, "the number of deliveries after which an SQS message is discarded (default unlimited)")
+		maxRetryAttempts        = flags.Int("max-retry-attempts", -1, "the number of retries after which stream records are discarded (default unlimited)")
+		retryDelay              = flags.Duration("retry-delay", time.Second, "the time after which a failed stream batch is retried")
+		partitionKeyField       = flags.String("partition-key-field", "", "the field of each JSON message holding the partition key of its kinesis record (default one key for every record)")
+	)
+
+	if err := flags.Parse(args); err != nil {
+		return exitUsage
+	}
+
+	fail := func(code int, format string, args ...interface{}) int {
+		fmt.Fprintf(stderr, "lambdabase-local: %s\n", fmt.Sprintf(format, args...))
+		return code
+	}
+
+	if *port == "" {
+		return fail(exitUsage, "no port supplied (set -port or _LAMBDA_SERVER_PORT)")
+	}
+
+	switch lambdabaselocal.EventSource(*eventSource) {
+	case lambdabaselocal.EventSourceSQS, lambdabaselocal.EventSourceKinesis, lambdabaselocal.EventSourceDynamoDB:
+	default:
+		return fail(exitUsage, "unknown event source %q", *eventSource)
+	}
+
+	logger, err := log.InitLogger(&log.Config{
+		LogLevel:         "info",
+		LogEncoding:      "console",
+		LogColorize:      true,
+		LogDisplayFields: true,
+	})
+	if err != nil {
+		return fail(exitFailure, "failed to create logger (%s)", err.Error())
+	}
+
+	client, err := rpcclient.Dial(
+		net.JoinHostPort(*host, *port),
+		rpcclient.WithFunctionARN(*functionARN),
+		rpcclient.WithTimeout(*timeout),
+	)
+	if err != nil {
+		return fail(exitFailure, "failed to connect to function (%s)", err.Error())
+	}
+	defer client.Close()
+
+	source := lambdabaselocal.NewReaderSource(stdin)
+	if *dir != "" {
+		source = lambdabaselocal.NewDirectorySource(*dir, *pollInterval)
+	}
+
+	emulator := lambdabaselocal.NewEmulator(client, source,
+		lambdabaselocal.WithEventSource(lambdabaselocal.EventSource(*eventSource)),
+		lambdabaselocal.WithBatchSize(*batchSize),
+		lambdabaselocal.WithBatchWindow(*batchWindow),
+		lambdabaselocal.WithReportBatchItemFailures(*reportBatchItemFailures),
+		lambdabaselocal.WithVisibilityTimeout(*visibilityTimeout),
+		lambdabaselocal.WithMaxReceiveCount(*maxReceiveCount),
+		lambdabaselocal.WithMaxRetryAttempts(*maxRetryAttempts),
+		lambdabaselocal.WithRetryDelay(*retryDelay),
+		lambdabaselocal.WithPartitionKeyField(*partitionKeyField),
+		lambdabaselocal.WithLogger(logger),
+	)
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	err = emulator.Run(ctx)
+	_ = logger.Sync()
+
+	if err != nil && !errors.Is(err, context.Canceled) {
+		return fail(exitFailure, "%s", err.Error())
+	}
+
+	return 0
+}
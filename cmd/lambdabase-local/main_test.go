@@ -0,0 +1,127 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/go-nacelle/lambdabase"
+	"github.com/go-nacelle/lambdabase/lambdabasetest"
+	"github.com/go-nacelle/nacelle/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocalDirectory(t *testing.T) {
+	handler := &sqsHandler{}
+	port := startServer(t, lambdabase.NewSQSRecordServer(handler))
+
+	dir := t.TempDir()
+	for name, body := range map[string]string{"1.json": `{"name":"foo"}`, "2.json": `{"name":"bar"}`} {
+		require.Nil(t, os.WriteFile(filepath.Join(dir, name), []byte(body), 0644))
+	}
+
+	stderr, code := local(t, "", "-port", port, "-dir", dir)
+	require.Equal(t, 0, code, stderr)
+	require.Equal(t, []string{`{"name":"foo"}`, `{"name":"bar"}`}, handler.bodies)
+	require.Equal(t, []string{"1.json", "2.json"}, handler.ids)
+}
+
+func TestLocalStdin(t *testing.T) {
+	handler := &sqsHandler{}
+	port := startServer(t, lambdabase.NewSQSRecordServer(handler))
+
+	stderr, code := local(t, "foo\nbar\n", "-port", port, "-batch-window", "1s")
+	require.Equal(t, 0, code, stderr)
+	require.Equal(t, []string{"foo", "bar"}, handler.bodies)
+}
+
+func TestLocalEventSource(t *testing.T) {
+	handler := &kinesisHandler{}
+	port := startServer(t, lambdabase.NewKinesisRecordServer(handler))
+
+	stderr, code := local(t, `{"user":"a"}`+"\n"+`{"user":"b"}`+"\n", "-port", port, "-event-source", "kinesis", "-partition-key-field", "user")
+	require.Equal(t, 0, code, stderr)
+	require.Equal(t, []string{"a", "b"}, handler.partitionKeys)
+}
+
+func TestLocalInvalidFlags(t *testing.T) {
+	t.Setenv("_LAMBDA_SERVER_PORT", "")
+
+	_, code := local(t, "", "-batch-size", "lots")
+	require.Equal(t, exitUsage, code)
+
+	stderr, code := local(t, "")
+	require.Equal(t, exitUsage, code)
+	require.Contains(t, stderr, "no port supplied")
+
+	stderr, code = local(t, "", "-port", "9001", "-event-source", "sns")
+	require.Equal(t, exitUsage, code)
+	require.Contains(t, stderr, `unknown event source "sns"`)
+}
+
+func TestLocalUnreachable(t *testing.T) {
+	listener, err := net.Listen("tcp", "localhost:0")
+	require.Nil(t, err)
+	_, port, _ := net.SplitHostPort(listener.Addr().String())
+	listener.Close()
+
+	stderr, code := local(t, "", "-port", port)
+	require.Equal(t, exitFailure, code)
+	require.Contains(t, stderr, "failed to connect to function")
+}
+
+func TestLocalMissingDirectory(t *testing.T) {
+	port := startServer(t, lambdabase.NewSQSRecordServer(&sqsHandler{}))
+
+	stderr, code := local(t, "", "-port", port, "-dir", filepath.Join(t.TempDir(), "missing"))
+	require.Equal(t, exitFailure, code)
+	require.Contains(t, stderr, "failed to read from source")
+}
+
+// startServer runs the given server and returns the port on which it listens.
+func startServer(t *testing.T, server *lambdabase.Server) string {
+	harness := lambdabasetest.Start(t, server)
+	_, port, err := net.SplitHostPort(harness.Server().Addr().String())
+	require.Nil(t, err)
+	return port
+}
+
+func local(t *testing.T, stdin string, args ...string) (string, int) {
+	stderr := &bytes.Buffer{}
+	code := run(args, strings.NewReader(stdin), stderr)
+	return stderr.String(), code
+}
+
+type sqsHandler struct {
+	mutex  sync.Mutex
+	bodies []string
+	ids    []string
+}
+
+func (h *sqsHandler) Handle(ctx context.Context, message events.SQSMessage, logger nacelle.Logger) error {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	h.bodies = append(h.bodies, message.Body)
+	h.ids = append(h.ids, message.MessageId)
+	return nil
+}
+
+type kinesisHandler struct {
+	mutex         sync.Mutex
+	partitionKeys []string
+}
+
+func (h *kinesisHandler) Handle(ctx context.Context, record events.KinesisEventRecord, logger nacelle.Logger) error {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	h.partitionKeys = append(h.partitionKeys, record.Kinesis.PartitionKey)
+	return nil
+}
@@ -0,0 +1,21 @@
+package lambdabase
+
+import "encoding/json"
+
+type (
+	Codec interface {
+		Unmarshal(data []byte, v interface{}) error
+	}
+
+	CodecFunc func(data []byte, v interface{}) error
+
+	JSONCodec struct{}
+)
+
+func (f CodecFunc) Unmarshal(data []byte, v interface{}) error {
+	return f(data, v)
+}
+
+func (JSONCodec) Unmarshal(data []byte, v interface{}) error {
+	return json.Unmarshal(data, v)
+}
@@ -1,5 +1,80 @@
 package lambdabase
 
-type Config struct {
-	LambdaServerPort int `env:"_lambda_server_port" required:"true"`
+import (
+	"fmt"
+	"time"
+)
+
+type (
+	Config struct {
+		LambdaServerPort *int              `env:"_lambda_server_port"`
+		RuntimeAPI       string            `env:"aws_lambda_runtime_api"`
+		LambdaServerHost string            `env:"lambda_server_host"`
+		HealthTokenName  string            `env:"lambda_health_token_name"`
+		PanicPolicy      PanicPolicy       `env:"lambda_panic_policy"`
+		PanicRecovery    *bool             `env:"lambda_panic_recovery"`
+		DeadlineMarginMS *int              `env:"lambda_deadline_margin_ms"`
+		DrainTimeoutMS   *int              `env:"lambda_drain_timeout_ms"`
+		LoggerFieldNames map[string]string `env:"lambda_logger_field_names"`
+	}
+
+	RecordConfig struct {
+		RecordConcurrency       int   `env:"lambda_record_concurrency"`
+		ReportBatchItemFailures *bool `env:"lambda_report_batch_item_failures"`
+	}
+)
+
+func (c *Config) PostLoad() error {
+	if c.LambdaServerPort == nil && c.RuntimeAPI == "" {
+		return fmt.Errorf("one of _lambda_server_port or aws_lambda_runtime_api must be supplied")
+	}
+
+	if c.DeadlineMarginMS != nil && *c.DeadlineMarginMS < 0 {
+		return fmt.Errorf("lambda_deadline_margin_ms must not be negative")
+	}
+
+	if c.DrainTimeoutMS != nil && *c.DrainTimeoutMS < 0 {
+		return fmt.Errorf("lambda_drain_timeout_ms must not be negative")
+	}
+
+	switch c.PanicPolicy {
+	case "", PanicPolicyExit, PanicPolicyContinue:
+	default:
+		return fmt.Errorf("unknown lambda_panic_policy %q", c.PanicPolicy)
+	}
+
+	return nil
+}
+
+// apply overrides the given server options with the values supplied by
+// this config. Values from config take precedence over options, which take
+// precedence over defaults.
+func (c *Config) apply(o *options) {
+	if c.LambdaServerHost != "" {
+		o.listenerHost = c.LambdaServerHost
+	}
+
+	if c.HealthTokenName != "" {
+		o.healthTokenName = c.HealthTokenName
+	}
+
+	if c.PanicPolicy != "" {
+		o.panicPolicy = c.PanicPolicy
+	}
+
+	if c.PanicRecovery != nil {
+		o.recoverPanics = *c.PanicRecovery
+	}
+
+	if c.DeadlineMarginMS != nil {
+		o.deadlineMargin = time.Duration(*c.DeadlineMarginMS) * time.Millisecond
+	}
+
+	if c.DrainTimeoutMS != nil {
+		o.drainTimeout = time.Duration(*c.DrainTimeoutMS) * time.Millisecond
+	}
+
+	if len(c.LoggerFieldNames) > 0 {
+		o.loggerFieldNames = c.LoggerFieldNames
+	}
 }
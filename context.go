@@ -3,6 +3,7 @@ package lambdabase
 import (
 	"context"
 
+	"github.com/aws/aws-lambda-go/events"
 	"github.com/aws/aws-lambda-go/lambdacontext"
 )
 
@@ -13,3 +14,36 @@ func GetRequestID(ctx context.Context) string {
 
 	return "<unknown request id>"
 }
+
+type (
+	apiGatewayProxyRequestKey struct{}
+	httpAPIRequestKey         struct{}
+	albTargetGroupRequestKey  struct{}
+)
+
+func GetAPIGatewayProxyRequest(ctx context.Context) (events.APIGatewayProxyRequest, bool) {
+	event, ok := ctx.Value(apiGatewayProxyRequestKey{}).(events.APIGatewayProxyRequest)
+	return event, ok
+}
+
+func GetHTTPAPIRequest(ctx context.Context) (events.APIGatewayV2HTTPRequest, bool) {
+	event, ok := ctx.Value(httpAPIRequestKey{}).(events.APIGatewayV2HTTPRequest)
+	return event, ok
+}
+
+func GetALBTargetGroupRequest(ctx context.Context) (events.ALBTargetGroupRequest, bool) {
+	event, ok := ctx.Value(albTargetGroupRequestKey{}).(events.ALBTargetGroupRequest)
+	return event, ok
+}
+
+func withAPIGatewayProxyRequest(ctx context.Context, event events.APIGatewayProxyRequest) context.Context {
+	return context.WithValue(ctx, apiGatewayProxyRequestKey{}, event)
+}
+
+func withHTTPAPIRequest(ctx context.Context, event events.APIGatewayV2HTTPRequest) context.Context {
+	return context.WithValue(ctx, httpAPIRequestKey{}, event)
+}
+
+func withALBTargetGroupRequest(ctx context.Context, event events.ALBTargetGroupRequest) context.Context {
+	return context.WithValue(ctx, albTargetGroupRequestKey{}, event)
+}
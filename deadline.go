@@ -0,0 +1,133 @@
+package lambdabase
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-lambda-go/lambda"
+)
+
+type (
+	// deadlineState tracks the point of an invocation after which no new
+	// records are started and the callbacks registered by the handler.
+	deadlineState struct {
+		cutoff    time.Time
+		mutex     sync.Mutex
+		callbacks []func()
+		fired     bool
+		done      bool
+	}
+
+	deadlineStateKeyType struct{}
+)
+
+var deadlineStateKey = deadlineStateKeyType{}
+
+var errDeadlineApproaching = fmt.Errorf("record not started before the invocation deadline")
+
+// OnDeadlineApproaching registers a function to be called once the remaining
+// time of the current invocation drops below the deadline margin of the
+// server. The function is called on its own goroutine, and is called right
+// away if the margin has already been reached. Functions are not called
+// after the invocation completes, or at all when the deadline margin is
+// zero. The return value is false if the given context does not belong to an
+// invocation with a deadline.
+func OnDeadlineApproaching(ctx context.Context, f func()) bool {
+	state := getDeadlineState(ctx)
+	if state == nil {
+		return false
+	}
+
+	state.mutex.Lock()
+	defer state.mutex.Unlock()
+
+	if state.done {
+		return true
+	}
+
+	if state.fired {
+		go f()
+	} else {
+		state.callbacks = append(state.callbacks, f)
+	}
+
+	return true
+}
+
+// DeadlineApproaching returns true if the remaining time of the current
+// invocation is below the deadline margin of the server. Record servers do
+// not start new records once the deadline is approaching.
+func DeadlineApproaching(ctx context.Context) bool {
+	if state := getDeadlineState(ctx); state != nil {
+		return !time.Now().Before(state.cutoff)
+	}
+
+	return false
+}
+
+func getDeadlineState(ctx context.Context) *deadlineState {
+	if state, ok := ctx.Value(deadlineStateKey).(*deadlineState); ok {
+		return state
+	}
+
+	return nil
+}
+
+func (s *deadlineState) fire() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.fired || s.done {
+		return
+	}
+
+	s.fired = true
+	for _, f := range s.callbacks {
+		go f()
+	}
+	s.callbacks = nil
+}
+
+func (s *deadlineState) stop() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.done = true
+	s.callbacks = nil
+}
+
+// trackDeadline wraps the given handler so that the invocation context
+// carries the cutoff after which record servers stop starting new records.
+// Callbacks registered with OnDeadlineApproaching are called at the cutoff.
+// With a zero deadline margin, the cutoff is the deadline itself, at which
+// point the invocation is ending, so no warning is logged and callbacks are
+// not called.
+func (s *Server) trackDeadline(handler lambda.Handler) lambda.Handler {
+	return LambdaHandlerFunc(func(ctx context.Context, payload []byte) ([]byte, error) {
+		deadline, ok := ctx.Deadline()
+		if !ok {
+			return handler.Invoke(ctx, payload)
+		}
+
+		state := &deadlineState{cutoff: deadline.Add(-s.options.deadlineMargin)}
+		defer state.stop()
+
+		if s.options.deadlineMargin == 0 {
+			return handler.Invoke(context.WithValue(ctx, deadlineStateKey, state), payload)
+		}
+
+		timer := time.AfterFunc(time.Until(state.cutoff), func() {
+			logger := s.Logger.WithFields(map[string]interface{}{
+				"requestId": GetRequestID(ctx),
+			})
+
+			logger.Warning("Invocation deadline approaching (%s remaining)", time.Until(deadline).Round(time.Millisecond))
+			state.fire()
+		})
+		defer timer.Stop()
+
+		return handler.Invoke(context.WithValue(ctx, deadlineStateKey, state), payload)
+	})
+}
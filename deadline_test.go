@@ -0,0 +1,213 @@
+package lambdabase
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda/messages"
+	mockassert "github.com/derision-test/go-mockgen/testutil/assert"
+	"github.com/go-nacelle/config/v3"
+	"github.com/go-nacelle/nacelle/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServerDeadlineMargin(t *testing.T) {
+	runtimeAPI := newTestRuntimeAPI()
+	defer runtimeAPI.Close()
+
+	ctx := context.Background()
+	ctx = config.WithConfig(ctx, runtimeAPI.config())
+
+	approaching := make(chan bool, 1)
+	deadlineHandler := LambdaHandlerFunc(func(ctx context.Context, payload []byte) ([]byte, error) {
+		called := make(chan struct{})
+		if !OnDeadlineApproaching(ctx, func() { close(called) }) {
+			return nil, nil
+		}
+
+		select {
+		case <-called:
+		case <-time.After(time.Second * 5):
+		}
+
+		approaching <- DeadlineApproaching(ctx)
+		return testHandler(ctx, payload)
+	})
+
+	// The test runtime API sets a deadline of one minute from the invocation
+	server := makeLambdaServer(deadlineHandler, WithDeadlineMargin(time.Minute-time.Millisecond*100))
+	err := server.Init(ctx)
+	require.Nil(t, err)
+
+	errs := make(chan error, 1)
+	go func() { errs <- server.Run(ctx) }()
+
+	runtimeAPI.invocations <- testRuntimeAPIInvocation{requestID: "bonk", payload: `["foo"]`}
+	result := <-runtimeAPI.results
+	require.Equal(t, "/2018-06-01/runtime/invocation/bonk/response", result.path)
+	require.True(t, <-approaching)
+
+	require.Nil(t, server.Stop(ctx))
+	require.Nil(t, <-errs)
+}
+
+func TestServerDeadlineMarginZero(t *testing.T) {
+	ctx := context.Background()
+	ctx = config.WithConfig(ctx, testConfig)
+
+	called := make(chan struct{}, 1)
+	deadlineHandler := LambdaHandlerFunc(func(ctx context.Context, payload []byte) ([]byte, error) {
+		OnDeadlineApproaching(ctx, func() { called <- struct{}{} })
+		<-ctx.Done()
+
+		// Give a timer armed for the deadline a chance to fire
+		time.Sleep(time.Millisecond * 50)
+		return nil, nil
+	})
+
+	logger := &warningRecordingLogger{Logger: nacelle.NewNilLogger()}
+	server := makeLambdaServer(deadlineHandler)
+	server.Logger = logger
+	err := server.Init(ctx)
+	require.Nil(t, err)
+
+	errs := make(chan error, 1)
+	go func() { errs <- server.Run(ctx) }()
+
+	deadline := time.Now().Add(time.Millisecond * 50)
+	request := &messages.InvokeRequest{
+		Payload:   []byte(`{}`),
+		RequestId: "bonk",
+		Deadline:  messages.InvokeRequest_Timestamp{Seconds: deadline.Unix(), Nanos: int64(deadline.Nanosecond())},
+	}
+
+	client := dialServer(t, server)
+	require.Nil(t, client.Call("Function.Invoke", request, &messages.InvokeResponse{}))
+	require.Len(t, called, 0)
+	require.Equal(t, int32(0), atomic.LoadInt32(&logger.warnings))
+
+	require.Nil(t, server.Stop(ctx))
+	require.Nil(t, <-errs)
+}
+
+func TestServerDeadlineMarginFromConfig(t *testing.T) {
+	ctx := context.Background()
+	ctx = config.WithConfig(ctx, nacelle.NewConfig(nacelle.NewTestEnvSourcer(map[string]string{
+		"_lambda_server_port":       "0",
+		"lambda_deadline_margin_ms": "1500",
+	})))
+
+	server := makeLambdaServer(testHandler, WithDeadlineMargin(time.Second))
+	err := server.Init(ctx)
+	require.Nil(t, err)
+	defer server.Stop(ctx)
+
+	require.Equal(t, time.Millisecond*1500, server.options.deadlineMargin)
+}
+
+func TestOnDeadlineApproaching(t *testing.T) {
+	require.False(t, OnDeadlineApproaching(context.Background(), func() {}))
+
+	state := &deadlineState{cutoff: time.Now().Add(time.Minute)}
+	ctx := context.WithValue(context.Background(), deadlineStateKey, state)
+
+	called := make(chan string, 2)
+	require.True(t, OnDeadlineApproaching(ctx, func() { called <- "before" }))
+	require.False(t, DeadlineApproaching(ctx))
+
+	state.fire()
+	require.Equal(t, "before", <-called)
+
+	require.True(t, OnDeadlineApproaching(ctx, func() { called <- "after" }))
+	require.Equal(t, "after", <-called)
+
+	state.stop()
+	require.True(t, OnDeadlineApproaching(ctx, func() { called <- "stopped" }))
+
+	select {
+	case value := <-called:
+		t.Fatalf("unexpected callback %s", value)
+	case <-time.After(time.Millisecond * 50):
+	}
+}
+
+func TestSQSMessageHandleDeadlineApproaching(t *testing.T) {
+	state := &deadlineState{cutoff: time.Now().Add(time.Minute)}
+	ctx := context.WithValue(context.Background(), deadlineStateKey, state)
+
+	handler := NewMockSqsMessageHandlerInitializer()
+	handler.HandleFunc.SetDefaultHook(func(ctx context.Context, message events.SQSMessage, logger nacelle.Logger) error {
+		state.cutoff = time.Now()
+		return nil
+	})
+	outer := &sqsMessageHandler{handler: handler, reportBatchItemFailures: true}
+
+	response, err := outer.HandleWithResponse(ctx, testSQSMessages, nacelle.NewNilLogger())
+	require.Nil(t, err)
+	require.Equal(t, []events.SQSBatchItemFailure{{ItemIdentifier: "m2"}, {ItemIdentifier: "m3"}}, response.BatchItemFailures)
+	mockassert.CalledN(t, handler.HandleFunc, 1)
+
+	err = outer.Handle(ctx, testSQSMessages, nacelle.NewNilLogger())
+	require.EqualError(t, err, "failed to process SQS message m1 (record not started before the invocation deadline)")
+}
+
+func TestKinesisRecordHandleDeadlineApproaching(t *testing.T) {
+	state := &deadlineState{cutoff: time.Now().Add(time.Minute)}
+	ctx := context.WithValue(context.Background(), deadlineStateKey, state)
+
+	handler := NewMockKinesisRecordHandlerInitializer()
+	handler.HandleFunc.SetDefaultHook(func(ctx context.Context, record events.KinesisEventRecord, logger nacelle.Logger) error {
+		state.cutoff = time.Now()
+		return nil
+	})
+	outer := &kinesisRecordHandler{handler: handler, reportBatchItemFailures: true}
+
+	response, err := outer.HandleWithResponse(ctx, testKinesisRecords, nacelle.NewNilLogger())
+	require.Nil(t, err)
+	require.Equal(t, []events.KinesisBatchItemFailure{{ItemIdentifier: "2"}}, response.BatchItemFailures)
+	mockassert.CalledN(t, handler.HandleFunc, 1)
+}
+
+func TestFirehoseTransformDeadlineApproaching(t *testing.T) {
+	state := &deadlineState{cutoff: time.Now().Add(time.Minute)}
+	ctx := context.WithValue(context.Background(), deadlineStateKey, state)
+
+	calls := 0
+	handler := &testFirehoseTransformHandler{
+		handle: func(record events.KinesisFirehoseEventRecord) (FirehoseTransformResult, error) {
+			calls++
+			state.cutoff = time.Now()
+			return FirehoseOk(record.Data), nil
+		},
+	}
+	outer := &firehoseTransformHandler{
+		handler:           handler,
+		Logger:            nacelle.NewNilLogger(),
+		recordConcurrency: 1,
+		responseLimit:     firehoseResponseLimit,
+	}
+
+	_, err := outer.Invoke(ctx, []byte(testFirehosePayload))
+	require.EqualError(t, err, "failed to transform 3 Firehose records (record not started before the invocation deadline)")
+	require.ErrorIs(t, err, errDeadlineApproaching)
+	require.Equal(t, 1, calls)
+}
+
+//
+// Helpers
+
+type warningRecordingLogger struct {
+	nacelle.Logger
+	warnings int32
+}
+
+func (l *warningRecordingLogger) WithFields(fields nacelle.LogFields) nacelle.Logger {
+	return l
+}
+
+func (l *warningRecordingLogger) Warning(format string, args ...interface{}) {
+	atomic.AddInt32(&l.warnings, 1)
+}
@@ -0,0 +1,187 @@
+package lambdabase
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-lambda-go/lambda"
+)
+
+type (
+	// inFlightInvocations tracks the invocations being handled by a server
+	// so that they can be awaited, and canceled if necessary, while the
+	// server drains.
+	inFlightInvocations struct {
+		mutex       sync.Mutex
+		nextID      int
+		invocations map[int]inFlightInvocation
+		changed     chan struct{}
+		draining    bool
+	}
+
+	inFlightInvocation struct {
+		requestID string
+		cancel    func()
+	}
+)
+
+// drainLogInterval is the interval at which a draining server logs the number
+// of invocations it is waiting for.
+const drainLogInterval = time.Second
+
+func newInFlightInvocations() *inFlightInvocations {
+	return &inFlightInvocations{
+		invocations: map[int]inFlightInvocation{},
+		changed:     make(chan struct{}),
+	}
+}
+
+func (f *inFlightInvocations) add(requestID string, cancel func()) int {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	f.nextID++
+	f.invocations[f.nextID] = inFlightInvocation{requestID: requestID, cancel: cancel}
+	f.notify()
+	return f.nextID
+}
+
+func (f *inFlightInvocations) remove(id int) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	delete(f.invocations, id)
+	f.notify()
+}
+
+// count returns the number of in-flight invocations and a channel that is
+// closed the next time an invocation starts or completes.
+func (f *inFlightInvocations) count() (int, <-chan struct{}) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	return len(f.invocations), f.changed
+}
+
+// progress returns the number of in-flight invocations and whether or not
+// the server is draining.
+func (f *inFlightInvocations) progress() (int, bool) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	return len(f.invocations), f.draining
+}
+
+func (f *inFlightInvocations) startDrain() {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	f.draining = true
+}
+
+// cancel cancels the context of each in-flight invocation and returns their
+// request IDs in sorted order.
+func (f *inFlightInvocations) cancel() []string {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	requestIDs := make([]string, 0, len(f.invocations))
+	for _, invocation := range f.invocations {
+		invocation.cancel()
+		requestIDs = append(requestIDs, invocation.requestID)
+	}
+
+	sort.Strings(requestIDs)
+	return requestIDs
+}
+
+// notify wakes the goroutines waiting on a change to the set of in-flight
+// invocations. The caller must hold the mutex.
+func (f *inFlightInvocations) notify() {
+	close(f.changed)
+	f.changed = make(chan struct{})
+}
+
+// InFlight returns the number of invocations currently being handled by the
+// server.
+func (s *Server) InFlight() int {
+	n, _ := s.inFlight.progress()
+	return n
+}
+
+// Draining returns true once the server has been stopped and has begun
+// waiting for its in-flight invocations to complete.
+func (s *Server) Draining() bool {
+	_, draining := s.inFlight.progress()
+	return draining
+}
+
+// trackInFlight wraps the given handler so that the server can wait for the
+// invocation to complete while draining and cancel its context if the drain
+// timeout elapses first.
+func (s *Server) trackInFlight(handler lambda.Handler) lambda.Handler {
+	return LambdaHandlerFunc(func(ctx context.Context, payload []byte) ([]byte, error) {
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+
+		id := s.inFlight.add(GetRequestID(ctx), cancel)
+		defer s.inFlight.remove(id)
+
+		return handler.Invoke(ctx, payload)
+	})
+}
+
+// drain blocks until the in-flight invocations of a stopped server complete
+// and the given channel is closed. The channel is closed once the server stops
+// receiving invocations, so that an invocation received before the server
+// stopped but not yet tracked is also awaited. The server reports itself
+// unhealthy while draining. If the drain timeout elapses first, the contexts
+// of the remaining invocations are canceled and a *DrainTimeoutError is
+// returned without waiting for them to return.
+func (s *Server) drain(stopped <-chan struct{}) error {
+	s.inFlight.startDrain()
+	s.healthStatus.Update(false)
+
+	n, changed := s.inFlight.count()
+	s.Logger.Info("Draining lambda server (%d invocations in flight)", n)
+
+	var timeout <-chan time.Time
+	if s.options.drainTimeout > 0 {
+		timer := time.NewTimer(s.options.drainTimeout)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+
+	ticker := time.NewTicker(drainLogInterval)
+	defer ticker.Stop()
+
+	for n > 0 || stopped != nil {
+		select {
+		case <-changed:
+		case <-stopped:
+			stopped = nil
+
+		case <-ticker.C:
+			s.Logger.Info("Waiting for %d in-flight invocations to complete", n)
+
+		case <-timeout:
+			requestIDs := s.inFlight.cancel()
+			for _, requestID := range requestIDs {
+				logger := s.Logger.WithFields(map[string]interface{}{
+					"requestId": requestID,
+				})
+
+				logger.Warning("Canceled in-flight invocation after drain timeout of %s", s.options.drainTimeout)
+			}
+
+			return &DrainTimeoutError{Timeout: s.options.drainTimeout, RequestIDs: requestIDs}
+		}
+
+		n, changed = s.inFlight.count()
+	}
+
+	s.Logger.Info("Lambda server drained")
+	return nil
+}
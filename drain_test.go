@@ -0,0 +1,272 @@
+package lambdabase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/rpc"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-lambda-go/lambda/messages"
+	"github.com/go-nacelle/config/v3"
+	"github.com/go-nacelle/nacelle/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServerDrain(t *testing.T) {
+	ctx := context.Background()
+	ctx = config.WithConfig(ctx, testConfig)
+
+	release := make(chan struct{})
+	started := make(chan struct{}, 1)
+	blockingHandler := LambdaHandlerFunc(func(ctx context.Context, payload []byte) ([]byte, error) {
+		started <- struct{}{}
+		<-release
+		return testHandler(ctx, payload)
+	})
+
+	server := makeLambdaServer(blockingHandler)
+	err := server.Init(ctx)
+	require.Nil(t, err)
+
+	errs := make(chan error, 1)
+	go func() { errs <- server.Run(ctx) }()
+
+	client := dialServer(t, server)
+	call := client.Go("Function.Invoke", &messages.InvokeRequest{Payload: []byte(`["foo"]`), RequestId: "bonk"}, &messages.InvokeResponse{}, nil)
+	<-started
+	require.Equal(t, 1, server.InFlight())
+
+	require.Nil(t, server.Stop(ctx))
+	require.False(t, server.Health.Healthy())
+	require.True(t, server.Draining())
+	require.Equal(t, 1, server.InFlight())
+	require.Equal(t, "lambda-init", server.healthToken.String())
+
+	select {
+	case err := <-errs:
+		t.Fatalf("unexpected return from Run (%v)", err)
+	case <-time.After(time.Millisecond * 50):
+	}
+
+	// The in-flight invocation completes before the idle connection is closed
+	close(release)
+	<-call.Done
+	require.Nil(t, call.Error)
+	require.Equal(t, `["foo:bonk"]`, string(call.Reply.(*messages.InvokeResponse).Payload))
+	require.Nil(t, <-errs)
+	require.Equal(t, 0, server.InFlight())
+}
+
+func TestServerDrainTimeout(t *testing.T) {
+	ctx := context.Background()
+	ctx = config.WithConfig(ctx, testConfig)
+
+	canceled := make(chan error, 2)
+	stuckHandler := LambdaHandlerFunc(func(ctx context.Context, payload []byte) ([]byte, error) {
+		<-ctx.Done()
+		canceled <- ctx.Err()
+
+		// Ignore the cancellation for longer than the test waits for Run
+		time.Sleep(time.Second)
+		return nil, nil
+	})
+
+	server := makeLambdaServer(stuckHandler, WithDrainTimeout(time.Millisecond*50))
+	err := server.Init(ctx)
+	require.Nil(t, err)
+
+	errs := make(chan error, 1)
+	go func() { errs <- server.Run(ctx) }()
+
+	// Requests without a deadline would be canceled by their own deadline
+	deadline := time.Now().Add(time.Minute)
+
+	client := dialServer(t, server)
+	for _, requestID := range []string{"quux", "bonk"} {
+		request := &messages.InvokeRequest{
+			Payload:   []byte(`{}`),
+			RequestId: requestID,
+			Deadline:  messages.InvokeRequest_Timestamp{Seconds: deadline.Unix()},
+		}
+
+		client.Go("Function.Invoke", request, &messages.InvokeResponse{}, nil)
+	}
+
+	require.Eventually(t, func() bool { return server.InFlight() == 2 }, time.Second, time.Millisecond)
+	require.Nil(t, server.Stop(ctx))
+
+	select {
+	case err := <-errs:
+		drainErr := &DrainTimeoutError{}
+		require.True(t, errors.As(err, &drainErr))
+		require.Equal(t, []string{"bonk", "quux"}, drainErr.RequestIDs)
+		require.EqualError(t, err, "lambda server did not drain within 50ms (2 invocations in flight)")
+	case <-time.After(time.Millisecond * 500):
+		t.Fatalf("Run did not return after the drain timeout")
+	}
+
+	require.Equal(t, context.Canceled, <-canceled)
+	require.Equal(t, context.Canceled, <-canceled)
+}
+
+func TestServerDrainIdleConnection(t *testing.T) {
+	ctx := context.Background()
+	ctx = config.WithConfig(ctx, testConfig)
+
+	server := makeLambdaServer(testHandler)
+	err := server.Init(ctx)
+	require.Nil(t, err)
+
+	errs := make(chan error, 1)
+	go func() { errs <- server.Run(ctx) }()
+
+	client := dialServer(t, server)
+	response := &messages.InvokeResponse{}
+	require.Nil(t, client.Call("Function.Invoke", &messages.InvokeRequest{Payload: []byte(`["foo"]`), RequestId: "bonk"}, response))
+
+	require.Nil(t, server.Stop(ctx))
+
+	select {
+	case err := <-errs:
+		require.Nil(t, err)
+	case <-time.After(time.Second):
+		t.Fatalf("Run did not return with an idle client connection")
+	}
+}
+
+func TestServerRuntimeAPIDrainTimeout(t *testing.T) {
+	runtimeAPI := newTestRuntimeAPI()
+	defer runtimeAPI.Close()
+
+	ctx := context.Background()
+	ctx = config.WithConfig(ctx, runtimeAPI.config())
+
+	started := make(chan struct{})
+	stuckHandler := LambdaHandlerFunc(func(ctx context.Context, payload []byte) ([]byte, error) {
+		close(started)
+		<-ctx.Done()
+		return nil, fmt.Errorf("canceled")
+	})
+
+	logger := &drainRecordingLogger{Logger: nacelle.NewNilLogger(), requestIDs: make(chan interface{}, 1)}
+	server := NewServer(&wrappedHandler{Handler: stuckHandler}, WithDrainTimeout(time.Millisecond*50))
+	server.Logger = logger
+	server.Services = nacelle.NewServiceContainer()
+	server.Health = nacelle.NewHealth()
+
+	err := server.Init(ctx)
+	require.Nil(t, err)
+
+	errs := make(chan error, 1)
+	go func() { errs <- server.Run(ctx) }()
+
+	runtimeAPI.invocations <- testRuntimeAPIInvocation{requestID: "bonk", payload: `{}`}
+	<-started
+
+	require.Nil(t, server.Stop(ctx))
+	require.Equal(t, "bonk", <-logger.requestIDs)
+
+	err = <-errs
+	drainErr := &DrainTimeoutError{}
+	require.True(t, errors.As(err, &drainErr))
+	require.Equal(t, []string{"bonk"}, drainErr.RequestIDs)
+
+	// The canceled invocation still reports its result
+	result := <-runtimeAPI.results
+	require.Equal(t, "/2018-06-01/runtime/invocation/bonk/error", result.path)
+}
+
+func TestServerRuntimeAPIDrainTimeoutUntrackedInvocation(t *testing.T) {
+	runtimeAPI := newTestRuntimeAPI()
+	defer runtimeAPI.Close()
+
+	ctx := context.Background()
+	ctx = config.WithConfig(ctx, runtimeAPI.config())
+
+	started := make(chan struct{})
+	handler := LambdaHandlerFunc(func(ctx context.Context, payload []byte) ([]byte, error) {
+		close(started)
+		return testHandler(ctx, payload)
+	})
+
+	server := makeLambdaServer(handler, WithDrainTimeout(time.Millisecond*50))
+	err := server.Init(ctx)
+	require.Nil(t, err)
+
+	// Fill the results buffer so that the response of the invocation blocks
+	// after the handler returns and the invocation is no longer tracked
+	runtimeAPI.results <- testRuntimeAPIResult{}
+
+	errs := make(chan error, 1)
+	go func() { errs <- server.Run(ctx) }()
+
+	runtimeAPI.invocations <- testRuntimeAPIInvocation{requestID: "bonk", payload: `["foo"]`}
+	<-started
+	require.Nil(t, server.Stop(ctx))
+
+	select {
+	case err := <-errs:
+		drainErr := &DrainTimeoutError{}
+		require.True(t, errors.As(err, &drainErr))
+		require.Empty(t, drainErr.RequestIDs)
+	case <-time.After(time.Millisecond * 500):
+		t.Fatalf("Run did not return after the drain timeout")
+	}
+
+	<-runtimeAPI.results
+	require.Equal(t, "/2018-06-01/runtime/invocation/bonk/response", (<-runtimeAPI.results).path)
+}
+
+func TestServerDrainTimeoutFromConfig(t *testing.T) {
+	ctx := context.Background()
+	ctx = config.WithConfig(ctx, nacelle.NewConfig(nacelle.NewTestEnvSourcer(map[string]string{
+		"_lambda_server_port":     "0",
+		"lambda_drain_timeout_ms": "2500",
+	})))
+
+	server := makeLambdaServer(testHandler, WithDrainTimeout(time.Second))
+	err := server.Init(ctx)
+	require.Nil(t, err)
+	defer server.Stop(ctx)
+
+	require.Equal(t, time.Millisecond*2500, server.options.drainTimeout)
+
+	ctx = config.WithConfig(context.Background(), nacelle.NewConfig(nacelle.NewTestEnvSourcer(map[string]string{
+		"_lambda_server_port":     "0",
+		"lambda_drain_timeout_ms": "-1",
+	})))
+
+	err = makeLambdaServer(testHandler).Init(ctx)
+	require.NotNil(t, err)
+	require.Contains(t, err.Error(), "lambda_drain_timeout_ms must not be negative")
+}
+
+func dialServer(t *testing.T, server *Server) *rpc.Client {
+	conn, err := net.Dial("tcp", fmt.Sprintf("localhost:%d", getDynamicPort(server.listener)))
+	require.Nil(t, err)
+
+	client := rpc.NewClient(conn)
+	t.Cleanup(func() { client.Close() })
+	return client
+}
+
+// drainRecordingLogger records the request IDs of invocations canceled by
+// the drain timeout.
+type drainRecordingLogger struct {
+	nacelle.Logger
+	requestIDs chan interface{}
+}
+
+func (l *drainRecordingLogger) WithFields(fields nacelle.LogFields) nacelle.Logger {
+	if requestID, ok := fields["requestId"]; ok {
+		select {
+		case l.requestIDs <- requestID:
+		default:
+		}
+	}
+
+	return l
+}
@@ -0,0 +1,409 @@
+package lambdabase
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/aws/aws-lambda-go/events"
+)
+
+var (
+	jsonNumberType      = reflect.TypeOf(json.Number(""))
+	byteSliceType       = reflect.TypeOf([]byte(nil))
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// UnmarshalDynamoDBImage decodes the given DynamoDB stream image into the
+// value pointed to by v. Struct fields are matched to attribute names by the
+// `dynamodbav` struct tag, or by the field name if the tag is absent. A tag
+// of "-" skips the field.
+//
+// Numbers decode into any integer or float type, into string or json.Number
+// values without loss of precision, and into types that implement
+// encoding.TextUnmarshaler such as *big.Int and *big.Float. String, number,
+// and binary sets decode into slices or into maps keyed by the set members.
+// Attributes decoded into an empty interface become string, json.Number,
+// []byte, bool, nil, []interface{}, or map[string]interface{} values.
+func UnmarshalDynamoDBImage(image map[string]events.DynamoDBAttributeValue, v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return fmt.Errorf("cannot unmarshal DynamoDB image into non-pointer %T", v)
+	}
+
+	return decodeAttributeMap(image, rv.Elem())
+}
+
+func decodeAttribute(av events.DynamoDBAttributeValue, v reflect.Value) error {
+	if av.DataType() == events.DataTypeNull {
+		v.Set(reflect.Zero(v.Type()))
+		return nil
+	}
+
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+
+		return decodeAttribute(av, v.Elem())
+	}
+
+	if v.Kind() == reflect.Interface && v.NumMethod() == 0 {
+		value, err := attributeInterface(av)
+		if err != nil {
+			return err
+		}
+
+		if value == nil {
+			v.Set(reflect.Zero(v.Type()))
+		} else {
+			v.Set(reflect.ValueOf(value))
+		}
+
+		return nil
+	}
+
+	if v.Type() != byteSliceType && v.CanAddr() && v.Addr().Type().Implements(textUnmarshalerType) {
+		switch av.DataType() {
+		case events.DataTypeString:
+			return v.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(av.String()))
+		case events.DataTypeNumber:
+			return v.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(av.Number()))
+		}
+	}
+
+	switch av.DataType() {
+	case events.DataTypeString:
+		if v.Kind() != reflect.String {
+			return typeMismatchError(av, v)
+		}
+
+		v.SetString(av.String())
+		return nil
+
+	case events.DataTypeNumber:
+		return decodeNumber(av.Number(), v)
+
+	case events.DataTypeBinary:
+		if v.Type() != byteSliceType {
+			return typeMismatchError(av, v)
+		}
+
+		v.SetBytes(av.Binary())
+		return nil
+
+	case events.DataTypeBoolean:
+		if v.Kind() != reflect.Bool {
+			return typeMismatchError(av, v)
+		}
+
+		v.SetBool(av.Boolean())
+		return nil
+
+	case events.DataTypeList:
+		return decodeList(av, av.List(), v)
+
+	case events.DataTypeMap:
+		return decodeAttributeMap(av.Map(), v)
+
+	case events.DataTypeStringSet:
+		return decodeSet(av, stringSetMembers(av.StringSet(), events.NewStringAttribute), v)
+
+	case events.DataTypeNumberSet:
+		return decodeSet(av, stringSetMembers(av.NumberSet(), events.NewNumberAttribute), v)
+
+	case events.DataTypeBinarySet:
+		members := make([]events.DynamoDBAttributeValue, 0, len(av.BinarySet()))
+		for _, member := range av.BinarySet() {
+			members = append(members, events.NewBinaryAttribute(member))
+		}
+
+		return decodeSet(av, members, v)
+	}
+
+	return fmt.Errorf("unsupported DynamoDB attribute type %d", av.DataType())
+}
+
+func decodeNumber(number string, v reflect.Value) error {
+	if v.Type() == jsonNumberType {
+		v.SetString(number)
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(number)
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(number, 10, v.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("cannot unmarshal number %s into %s (%w)", number, v.Type(), err)
+		}
+
+		v.SetInt(n)
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(number, 10, v.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("cannot unmarshal number %s into %s (%w)", number, v.Type(), err)
+		}
+
+		v.SetUint(n)
+
+	case reflect.Float32, reflect.Float64:
+		n, err := strconv.ParseFloat(number, v.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("cannot unmarshal number %s into %s (%w)", number, v.Type(), err)
+		}
+
+		v.SetFloat(n)
+
+	default:
+		return fmt.Errorf("cannot unmarshal DynamoDB number into %s", v.Type())
+	}
+
+	return nil
+}
+
+func decodeList(av events.DynamoDBAttributeValue, elements []events.DynamoDBAttributeValue, v reflect.Value) error {
+	switch v.Kind() {
+	case reflect.Slice:
+		slice := reflect.MakeSlice(v.Type(), len(elements), len(elements))
+		for i, element := range elements {
+			if err := decodeAttribute(element, slice.Index(i)); err != nil {
+				return err
+			}
+		}
+
+		v.Set(slice)
+		return nil
+
+	case reflect.Array:
+		if len(elements) > v.Len() {
+			return fmt.Errorf("cannot unmarshal %d DynamoDB list elements into %s", len(elements), v.Type())
+		}
+
+		for i, element := range elements {
+			if err := decodeAttribute(element, v.Index(i)); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	return typeMismatchError(av, v)
+}
+
+// decodeSet decodes the members of a set into a slice or array, or into a
+// map whose keys are the members of the set. Map values are set to true for
+// maps of bools and to the zero value otherwise (e.g. map[string]struct{}).
+func decodeSet(av events.DynamoDBAttributeValue, members []events.DynamoDBAttributeValue, v reflect.Value) error {
+	if v.Kind() != reflect.Map {
+		return decodeList(av, members, v)
+	}
+
+	m := reflect.MakeMapWithSize(v.Type(), len(members))
+	for _, member := range members {
+		key := reflect.New(v.Type().Key()).Elem()
+		if err := decodeAttribute(member, key); err != nil {
+			return err
+		}
+
+		value := reflect.New(v.Type().Elem()).Elem()
+		if value.Kind() == reflect.Bool {
+			value.SetBool(true)
+		}
+
+		m.SetMapIndex(key, value)
+	}
+
+	v.Set(m)
+	return nil
+}
+
+func decodeAttributeMap(attributes map[string]events.DynamoDBAttributeValue, v reflect.Value) error {
+	switch v.Kind() {
+	case reflect.Map:
+		if v.Type().Key().Kind() != reflect.String {
+			return fmt.Errorf("cannot unmarshal DynamoDB map into %s", v.Type())
+		}
+
+		m := reflect.MakeMapWithSize(v.Type(), len(attributes))
+		for name, attribute := range attributes {
+			value := reflect.New(v.Type().Elem()).Elem()
+			if err := decodeAttribute(attribute, value); err != nil {
+				return fmt.Errorf("%s: %w", name, err)
+			}
+
+			m.SetMapIndex(reflect.ValueOf(name).Convert(v.Type().Key()), value)
+		}
+
+		v.Set(m)
+		return nil
+
+	case reflect.Struct:
+		for _, field := range attributeFields(v.Type()) {
+			attribute, ok := attributes[field.name]
+			if !ok {
+				continue
+			}
+
+			if err := decodeAttribute(attribute, v.FieldByIndex(field.index)); err != nil {
+				return fmt.Errorf("%s: %w", field.name, err)
+			}
+		}
+
+		return nil
+	}
+
+	return fmt.Errorf("cannot unmarshal DynamoDB map into %s", v.Type())
+}
+
+type attributeField struct {
+	name  string
+	index []int
+}
+
+// attributeFields returns the exported fields of the given struct type along
+// with their attribute names. Untagged embedded structs are flattened into
+// the fields of the enclosing struct.
+func attributeFields(t reflect.Type) []attributeField {
+	fields := []attributeField{}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := strings.Split(field.Tag.Get("dynamodbav"), ",")[0]
+		if tag == "-" {
+			continue
+		}
+
+		if field.Anonymous && tag == "" && field.Type.Kind() == reflect.Struct {
+			for _, embedded := range attributeFields(field.Type) {
+				fields = append(fields, attributeField{
+					name:  embedded.name,
+					index: append([]int{i}, embedded.index...),
+				})
+			}
+
+			continue
+		}
+
+		if field.PkgPath != "" {
+			continue
+		}
+
+		name := tag
+		if name == "" {
+			name = field.Name
+		}
+
+		fields = append(fields, attributeField{name: name, index: []int{i}})
+	}
+
+	return fields
+}
+
+func attributeInterface(av events.DynamoDBAttributeValue) (interface{}, error) {
+	switch av.DataType() {
+	case events.DataTypeNull:
+		return nil, nil
+	case events.DataTypeString:
+		return av.String(), nil
+	case events.DataTypeNumber:
+		return json.Number(av.Number()), nil
+	case events.DataTypeBinary:
+		return av.Binary(), nil
+	case events.DataTypeBoolean:
+		return av.Boolean(), nil
+	}
+
+	var value interface{}
+	switch av.DataType() {
+	case events.DataTypeList, events.DataTypeBinarySet:
+		value = &[]interface{}{}
+	case events.DataTypeMap:
+		value = &map[string]interface{}{}
+	case events.DataTypeStringSet:
+		value = &[]string{}
+	case events.DataTypeNumberSet:
+		value = &[]json.Number{}
+	default:
+		return nil, fmt.Errorf("unsupported DynamoDB attribute type %d", av.DataType())
+	}
+
+	if err := decodeAttribute(av, reflect.ValueOf(value).Elem()); err != nil {
+		return nil, err
+	}
+
+	return reflect.ValueOf(value).Elem().Interface(), nil
+}
+
+func stringSetMembers(members []string, makeAttribute func(string) events.DynamoDBAttributeValue) []events.DynamoDBAttributeValue {
+	attributes := make([]events.DynamoDBAttributeValue, 0, len(members))
+	for _, member := range members {
+		attributes = append(attributes, makeAttribute(member))
+	}
+
+	return attributes
+}
+
+func typeMismatchError(av events.DynamoDBAttributeValue, v reflect.Value) error {
+	return fmt.Errorf("cannot unmarshal DynamoDB attribute of type %d into %s", av.DataType(), v.Type())
+}
+
+// ChangedDynamoDBAttributes returns the attribute names of the fields that
+// differ between the given old and new images, in field order. Attribute
+// names are determined by the same struct tags as UnmarshalDynamoDBImage.
+// Images decoded into maps are compared by key, and the names are sorted. If
+// either image is nil, every attribute set in the other image is reported as
+// changed.
+func ChangedDynamoDBAttributes[T any](old, new *T) []string {
+	var zero T
+	if old == nil {
+		old = &zero
+	}
+	if new == nil {
+		new = &zero
+	}
+
+	ov := reflect.ValueOf(old).Elem()
+	nv := reflect.ValueOf(new).Elem()
+
+	changed := []string{}
+	if ov.Kind() == reflect.Map && ov.Type().Key().Kind() == reflect.String {
+		names := map[string]struct{}{}
+		for _, value := range []reflect.Value{ov, nv} {
+			iter := value.MapRange()
+			for iter.Next() {
+				names[iter.Key().String()] = struct{}{}
+			}
+		}
+
+		for name := range names {
+			key := reflect.ValueOf(name).Convert(ov.Type().Key())
+			ok, nk := ov.MapIndex(key), nv.MapIndex(key)
+			if ok.IsValid() != nk.IsValid() || (ok.IsValid() && !reflect.DeepEqual(ok.Interface(), nk.Interface())) {
+				changed = append(changed, name)
+			}
+		}
+
+		sort.Strings(changed)
+		return changed
+	}
+
+	if ov.Kind() != reflect.Struct {
+		return changed
+	}
+
+	for _, field := range attributeFields(ov.Type()) {
+		if !reflect.DeepEqual(ov.FieldByIndex(field.index).Interface(), nv.FieldByIndex(field.index).Interface()) {
+			changed = append(changed, field.name)
+		}
+	}
+
+	return changed
+}
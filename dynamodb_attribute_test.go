@@ -0,0 +1,148 @@
+package lambdabase
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/stretchr/testify/require"
+)
+
+type testDynamoDBBase struct {
+	ID string `dynamodbav:"id"`
+}
+
+type testDynamoDBItem struct {
+	testDynamoDBBase
+	Name     string              `dynamodbav:"name"`
+	Count    int                 `dynamodbav:"count"`
+	Ratio    float64             `dynamodbav:"ratio"`
+	Balance  *big.Int            `dynamodbav:"balance"`
+	Precise  json.Number         `dynamodbav:"precise"`
+	Enabled  bool                `dynamodbav:"enabled"`
+	Payload  []byte              `dynamodbav:"payload"`
+	Tags     []string            `dynamodbav:"tags"`
+	Scores   map[int]struct{}    `dynamodbav:"scores"`
+	Blobs    [][]byte            `dynamodbav:"blobs"`
+	Items    []testDynamoDBChild `dynamodbav:"items"`
+	Extra    map[string]string   `dynamodbav:"extra"`
+	Any      interface{}         `dynamodbav:"any"`
+	Nickname *string             `dynamodbav:"nickname"`
+	Ignored  string              `dynamodbav:"-"`
+	Untagged string
+}
+
+type testDynamoDBChild struct {
+	SKU      string `dynamodbav:"sku"`
+	Quantity uint8  `dynamodbav:"quantity"`
+}
+
+var testDynamoDBImage = map[string]events.DynamoDBAttributeValue{
+	"id":      events.NewStringAttribute("i1"),
+	"name":    events.NewStringAttribute("widget"),
+	"count":   events.NewNumberAttribute("42"),
+	"ratio":   events.NewNumberAttribute("0.25"),
+	"balance": events.NewNumberAttribute("123456789012345678901234567890"),
+	"precise": events.NewNumberAttribute("3.14159265358979323846264338327950288"),
+	"enabled": events.NewBooleanAttribute(true),
+	"payload": events.NewBinaryAttribute([]byte("data")),
+	"tags":    events.NewStringSetAttribute([]string{"a", "b"}),
+	"scores":  events.NewNumberSetAttribute([]string{"1", "2"}),
+	"blobs":   events.NewBinarySetAttribute([][]byte{[]byte("x"), []byte("y")}),
+	"items": events.NewListAttribute([]events.DynamoDBAttributeValue{
+		events.NewMapAttribute(map[string]events.DynamoDBAttributeValue{
+			"sku":      events.NewStringAttribute("s1"),
+			"quantity": events.NewNumberAttribute("3"),
+		}),
+	}),
+	"extra": events.NewMapAttribute(map[string]events.DynamoDBAttributeValue{
+		"color": events.NewStringAttribute("red"),
+	}),
+	"any": events.NewListAttribute([]events.DynamoDBAttributeValue{
+		events.NewNumberAttribute("1"),
+		events.NewStringAttribute("two"),
+		events.NewNullAttribute(),
+	}),
+	"nickname": events.NewNullAttribute(),
+	"Ignored":  events.NewStringAttribute("nope"),
+	"Untagged": events.NewStringAttribute("yes"),
+	"unknown":  events.NewStringAttribute("skipped"),
+}
+
+func TestUnmarshalDynamoDBImage(t *testing.T) {
+	item := testDynamoDBItem{}
+	err := UnmarshalDynamoDBImage(testDynamoDBImage, &item)
+	require.Nil(t, err)
+
+	balance, _ := new(big.Int).SetString("123456789012345678901234567890", 10)
+
+	require.Equal(t, testDynamoDBItem{
+		testDynamoDBBase: testDynamoDBBase{ID: "i1"},
+		Name:             "widget",
+		Count:            42,
+		Ratio:            0.25,
+		Balance:          balance,
+		Precise:          json.Number("3.14159265358979323846264338327950288"),
+		Enabled:          true,
+		Payload:          []byte("data"),
+		Tags:             []string{"a", "b"},
+		Scores:           map[int]struct{}{1: {}, 2: {}},
+		Blobs:            [][]byte{[]byte("x"), []byte("y")},
+		Items:            []testDynamoDBChild{{SKU: "s1", Quantity: 3}},
+		Extra:            map[string]string{"color": "red"},
+		Any:              []interface{}{json.Number("1"), "two", nil},
+		Untagged:         "yes",
+	}, item)
+}
+
+func TestUnmarshalDynamoDBImageMap(t *testing.T) {
+	item := map[string]interface{}{}
+	err := UnmarshalDynamoDBImage(map[string]events.DynamoDBAttributeValue{
+		"id":   events.NewStringAttribute("i1"),
+		"tags": events.NewStringSetAttribute([]string{"a"}),
+		"nested": events.NewMapAttribute(map[string]events.DynamoDBAttributeValue{
+			"n": events.NewNumberAttribute("9007199254740993"),
+		}),
+	}, &item)
+	require.Nil(t, err)
+	require.Equal(t, map[string]interface{}{
+		"id":     "i1",
+		"tags":   []string{"a"},
+		"nested": map[string]interface{}{"n": json.Number("9007199254740993")},
+	}, item)
+}
+
+func TestUnmarshalDynamoDBImageErrors(t *testing.T) {
+	item := testDynamoDBItem{}
+
+	err := UnmarshalDynamoDBImage(map[string]events.DynamoDBAttributeValue{"count": events.NewStringAttribute("x")}, &item)
+	require.EqualError(t, err, "count: cannot unmarshal DynamoDB attribute of type 8 into int")
+
+	err = UnmarshalDynamoDBImage(map[string]events.DynamoDBAttributeValue{"count": events.NewNumberAttribute("1.5")}, &item)
+	require.NotNil(t, err)
+	require.Contains(t, err.Error(), "cannot unmarshal number 1.5 into int")
+
+	err = UnmarshalDynamoDBImage(map[string]events.DynamoDBAttributeValue{
+		"items": events.NewListAttribute([]events.DynamoDBAttributeValue{
+			events.NewMapAttribute(map[string]events.DynamoDBAttributeValue{"quantity": events.NewNumberAttribute("256")}),
+		}),
+	}, &item)
+	require.NotNil(t, err)
+	require.Contains(t, err.Error(), "items: quantity: cannot unmarshal number 256 into uint8")
+
+	err = UnmarshalDynamoDBImage(testDynamoDBImage, item)
+	require.NotNil(t, err)
+}
+
+func TestChangedDynamoDBAttributes(t *testing.T) {
+	old := &testDynamoDBItem{Name: "widget", Count: 1, Tags: []string{"a"}, Ignored: "x"}
+	new := &testDynamoDBItem{Name: "widget", Count: 2, Tags: []string{"a", "b"}, Ignored: "y"}
+	require.Equal(t, []string{"count", "tags"}, ChangedDynamoDBAttributes(old, new))
+	require.Equal(t, []string{}, ChangedDynamoDBAttributes(old, old))
+	require.Equal(t, []string{"id", "name"}, ChangedDynamoDBAttributes(nil, &testDynamoDBItem{testDynamoDBBase: testDynamoDBBase{ID: "i1"}, Name: "widget"}))
+
+	oldMap := &map[string]interface{}{"a": "1", "b": "2"}
+	newMap := &map[string]interface{}{"b": "3", "c": "4"}
+	require.Equal(t, []string{"a", "b", "c"}, ChangedDynamoDBAttributes(oldMap, newMap))
+}
@@ -14,6 +14,10 @@ type (
 		Handle(ctx context.Context, batch []events.DynamoDBEventRecord, logger nacelle.Logger) error
 	}
 
+	DynamoDBEventResponseHandler interface {
+		HandleWithResponse(ctx context.Context, batch []events.DynamoDBEventRecord, logger nacelle.Logger) (*events.DynamoDBEventResponse, error)
+	}
+
 	dynamoDBEventHandlerInitializer interface {
 		nacelle.Initializer
 		DynamoDBEventHandler
@@ -26,10 +30,10 @@ type (
 	}
 )
 
-func NewDynamoDBEventServer(handler DynamoDBEventHandler) *Server {
+func NewDynamoDBEventServer(handler DynamoDBEventHandler, configs ...ConfigFunc) *Server {
 	return NewServer(&dynamoDBEventHandler{
 		handler: handler,
-	})
+	}, configs...)
 }
 
 func (h *dynamoDBEventHandler) Init(ctx context.Context) error {
@@ -39,7 +43,7 @@ func (h *dynamoDBEventHandler) Init(ctx context.Context) error {
 func (h *dynamoDBEventHandler) Invoke(ctx context.Context, payload []byte) ([]byte, error) {
 	event := &events.DynamoDBEvent{}
 	if err := json.Unmarshal(payload, &event); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal event (%s)", err.Error())
+		return nil, fmt.Errorf("failed to unmarshal event (%w)", err)
 	}
 
 	logger := h.Logger.WithFields(map[string]interface{}{
@@ -48,10 +52,35 @@ func (h *dynamoDBEventHandler) Invoke(ctx context.Context, payload []byte) ([]by
 
 	logger.Debug("Received %d DynamoDB records", len(event.Records))
 
+	if responseHandler, ok := h.handler.(DynamoDBEventResponseHandler); ok {
+		return h.invokeWithResponse(ctx, responseHandler, event.Records, logger)
+	}
+
 	if err := h.handler.Handle(ctx, event.Records, logger); err != nil {
-		return nil, fmt.Errorf("failed to process DynamoDB event (%s)", err.Error())
+		return nil, fmt.Errorf("failed to process DynamoDB event (%w)", err)
 	}
 
 	logger.Debug("DynamoDB event handled successfully")
 	return nil, nil
 }
+
+func (h *dynamoDBEventHandler) invokeWithResponse(ctx context.Context, handler DynamoDBEventResponseHandler, batch []events.DynamoDBEventRecord, logger nacelle.Logger) ([]byte, error) {
+	response, err := handler.HandleWithResponse(ctx, batch, logger)
+	if err != nil {
+		return nil, fmt.Errorf("failed to process DynamoDB event (%w)", err)
+	}
+
+	if response == nil {
+		logger.Debug("DynamoDB event handled successfully")
+		return nil, nil
+	}
+
+	logger.Debug("DynamoDB event handled with %d failed records", len(response.BatchItemFailures))
+
+	serialized, err := json.Marshal(response)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal DynamoDB event response (%w)", err)
+	}
+
+	return serialized, nil
+}
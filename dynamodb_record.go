@@ -2,7 +2,7 @@ package lambdabase
 
 import (
 	"context"
-	"fmt"
+	"encoding/json"
 
 	"github.com/aws/aws-lambda-go/events"
 	"github.com/go-nacelle/nacelle/v2"
@@ -19,34 +19,134 @@ type (
 	}
 
 	dynamoDBRecordHandler struct {
-		Services *nacelle.ServiceContainer `service:"services"`
-		handler  DynamoDBRecordHandler
+		Services                *nacelle.ServiceContainer `service:"services"`
+		handler                 DynamoDBRecordHandler
+		reportBatchItemFailures bool
+		recordConcurrency       int
+		partitionKey            string
+		recordMiddleware        []RecordMiddleware
 	}
 )
 
-func NewDynamoDBRecordServer(handler DynamoDBRecordHandler) *Server {
+// dynamoDBCompositeKey is the key shared by records whose partition key
+// cannot be told apart from the sort key. It cannot collide with a serialized
+// primary key.
+const dynamoDBCompositeKey = "composite"
+
+func NewDynamoDBRecordServer(handler DynamoDBRecordHandler, configs ...ConfigFunc) *Server {
+	options := getOptions(configs)
+
 	return NewDynamoDBEventServer(&dynamoDBRecordHandler{
-		handler: handler,
-	})
+		handler:                 handler,
+		reportBatchItemFailures: options.reportBatchItemFailures,
+		recordConcurrency:       options.recordConcurrency,
+		partitionKey:            options.dynamoDBPartitionKey,
+		recordMiddleware:        options.recordMiddleware,
+	}, configs...)
 }
 
 func (s *dynamoDBRecordHandler) Init(ctx context.Context) error {
+	recordConcurrency, err := loadRecordConcurrency(ctx, s.recordConcurrency)
+	if err != nil {
+		return err
+	}
+	s.recordConcurrency = recordConcurrency
+
+	reportBatchItemFailures, err := loadReportBatchItemFailures(ctx, s.reportBatchItemFailures)
+	if err != nil {
+		return err
+	}
+	s.reportBatchItemFailures = reportBatchItemFailures
+
 	return doInit(ctx, s.Services, s.handler)
 }
 
 func (h *dynamoDBRecordHandler) Handle(ctx context.Context, records []events.DynamoDBEventRecord, logger nacelle.Logger) error {
+	errs := h.handleRecords(ctx, records, logger)
+
+	if i := firstFailure(errs); i >= 0 {
+		return newRecordError("DynamoDB", "record", records[i].EventID, errs[i])
+	}
+
+	logger.Debug("DynamoDB record handled successfully")
+	return nil
+}
+
+func (h *dynamoDBRecordHandler) HandleWithResponse(ctx context.Context, records []events.DynamoDBEventRecord, logger nacelle.Logger) (*events.DynamoDBEventResponse, error) {
+	if !h.reportBatchItemFailures {
+		return nil, h.Handle(ctx, records, logger)
+	}
+
+	response := &events.DynamoDBEventResponse{
+		BatchItemFailures: []events.DynamoDBBatchItemFailure{},
+	}
+
+	errs := h.handleRecords(ctx, records, logger)
+
+	for i, err := range errs {
+		if err != nil && err != errRecordSkipped {
+			recordLogger := logger.WithFields(map[string]interface{}{
+				"eventId": records[i].EventID,
+			})
+
+			recordLogger.Error("Failed to process DynamoDB record (%s)", err.Error())
+		}
+	}
+
+	// Lambda retries the batch starting from the reported sequence number,
+	// so only the earliest record that failed or was skipped is reported.
+	for i, err := range errs {
+		if err != nil {
+			logger.Warning("Checkpointing DynamoDB batch at sequence number %s", records[i].Change.SequenceNumber)
+
+			response.BatchItemFailures = append(response.BatchItemFailures, events.DynamoDBBatchItemFailure{
+				ItemIdentifier: records[i].Change.SequenceNumber,
+			})
+
+			break
+		}
+	}
+
+	return response, nil
+}
+
+func (h *dynamoDBRecordHandler) handleRecords(ctx context.Context, records []events.DynamoDBEventRecord, logger nacelle.Logger) []error {
+	keys := make([]string, 0, len(records))
 	for _, record := range records {
+		keys = append(keys, dynamoDBRecordKey(record, h.partitionKey))
+	}
+
+	return processBatch(keys, h.recordConcurrency, true, func(i int) error {
 		recordLogger := logger.WithFields(map[string]interface{}{
-			"eventId": record.EventID,
+			"eventId": records[i].EventID,
 		})
 
-		logger.Debug("Handling record")
+		recordLogger.Debug("Handling record")
+		return invokeRecordHandler(ctx, h.recordMiddleware, records[i], recordLogger, h.handler.Handle)
+	})
+}
 
-		if err := h.handler.Handle(ctx, record, recordLogger); err != nil {
-			return fmt.Errorf("failed to process DynamoDB record %s (%s)", record.EventID, err.Error())
-		}
+// dynamoDBRecordKey serializes the partition key of the modified item so that
+// changes to items sharing a partition key are handled in order. The only
+// attribute of a simple primary key is its partition key. Records of an item
+// with a composite primary key whose partition key attribute is not named
+// share a single key.
+func dynamoDBRecordKey(record events.DynamoDBEventRecord, partitionKey string) string {
+	keys := record.Change.Keys
+	if value, ok := keys[partitionKey]; ok {
+		keys = map[string]events.DynamoDBAttributeValue{partitionKey: value}
+	} else if len(keys) > 1 {
+		return dynamoDBCompositeKey
 	}
 
-	logger.Debug("DynamoDB record handled successfully")
-	return nil
+	if len(keys) == 0 {
+		return ""
+	}
+
+	serialized, err := json.Marshal(keys)
+	if err != nil {
+		return ""
+	}
+
+	return string(serialized)
 }
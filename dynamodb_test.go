@@ -3,7 +3,9 @@ package lambdabase
 import (
 	"context"
 	"fmt"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/aws/aws-lambda-go/events"
 	mockassert "github.com/derision-test/go-mockgen/testutil/assert"
@@ -18,6 +20,7 @@ var testDynamoDBPayload = `{
 			"eventID": "ev1",
 			"eventName": "INSERT",
 			"dynamodb": {
+				"SequenceNumber": "1",
 				"NewImage": {
 					"PK": {"S": "foo"},
 					"SK": {"S": "bonk"}
@@ -28,6 +31,7 @@ var testDynamoDBPayload = `{
 			"eventID": "ev2",
 			"eventName": "INSERT",
 			"dynamodb": {
+				"SequenceNumber": "2",
 				"NewImage": {
 					"PK": {"S": "bar"},
 					"SK": {"S": "quux"}
@@ -38,6 +42,7 @@ var testDynamoDBPayload = `{
 			"eventID": "ev3",
 			"eventName": "INSERT",
 			"dynamodb": {
+				"SequenceNumber": "3",
 				"NewImage": {
 					"PK": {"S": "baz"},
 					"SK": {"S": "honk"}
@@ -52,6 +57,7 @@ var testDynamoDBRecords = []events.DynamoDBEventRecord{
 		EventID:   "ev1",
 		EventName: "INSERT",
 		Change: events.DynamoDBStreamRecord{
+			SequenceNumber: "1",
 			NewImage: map[string]events.DynamoDBAttributeValue{
 				"PK": events.NewStringAttribute("foo"),
 				"SK": events.NewStringAttribute("bonk"),
@@ -62,6 +68,7 @@ var testDynamoDBRecords = []events.DynamoDBEventRecord{
 		EventID:   "ev2",
 		EventName: "INSERT",
 		Change: events.DynamoDBStreamRecord{
+			SequenceNumber: "2",
 			NewImage: map[string]events.DynamoDBAttributeValue{
 				"PK": events.NewStringAttribute("bar"),
 				"SK": events.NewStringAttribute("quux"),
@@ -72,6 +79,7 @@ var testDynamoDBRecords = []events.DynamoDBEventRecord{
 		EventID:   "ev3",
 		EventName: "INSERT",
 		Change: events.DynamoDBStreamRecord{
+			SequenceNumber: "3",
 			NewImage: map[string]events.DynamoDBAttributeValue{
 				"PK": events.NewStringAttribute("baz"),
 				"SK": events.NewStringAttribute("honk"),
@@ -210,6 +218,21 @@ func TestDynamoDBEventInvokeError(t *testing.T) {
 	require.EqualError(t, err, "failed to process DynamoDB event (oops)")
 }
 
+func TestDynamoDBEventInvokeWithResponse(t *testing.T) {
+	handler := NewMockDynamoDBRecordHandlerInitializer()
+	handler.HandleFunc.PushReturn(nil)
+	handler.HandleFunc.PushReturn(fmt.Errorf("oops"))
+	outer := &dynamoDBEventHandler{
+		handler: &dynamoDBRecordHandler{handler: handler, reportBatchItemFailures: true},
+		Logger:  nacelle.NewNilLogger(),
+	}
+
+	response, err := outer.Invoke(context.Background(), []byte(testDynamoDBPayload))
+	require.Nil(t, err)
+	require.JSONEq(t, `{"batchItemFailures": [{"itemIdentifier": "2"}]}`, string(response))
+	mockassert.CalledN(t, handler.HandleFunc, 2)
+}
+
 func TestDynamoDBRecordHandle(t *testing.T) {
 	handler := NewMockDynamoDBRecordHandlerInitializer()
 	outer := &dynamoDBRecordHandler{handler: handler}
@@ -233,6 +256,145 @@ func TestDynamoDBRecordHandleError(t *testing.T) {
 	mockassert.CalledN(t, handler.HandleFunc, 2)
 }
 
+func TestDynamoDBRecordHandleWithResponse(t *testing.T) {
+	handler := NewMockDynamoDBRecordHandlerInitializer()
+	outer := &dynamoDBRecordHandler{handler: handler, reportBatchItemFailures: true}
+
+	response, err := outer.HandleWithResponse(context.Background(), testDynamoDBRecords, nacelle.NewNilLogger())
+	require.Nil(t, err)
+	require.Empty(t, response.BatchItemFailures)
+	mockassert.CalledN(t, handler.HandleFunc, 3)
+}
+
+func TestDynamoDBRecordHandleWithResponseDisabled(t *testing.T) {
+	handler := NewMockDynamoDBRecordHandlerInitializer()
+	handler.HandleFunc.PushReturn(fmt.Errorf("oops"))
+	outer := &dynamoDBRecordHandler{handler: handler}
+
+	response, err := outer.HandleWithResponse(context.Background(), testDynamoDBRecords, nacelle.NewNilLogger())
+	require.EqualError(t, err, "failed to process DynamoDB record ev1 (oops)")
+	require.Nil(t, response)
+	mockassert.CalledN(t, handler.HandleFunc, 1)
+}
+
+func TestDynamoDBRecordHandleConcurrentPartitionKey(t *testing.T) {
+	records := []events.DynamoDBEventRecord{
+		testDynamoDBKeyRecord("1", "foo", "bonk"),
+		testDynamoDBKeyRecord("2", "foo", "quux"),
+		testDynamoDBKeyRecord("3", "bar", "honk"),
+	}
+
+	var mutex sync.Mutex
+	var handled []string
+	released := make(chan struct{})
+
+	handler := NewMockDynamoDBRecordHandlerInitializer()
+	handler.HandleFunc.SetDefaultHook(func(ctx context.Context, record events.DynamoDBEventRecord, logger nacelle.Logger) error {
+		switch record.Change.SequenceNumber {
+		case "1":
+			// Blocks until a record with another partition key is handled
+			select {
+			case <-released:
+			case <-time.After(time.Second):
+				return fmt.Errorf("records were not handled in parallel")
+			}
+		case "3":
+			close(released)
+		}
+
+		mutex.Lock()
+		defer mutex.Unlock()
+		handled = append(handled, record.Change.SequenceNumber)
+		return nil
+	})
+	outer := &dynamoDBRecordHandler{handler: handler, recordConcurrency: 3, partitionKey: "PK"}
+
+	err := outer.Handle(context.Background(), records, nacelle.NewNilLogger())
+	require.Nil(t, err)
+	require.Equal(t, []string{"3", "1", "2"}, handled)
+}
+
+func TestDynamoDBRecordKey(t *testing.T) {
+	simple := events.DynamoDBEventRecord{Change: events.DynamoDBStreamRecord{
+		Keys: map[string]events.DynamoDBAttributeValue{"PK": events.NewStringAttribute("foo")},
+	}}
+
+	require.Equal(t, `{"PK":{"S":"foo"}}`, dynamoDBRecordKey(simple, ""))
+	require.Equal(t, `{"PK":{"S":"foo"}}`, dynamoDBRecordKey(testDynamoDBKeyRecord("1", "foo", "bonk"), "PK"))
+	require.Equal(t, dynamoDBCompositeKey, dynamoDBRecordKey(testDynamoDBKeyRecord("1", "foo", "bonk"), ""))
+	require.Equal(t, "", dynamoDBRecordKey(events.DynamoDBEventRecord{}, "PK"))
+}
+
+func TestTypedDynamoDBRecordHandle(t *testing.T) {
+	handler := &testTypedDynamoDBRecordHandler{}
+	outer := &dynamoDBRecordHandler{
+		handler: &typedDynamoDBRecordHandler[testDynamoDBKey]{handler: handler},
+	}
+
+	err := outer.Handle(context.Background(), testDynamoDBRecords, nacelle.NewNilLogger())
+	require.Nil(t, err)
+	require.Equal(t, []*testDynamoDBKey{nil, nil, nil}, handler.olds)
+	require.Equal(t, []*testDynamoDBKey{{PK: "foo", SK: "bonk"}, {PK: "bar", SK: "quux"}, {PK: "baz", SK: "honk"}}, handler.news)
+	require.Equal(t, []string{"INSERT", "INSERT", "INSERT"}, handler.eventNames)
+}
+
+func TestTypedDynamoDBRecordHandleModify(t *testing.T) {
+	handler := &testTypedDynamoDBRecordHandler{}
+	outer := &typedDynamoDBRecordHandler[testDynamoDBKey]{handler: handler}
+
+	record := events.DynamoDBEventRecord{
+		EventName: "MODIFY",
+		Change: events.DynamoDBStreamRecord{
+			OldImage: map[string]events.DynamoDBAttributeValue{"PK": events.NewStringAttribute("foo"), "SK": events.NewStringAttribute("a")},
+			NewImage: map[string]events.DynamoDBAttributeValue{"PK": events.NewStringAttribute("foo"), "SK": events.NewStringAttribute("b")},
+		},
+	}
+
+	err := outer.Handle(context.Background(), record, nacelle.NewNilLogger())
+	require.Nil(t, err)
+	require.Equal(t, []*testDynamoDBKey{{PK: "foo", SK: "a"}}, handler.olds)
+	require.Equal(t, []*testDynamoDBKey{{PK: "foo", SK: "b"}}, handler.news)
+	require.Equal(t, []string{"SK"}, ChangedDynamoDBAttributes(handler.olds[0], handler.news[0]))
+}
+
+func TestTypedDynamoDBRecordHandleDecodeError(t *testing.T) {
+	handler := &testTypedDynamoDBRecordHandler{}
+	outer := &typedDynamoDBRecordHandler[testDynamoDBKey]{handler: handler}
+
+	record := events.DynamoDBEventRecord{
+		EventName: "REMOVE",
+		Change: events.DynamoDBStreamRecord{
+			OldImage: map[string]events.DynamoDBAttributeValue{"PK": events.NewBooleanAttribute(true)},
+		},
+	}
+
+	err := outer.Handle(context.Background(), record, nacelle.NewNilLogger())
+	require.NotNil(t, err)
+	require.Contains(t, err.Error(), "failed to decode DynamoDB old image")
+	require.Empty(t, handler.eventNames)
+}
+
+//
+// Typed
+
+type testDynamoDBKey struct {
+	PK string
+	SK string
+}
+
+type testTypedDynamoDBRecordHandler struct {
+	olds       []*testDynamoDBKey
+	news       []*testDynamoDBKey
+	eventNames []string
+}
+
+func (h *testTypedDynamoDBRecordHandler) Handle(ctx context.Context, old *testDynamoDBKey, new *testDynamoDBKey, eventName string, logger nacelle.Logger) error {
+	h.olds = append(h.olds, old)
+	h.news = append(h.news, new)
+	h.eventNames = append(h.eventNames, eventName)
+	return nil
+}
+
 //
 // Bad Injection
 
@@ -251,3 +413,16 @@ type badInjectionDynamoDBRecordHandler struct {
 func (i *badInjectionDynamoDBRecordHandler) Handle(ctx context.Context, record events.DynamoDBEventRecord, logger nacelle.Logger) error {
 	return nil
 }
+
+func testDynamoDBKeyRecord(sequenceNumber, pk, sk string) events.DynamoDBEventRecord {
+	return events.DynamoDBEventRecord{
+		EventID: "ev" + sequenceNumber,
+		Change: events.DynamoDBStreamRecord{
+			SequenceNumber: sequenceNumber,
+			Keys: map[string]events.DynamoDBAttributeValue{
+				"PK": events.NewStringAttribute(pk),
+				"SK": events.NewStringAttribute(sk),
+			},
+		},
+	}
+}
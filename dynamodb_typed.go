@@ -0,0 +1,58 @@
+package lambdabase
+
+import (
+	"context"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/go-nacelle/nacelle/v2"
+)
+
+type (
+	TypedDynamoDBRecordHandler[T any] interface {
+		Handle(ctx context.Context, old *T, new *T, eventName string, logger nacelle.Logger) error
+	}
+
+	typedDynamoDBRecordHandler[T any] struct {
+		Services *nacelle.ServiceContainer `service:"services"`
+		handler  TypedDynamoDBRecordHandler[T]
+	}
+)
+
+func NewTypedDynamoDBServer[T any](handler TypedDynamoDBRecordHandler[T], configs ...ConfigFunc) *Server {
+	return NewDynamoDBRecordServer(&typedDynamoDBRecordHandler[T]{
+		handler: handler,
+	}, configs...)
+}
+
+func (h *typedDynamoDBRecordHandler[T]) Init(ctx context.Context) error {
+	return doInit(ctx, h.Services, h.handler)
+}
+
+func (h *typedDynamoDBRecordHandler[T]) Handle(ctx context.Context, record events.DynamoDBEventRecord, logger nacelle.Logger) error {
+	old, err := decodeDynamoDBImage[T](record.Change.OldImage)
+	if err != nil {
+		return &DecodeError{Part: "DynamoDB old image", Err: err}
+	}
+
+	new, err := decodeDynamoDBImage[T](record.Change.NewImage)
+	if err != nil {
+		return &DecodeError{Part: "DynamoDB new image", Err: err}
+	}
+
+	return h.handler.Handle(ctx, old, new, record.EventName, logger)
+}
+
+// decodeDynamoDBImage returns nil for images absent from the record, such as
+// the old image of an INSERT or either image of a KEYS_ONLY stream.
+func decodeDynamoDBImage[T any](image map[string]events.DynamoDBAttributeValue) (*T, error) {
+	if len(image) == 0 {
+		return nil, nil
+	}
+
+	value := new(T)
+	if err := UnmarshalDynamoDBImage(image, value); err != nil {
+		return nil, err
+	}
+
+	return value, nil
+}
@@ -0,0 +1,164 @@
+package lambdabase
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/aws/aws-lambda-go/events"
+)
+
+const (
+	eventSourceS3  = "aws:s3"
+	eventSourceSNS = "aws:sns"
+	eventSourceSQS = "aws:sqs"
+
+	eventBridgeSourceS3 = "aws.s3"
+)
+
+type envelopeProbe struct {
+	Records []struct {
+		EventSource string `json:"eventSource"`
+	} `json:"Records"`
+	Source string `json:"source"`
+}
+
+// probeEnvelope returns the event source of the first record of the given
+// payload and the source of an EventBridge event, whichever is present.
+// Field names are matched case-insensitively, which covers both the
+// eventSource key of SQS records and the EventSource key of SNS records.
+func probeEnvelope(payload []byte) (eventSource string, source string, _ error) {
+	probe := envelopeProbe{}
+	if err := json.Unmarshal(payload, &probe); err != nil {
+		return "", "", err
+	}
+
+	if len(probe.Records) > 0 {
+		eventSource = probe.Records[0].EventSource
+	}
+
+	return eventSource, probe.Source, nil
+}
+
+func unwrapSQSSNSRecords(messages []events.SQSMessage) ([]events.SNSEventRecord, error) {
+	records := make([]events.SNSEventRecord, 0, len(messages))
+	for _, message := range messages {
+		entity := events.SNSEntity{}
+		if err := json.Unmarshal([]byte(message.Body), &entity); err != nil {
+			return nil, fmt.Errorf("failed to unwrap SNS notification from SQS message %s (%w)", message.MessageId, err)
+		}
+
+		records = append(records, events.SNSEventRecord{
+			EventVersion: "1.0",
+			EventSource:  eventSourceSNS,
+			SNS:          entity,
+		})
+	}
+
+	return records, nil
+}
+
+type (
+	s3NotificationProbe struct {
+		Type    string `json:"Type"`
+		Message string `json:"Message"`
+		Source  string `json:"source"`
+		Event   string `json:"Event"`
+	}
+
+	eventBridgeS3Detail struct {
+		Bucket struct {
+			Name string `json:"name"`
+		} `json:"bucket"`
+		Object struct {
+			Key       string `json:"key"`
+			Size      int64  `json:"size"`
+			ETag      string `json:"etag"`
+			VersionID string `json:"version-id"`
+			Sequencer string `json:"sequencer"`
+		} `json:"object"`
+		Requester       string `json:"requester"`
+		SourceIPAddress string `json:"source-ip-address"`
+		Reason          string `json:"reason"`
+	}
+)
+
+// unwrapS3Notification returns the S3 records carried by the body of an SQS
+// message or the message of an SNS notification. The body may itself be an
+// SNS notification (S3 to SNS to SQS), an EventBridge event (S3 to EventBridge
+// to SQS), an S3 test event (which carries no records), or an S3 event.
+func unwrapS3Notification(data []byte) ([]events.S3EventRecord, error) {
+	probe := s3NotificationProbe{}
+	if err := json.Unmarshal(data, &probe); err != nil {
+		return nil, err
+	}
+
+	if probe.Type == "Notification" {
+		return unwrapS3Notification([]byte(probe.Message))
+	}
+
+	if probe.Source == eventBridgeSourceS3 {
+		event := events.CloudWatchEvent{}
+		if err := json.Unmarshal(data, &event); err != nil {
+			return nil, err
+		}
+
+		record, err := convertEventBridgeS3Record(event)
+		if err != nil {
+			return nil, err
+		}
+
+		return []events.S3EventRecord{record}, nil
+	}
+
+	if probe.Event == "s3:TestEvent" {
+		return nil, nil
+	}
+
+	event := events.S3Event{}
+	if err := json.Unmarshal(data, &event); err != nil {
+		return nil, err
+	}
+
+	return event.Records, nil
+}
+
+// convertEventBridgeS3Record converts an S3 event delivered by EventBridge
+// into the shape of an S3 event notification record. EventBridge does not
+// URL-encode object keys, so the key is encoded here to match the format of
+// S3 event notifications.
+func convertEventBridgeS3Record(event events.CloudWatchEvent) (events.S3EventRecord, error) {
+	detail := eventBridgeS3Detail{}
+	if err := json.Unmarshal(event.Detail, &detail); err != nil {
+		return events.S3EventRecord{}, err
+	}
+
+	return events.S3EventRecord{
+		EventVersion: "2.1",
+		EventSource:  eventSourceS3,
+		AWSRegion:    event.Region,
+		EventTime:    event.Time,
+		EventName:    strings.ReplaceAll(event.DetailType, " ", "") + ":" + detail.Reason,
+		PrincipalID: events.S3UserIdentity{
+			PrincipalID: detail.Requester,
+		},
+		RequestParameters: events.S3RequestParameters{
+			SourceIPAddress: detail.SourceIPAddress,
+		},
+		S3: events.S3Entity{
+			Bucket: events.S3Bucket{
+				Name: detail.Bucket.Name,
+				Arn:  "arn:aws:s3:::" + detail.Bucket.Name,
+			},
+			Object: events.S3Object{
+				Key:           url.QueryEscape(detail.Object.Key),
+				URLDecodedKey: detail.Object.Key,
+				Size:          detail.Object.Size,
+				ETag:          detail.Object.ETag,
+				VersionID:     detail.Object.VersionID,
+				Sequencer:     detail.Object.Sequencer,
+			},
+		},
+	}, nil
+}
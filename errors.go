@@ -0,0 +1,178 @@
+package lambdabase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"runtime"
+	"time"
+
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/aws/aws-lambda-go/lambda/messages"
+)
+
+type (
+	// RecordError is returned by a record server when the handler fails to
+	// process a record of a batch. Source names the event source of the
+	// record, such as SQS or Kinesis, and RecordID identifies the record
+	// within its batch.
+	RecordError struct {
+		Source   string
+		RecordID string
+		Err      error
+		kind     string
+	}
+
+	// DecodeError is returned when a part of an event, such as the body of
+	// an SQS message, cannot be decoded into the value a handler expects.
+	DecodeError struct {
+		Part string
+		Err  error
+	}
+
+	// InvokeError sets the error type and stack trace reported to Lambda
+	// when it is returned, possibly wrapped, from a handler. The message of
+	// the reported error is the message of the outermost error.
+	InvokeError struct {
+		Type       string
+		Err        error
+		StackTrace []*messages.InvokeResponse_Error_StackFrame
+	}
+
+	// DrainTimeoutError is returned from the Run method of a server when
+	// invocations are still in flight after the drain timeout elapses. The
+	// contexts of these invocations are canceled, and RequestIDs lists
+	// their request IDs.
+	DrainTimeoutError struct {
+		Timeout    time.Duration
+		RequestIDs []string
+	}
+)
+
+func newRecordError(source, kind, recordID string, err error) *RecordError {
+	return &RecordError{Source: source, RecordID: recordID, Err: err, kind: kind}
+}
+
+func (e *RecordError) Error() string {
+	kind := e.kind
+	if kind == "" {
+		kind = "record"
+	}
+
+	return fmt.Sprintf("failed to process %s %s %s (%s)", e.Source, kind, e.RecordID, e.Err.Error())
+}
+
+func (e *RecordError) Unwrap() error {
+	return e.Err
+}
+
+func (e *DecodeError) Error() string {
+	return fmt.Sprintf("failed to decode %s (%s)", e.Part, e.Err.Error())
+}
+
+func (e *DecodeError) Unwrap() error {
+	return e.Err
+}
+
+// NewInvokeError creates an error reported to Lambda with the given error
+// type and the stack trace of the caller.
+func NewInvokeError(errorType string, err error) *InvokeError {
+	return &InvokeError{
+		Type:       errorType,
+		Err:        err,
+		StackTrace: stackFrames(3),
+	}
+}
+
+func (e *InvokeError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *InvokeError) Unwrap() error {
+	return e.Err
+}
+
+func (e *DrainTimeoutError) Error() string {
+	return fmt.Sprintf("lambda server did not drain within %s (%d invocations in flight)", e.Timeout, len(e.RequestIDs))
+}
+
+// reportErrors wraps the given handler so that a returned error is converted
+// into the error response reported to Lambda. An InvokeResponse_Error in the
+// chain of the returned error is reported unchanged. Otherwise, the error type
+// and stack trace of an InvokeError in the chain are reported. Otherwise, the
+// error type is the name of the outermost error type that does not only add
+// context to the message of another error.
+func reportErrors(handler lambda.Handler) lambda.Handler {
+	return LambdaHandlerFunc(func(ctx context.Context, payload []byte) ([]byte, error) {
+		response, err := handler.Invoke(ctx, payload)
+		if err == nil {
+			return response, nil
+		}
+
+		return response, lambdaErrorResponse(err)
+	})
+}
+
+func lambdaErrorResponse(err error) messages.InvokeResponse_Error {
+	var responseErr messages.InvokeResponse_Error
+	if errors.As(err, &responseErr) {
+		return responseErr
+	}
+
+	var invokeErr *InvokeError
+	if errors.As(err, &invokeErr) {
+		return messages.InvokeResponse_Error{
+			Message:    err.Error(),
+			Type:       invokeErr.Type,
+			StackTrace: invokeErr.StackTrace,
+		}
+	}
+
+	return messages.InvokeResponse_Error{
+		Message: err.Error(),
+		Type:    errorTypeName(err),
+	}
+}
+
+// errorTypeName returns the name of the type of the given error, skipping
+// errors created by fmt.Errorf that wrap another error.
+func errorTypeName(err error) string {
+	for {
+		errorType := reflect.TypeOf(err)
+		if errorType.Kind() == reflect.Ptr {
+			errorType = errorType.Elem()
+		}
+
+		next := errors.Unwrap(err)
+		if errorType.PkgPath() != "fmt" || next == nil {
+			return errorType.Name()
+		}
+
+		err = next
+	}
+}
+
+// stackFrames returns the stack of the calling goroutine, skipping the given
+// number of frames as with runtime.Callers.
+func stackFrames(skip int) []*messages.InvokeResponse_Error_StackFrame {
+	pcs := make([]uintptr, 32)
+	n := runtime.Callers(skip, pcs)
+
+	stack := []*messages.InvokeResponse_Error_StackFrame{}
+	frames := runtime.CallersFrames(pcs[:n])
+	for {
+		frame, more := frames.Next()
+		stack = append(stack, &messages.InvokeResponse_Error_StackFrame{
+			Path:  frame.File,
+			Line:  int32(frame.Line),
+			Label: frame.Function,
+		})
+
+		if !more {
+			break
+		}
+	}
+
+	return stack
+}
@@ -0,0 +1,111 @@
+package lambdabase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda/messages"
+	"github.com/go-nacelle/config/v3"
+	"github.com/go-nacelle/nacelle/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecordError(t *testing.T) {
+	cause := fmt.Errorf("oops")
+	handler := NewMockSqsMessageHandlerInitializer()
+	handler.HandleFunc.PushReturn(nil)
+	handler.HandleFunc.PushReturn(cause)
+	outer := &sqsMessageHandler{handler: handler}
+
+	err := outer.Handle(context.Background(), testSQSMessages, nacelle.NewNilLogger())
+	require.EqualError(t, err, "failed to process SQS message m2 (oops)")
+	require.True(t, errors.Is(err, cause))
+
+	var recordErr *RecordError
+	require.True(t, errors.As(err, &recordErr))
+	require.Equal(t, "SQS", recordErr.Source)
+	require.Equal(t, "m2", recordErr.RecordID)
+
+	require.EqualError(t, &RecordError{Source: "Custom", RecordID: "r1", Err: cause}, "failed to process Custom record r1 (oops)")
+}
+
+func TestDecodeError(t *testing.T) {
+	handler := &testTypedSQSMessageHandler{}
+	outer := &sqsMessageHandler{
+		handler: &typedSQSMessageHandler[testTypedValue]{handler: handler, codec: JSONCodec{}},
+	}
+
+	batch := []events.SQSMessage{
+		{MessageId: "m1", Body: `not json`},
+	}
+
+	err := outer.Handle(context.Background(), batch, nacelle.NewNilLogger())
+	require.NotNil(t, err)
+
+	var decodeErr *DecodeError
+	require.True(t, errors.As(err, &decodeErr))
+	require.Equal(t, "SQS message body", decodeErr.Part)
+
+	var syntaxErr *json.SyntaxError
+	require.True(t, errors.As(err, &syntaxErr))
+}
+
+func TestLambdaErrorResponse(t *testing.T) {
+	recordErr := &RecordError{Source: "SQS", RecordID: "m1", Err: fmt.Errorf("oops"), kind: "message"}
+
+	response := lambdaErrorResponse(fmt.Errorf("oops"))
+	require.Equal(t, "oops", response.Message)
+	require.Equal(t, "errorString", response.Type)
+
+	response = lambdaErrorResponse(fmt.Errorf("failed to process SQS event (%w)", recordErr))
+	require.Equal(t, "failed to process SQS event (failed to process SQS message m1 (oops))", response.Message)
+	require.Equal(t, "RecordError", response.Type)
+
+	invokeErr := NewInvokeError("ValidationError", fmt.Errorf("bad input"))
+	response = lambdaErrorResponse(fmt.Errorf("failed to process SQS event (%w)", &RecordError{Source: "SQS", RecordID: "m1", Err: invokeErr}))
+	require.Equal(t, "failed to process SQS event (failed to process SQS record m1 (bad input))", response.Message)
+	require.Equal(t, "ValidationError", response.Type)
+	require.NotEmpty(t, response.StackTrace)
+	require.True(t, strings.HasSuffix(response.StackTrace[0].Label, "TestLambdaErrorResponse"))
+
+	responseErr := messages.InvokeResponse_Error{Message: "custom", Type: "Custom.Error"}
+	response = lambdaErrorResponse(fmt.Errorf("wrapped (%w)", responseErr))
+	require.Equal(t, responseErr, response)
+}
+
+func TestServerInvokeErrorType(t *testing.T) {
+	runtimeAPI := newTestRuntimeAPI()
+	defer runtimeAPI.Close()
+
+	ctx := context.Background()
+	ctx = config.WithConfig(ctx, runtimeAPI.config())
+
+	errorHandler := LambdaHandlerFunc(func(ctx context.Context, payload []byte) ([]byte, error) {
+		return nil, fmt.Errorf("failed to handle request (%w)", NewInvokeError("ValidationError", fmt.Errorf("bad input")))
+	})
+
+	server := makeLambdaServer(errorHandler)
+	err := server.Init(ctx)
+	require.Nil(t, err)
+
+	errs := make(chan error, 1)
+	go func() { errs <- server.Run(ctx) }()
+
+	runtimeAPI.invocations <- testRuntimeAPIInvocation{requestID: "bonk", payload: `{}`}
+	result := <-runtimeAPI.results
+	require.Equal(t, "/2018-06-01/runtime/invocation/bonk/error", result.path)
+	require.Equal(t, "ValidationError", result.errorType)
+
+	invokeErr := runtimeAPIError{}
+	require.Nil(t, json.Unmarshal([]byte(result.body), &invokeErr))
+	require.Equal(t, "failed to handle request (bad input)", invokeErr.Message)
+	require.NotEmpty(t, invokeErr.StackTrace)
+
+	require.Nil(t, server.Stop(ctx))
+	require.Nil(t, <-errs)
+}
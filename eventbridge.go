@@ -0,0 +1,131 @@
+package lambdabase
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/go-nacelle/nacelle/v2"
+)
+
+type (
+	TypedEventBridgeHandler[T any] interface {
+		Handle(ctx context.Context, detail T, event events.CloudWatchEvent, logger nacelle.Logger) error
+	}
+
+	// EventBridgeRoute pairs an event source and detail type with the handler
+	// that receives matching events. Routes are created by NewEventBridgeRoute.
+	EventBridgeRoute struct {
+		source     string
+		detailType string
+		handler    interface{}
+		handle     func(ctx context.Context, event events.CloudWatchEvent, codec Codec, logger nacelle.Logger) error
+	}
+
+	eventBridgeHandler struct {
+		Logger           nacelle.Logger            `service:"logger"`
+		Services         *nacelle.ServiceContainer `service:"services"`
+		routes           []EventBridgeRoute
+		codec            Codec
+		recordMiddleware []RecordMiddleware
+	}
+)
+
+// NewEventBridgeRoute creates a route that decodes the detail of events with
+// the given source and detail type into a value of the handler's type. An
+// empty source or detail type matches any value.
+func NewEventBridgeRoute[T any](source, detailType string, handler TypedEventBridgeHandler[T]) EventBridgeRoute {
+	return EventBridgeRoute{
+		source:     source,
+		detailType: detailType,
+		handler:    handler,
+		handle: func(ctx context.Context, event events.CloudWatchEvent, codec Codec, logger nacelle.Logger) error {
+			var detail T
+			if err := codec.Unmarshal(event.Detail, &detail); err != nil {
+				return &DecodeError{Part: "EventBridge event detail", Err: err}
+			}
+
+			return handler.Handle(ctx, detail, event, logger)
+		},
+	}
+}
+
+func NewEventBridgeServer(routes []EventBridgeRoute, configs ...ConfigFunc) *Server {
+	options := getOptions(configs)
+
+	return NewServer(&eventBridgeHandler{
+		routes:           routes,
+		codec:            options.codec,
+		recordMiddleware: options.recordMiddleware,
+	}, configs...)
+}
+
+// Init injects services into and initializes the handler of each route. A
+// handler registered under several routes is initialized only once.
+func (h *eventBridgeHandler) Init(ctx context.Context) error {
+	initialized := map[interface{}]struct{}{}
+	for _, route := range h.routes {
+		comparable := reflect.TypeOf(route.handler).Comparable()
+		if comparable {
+			if _, ok := initialized[route.handler]; ok {
+				continue
+			}
+		}
+
+		if err := doInit(ctx, h.Services, route.handler); err != nil {
+			return err
+		}
+
+		if comparable {
+			initialized[route.handler] = struct{}{}
+		}
+	}
+
+	return nil
+}
+
+func (h *eventBridgeHandler) Invoke(ctx context.Context, payload []byte) ([]byte, error) {
+	event := events.CloudWatchEvent{}
+	if err := json.Unmarshal(payload, &event); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal event (%w)", err)
+	}
+
+	logger := h.Logger.WithFields(map[string]interface{}{
+		"requestId":  GetRequestID(ctx),
+		"eventId":    event.ID,
+		"source":     event.Source,
+		"detailType": event.DetailType,
+	})
+
+	route, ok := h.match(event)
+	if !ok {
+		return nil, fmt.Errorf("no handler registered for EventBridge event with source %q and detail-type %q", event.Source, event.DetailType)
+	}
+
+	logger.Debug("Received EventBridge event")
+
+	handle := func(ctx context.Context, event events.CloudWatchEvent, logger nacelle.Logger) error {
+		return route.handle(ctx, event, h.codec, logger)
+	}
+
+	if err := invokeRecordHandler(ctx, h.recordMiddleware, event, logger, handle); err != nil {
+		return nil, newRecordError("EventBridge", "event", event.ID, err)
+	}
+
+	logger.Debug("EventBridge event handled successfully")
+	return nil, nil
+}
+
+// match returns the first registered route that matches the source and
+// detail type of the given event.
+func (h *eventBridgeHandler) match(event events.CloudWatchEvent) (EventBridgeRoute, bool) {
+	for _, route := range h.routes {
+		if (route.source == "" || route.source == event.Source) && (route.detailType == "" || route.detailType == event.DetailType) {
+			return route, true
+		}
+	}
+
+	return EventBridgeRoute{}, false
+}
@@ -0,0 +1,215 @@
+package lambdabase
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/go-nacelle/config/v3"
+	"github.com/go-nacelle/nacelle/v2"
+	"github.com/stretchr/testify/require"
+)
+
+var testEventBridgePayload = `{
+	"version": "0",
+	"id": "e1",
+	"detail-type": "Order Placed",
+	"source": "com.example.orders",
+	"account": "123456789012",
+	"time": "2021-11-12T00:00:00Z",
+	"region": "us-east-1",
+	"resources": [],
+	"detail": {"orderId": "o1", "total": 12}
+}`
+
+type testOrderPlaced struct {
+	OrderID string `json:"orderId"`
+	Total   int    `json:"total"`
+}
+
+func TestEventBridgeInit(t *testing.T) {
+	handler := &testEventBridgeHandler{}
+	outer := &eventBridgeHandler{
+		routes:   []EventBridgeRoute{NewEventBridgeRoute[testOrderPlaced]("com.example.orders", "Order Placed", handler)},
+		Logger:   nacelle.NewNilLogger(),
+		Services: nacelle.NewServiceContainer(),
+	}
+
+	ctx := context.Background()
+
+	cfg := nacelle.NewConfig(nacelle.NewTestEnvSourcer(nil))
+	ctx = config.WithConfig(ctx, cfg)
+
+	err := outer.Init(ctx)
+	require.Nil(t, err)
+	require.True(t, handler.initialized)
+}
+
+func TestEventBridgeInitSharedHandler(t *testing.T) {
+	handler := &testEventBridgeHandler{}
+	outer := &eventBridgeHandler{
+		routes: []EventBridgeRoute{
+			NewEventBridgeRoute[testOrderPlaced]("com.example.orders", "Order Placed", handler),
+			NewEventBridgeRoute[testOrderPlaced]("com.example.orders", "Order Updated", handler),
+			NewEventBridgeRoute[testOrderPlaced]("", "", handler),
+			NewEventBridgeRoute[[]string]("", "", &testEventBridgeListHandler{}),
+		},
+		Logger:   nacelle.NewNilLogger(),
+		Services: nacelle.NewServiceContainer(),
+	}
+
+	ctx := context.Background()
+
+	cfg := nacelle.NewConfig(nacelle.NewTestEnvSourcer(nil))
+	ctx = config.WithConfig(ctx, cfg)
+
+	err := outer.Init(ctx)
+	require.Nil(t, err)
+	require.Equal(t, 1, handler.inits)
+}
+
+func TestEventBridgeBadInjection(t *testing.T) {
+	outer := &eventBridgeHandler{
+		routes:   []EventBridgeRoute{NewEventBridgeRoute[json.RawMessage]("", "", &badInjectionEventBridgeHandler{})},
+		Logger:   nacelle.NewNilLogger(),
+		Services: makeBadContainer(),
+	}
+
+	ctx := context.Background()
+
+	cfg := nacelle.NewConfig(nacelle.NewTestEnvSourcer(nil))
+	ctx = config.WithConfig(ctx, cfg)
+
+	err := outer.Init(ctx)
+	require.NotNil(t, err)
+	require.Contains(t, err.Error(), "ServiceA")
+}
+
+func TestEventBridgeInitError(t *testing.T) {
+	handler := &testEventBridgeHandler{initErr: fmt.Errorf("oops")}
+	outer := &eventBridgeHandler{
+		routes:   []EventBridgeRoute{NewEventBridgeRoute[testOrderPlaced]("", "", handler)},
+		Logger:   nacelle.NewNilLogger(),
+		Services: nacelle.NewServiceContainer(),
+	}
+
+	ctx := context.Background()
+
+	cfg := nacelle.NewConfig(nacelle.NewTestEnvSourcer(nil))
+	ctx = config.WithConfig(ctx, cfg)
+
+	err := outer.Init(ctx)
+	require.EqualError(t, err, "oops")
+}
+
+func TestEventBridgeInvoke(t *testing.T) {
+	other := &testEventBridgeHandler{}
+	handler := &testEventBridgeHandler{}
+	outer := &eventBridgeHandler{
+		routes: []EventBridgeRoute{
+			NewEventBridgeRoute[testOrderPlaced]("com.example.orders", "Order Shipped", other),
+			NewEventBridgeRoute[testOrderPlaced]("com.example.orders", "Order Placed", handler),
+			NewEventBridgeRoute[testOrderPlaced]("", "", other),
+		},
+		Logger: nacelle.NewNilLogger(),
+		codec:  JSONCodec{},
+	}
+
+	response, err := outer.Invoke(context.Background(), []byte(testEventBridgePayload))
+	require.Nil(t, err)
+	require.Nil(t, response)
+	require.Equal(t, []testOrderPlaced{{OrderID: "o1", Total: 12}}, handler.details)
+	require.Equal(t, "e1", handler.events[0].ID)
+	require.Empty(t, other.details)
+}
+
+func TestEventBridgeInvokeWildcard(t *testing.T) {
+	handler := &testEventBridgeHandler{}
+	outer := &eventBridgeHandler{
+		routes: []EventBridgeRoute{NewEventBridgeRoute[testOrderPlaced]("com.example.orders", "", handler)},
+		Logger: nacelle.NewNilLogger(),
+		codec:  JSONCodec{},
+	}
+
+	_, err := outer.Invoke(context.Background(), []byte(testEventBridgePayload))
+	require.Nil(t, err)
+	require.Len(t, handler.details, 1)
+}
+
+func TestEventBridgeInvokeNoRoute(t *testing.T) {
+	outer := &eventBridgeHandler{
+		routes: []EventBridgeRoute{NewEventBridgeRoute[testOrderPlaced]("com.example.payments", "", &testEventBridgeHandler{})},
+		Logger: nacelle.NewNilLogger(),
+		codec:  JSONCodec{},
+	}
+
+	_, err := outer.Invoke(context.Background(), []byte(testEventBridgePayload))
+	require.EqualError(t, err, `no handler registered for EventBridge event with source "com.example.orders" and detail-type "Order Placed"`)
+}
+
+func TestEventBridgeInvokeError(t *testing.T) {
+	handler := &testEventBridgeHandler{handleErr: fmt.Errorf("oops")}
+	outer := &eventBridgeHandler{
+		routes: []EventBridgeRoute{NewEventBridgeRoute[testOrderPlaced]("", "", handler)},
+		Logger: nacelle.NewNilLogger(),
+		codec:  JSONCodec{},
+	}
+
+	_, err := outer.Invoke(context.Background(), []byte(testEventBridgePayload))
+	require.EqualError(t, err, "failed to process EventBridge event e1 (oops)")
+}
+
+func TestEventBridgeInvokeDecodeError(t *testing.T) {
+	outer := &eventBridgeHandler{
+		routes: []EventBridgeRoute{NewEventBridgeRoute[[]string]("", "", &testEventBridgeListHandler{})},
+		Logger: nacelle.NewNilLogger(),
+		codec:  JSONCodec{},
+	}
+
+	_, err := outer.Invoke(context.Background(), []byte(testEventBridgePayload))
+	require.NotNil(t, err)
+	require.Contains(t, err.Error(), "failed to decode EventBridge event detail")
+}
+
+//
+// Typed
+
+type testEventBridgeHandler struct {
+	initErr     error
+	handleErr   error
+	initialized bool
+	inits       int
+	details     []testOrderPlaced
+	events      []events.CloudWatchEvent
+}
+
+func (h *testEventBridgeHandler) Init(ctx context.Context) error {
+	h.initialized = true
+	h.inits++
+	return h.initErr
+}
+
+func (h *testEventBridgeHandler) Handle(ctx context.Context, detail testOrderPlaced, event events.CloudWatchEvent, logger nacelle.Logger) error {
+	h.details = append(h.details, detail)
+	h.events = append(h.events, event)
+	return h.handleErr
+}
+
+type testEventBridgeListHandler struct{}
+
+func (h *testEventBridgeListHandler) Handle(ctx context.Context, detail []string, event events.CloudWatchEvent, logger nacelle.Logger) error {
+	return nil
+}
+
+//
+// Bad Injection
+
+type badInjectionEventBridgeHandler struct {
+	ServiceA *A `service:"A"`
+}
+
+func (i *badInjectionEventBridgeHandler) Handle(ctx context.Context, detail json.RawMessage, event events.CloudWatchEvent, logger nacelle.Logger) error {
+	return nil
+}
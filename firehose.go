@@ -0,0 +1,236 @@
+package lambdabase
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/go-nacelle/nacelle/v2"
+)
+
+type (
+	// FirehoseTransformHandler transforms a single record of a Firehose
+	// event. A returned error marks the record as failed, and a result
+	// without a status delivers its data as transformed.
+	FirehoseTransformHandler interface {
+		Handle(ctx context.Context, record events.KinesisFirehoseEventRecord, logger nacelle.Logger) (FirehoseTransformResult, error)
+	}
+
+	// FirehoseTransformResult is the outcome of transforming a single
+	// Firehose record. Results are created by FirehoseOk, FirehoseDropped,
+	// and FirehoseProcessingFailed.
+	FirehoseTransformResult struct {
+		Result        string
+		Data          []byte
+		PartitionKeys map[string]string
+	}
+
+	firehoseTransformHandler struct {
+		Logger            nacelle.Logger            `service:"logger"`
+		Services          *nacelle.ServiceContainer `service:"services"`
+		handler           FirehoseTransformHandler
+		recordConcurrency int
+		responseLimit     int
+		recordMiddleware  []RecordMiddleware
+	}
+)
+
+// firehoseResponseLimit is the maximum size of a synchronous Lambda response.
+const firehoseResponseLimit = 6 * 1024 * 1024
+
+// FirehoseOk creates a result that delivers the given transformed data.
+func FirehoseOk(data []byte) FirehoseTransformResult {
+	return FirehoseTransformResult{Result: events.KinesisFirehoseTransformedStateOk, Data: data}
+}
+
+// FirehoseDropped creates a result that intentionally discards the record.
+func FirehoseDropped() FirehoseTransformResult {
+	return FirehoseTransformResult{Result: events.KinesisFirehoseTransformedStateDropped}
+}
+
+// FirehoseProcessingFailed creates a result that marks the record as failed.
+// Firehose delivers failed records to the error output of the stream.
+func FirehoseProcessingFailed() FirehoseTransformResult {
+	return FirehoseTransformResult{Result: events.KinesisFirehoseTransformedStateProcessingFailed}
+}
+
+// NewFirehoseTransformServer creates a server that invokes the given handler
+// once for each record of a Firehose event and returns the transformed records
+// to Firehose. Records are independent, so they are transformed in parallel
+// up to the record concurrency. Records that do not fit in the Lambda
+// response size limit are marked as failed, starting from the end of the
+// batch.
+func NewFirehoseTransformServer(handler FirehoseTransformHandler, configs ...ConfigFunc) *Server {
+	options := getOptions(configs)
+
+	return NewServer(&firehoseTransformHandler{
+		handler:           handler,
+		recordConcurrency: options.recordConcurrency,
+		responseLimit:     firehoseResponseLimit,
+		recordMiddleware:  options.recordMiddleware,
+	}, configs...)
+}
+
+func (h *firehoseTransformHandler) Init(ctx context.Context) error {
+	recordConcurrency, err := loadRecordConcurrency(ctx, h.recordConcurrency)
+	if err != nil {
+		return err
+	}
+	h.recordConcurrency = recordConcurrency
+
+	return doInit(ctx, h.Services, h.handler)
+}
+
+func (h *firehoseTransformHandler) Invoke(ctx context.Context, payload []byte) ([]byte, error) {
+	event := events.KinesisFirehoseEvent{}
+	if err := json.Unmarshal(payload, &event); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal event (%w)", err)
+	}
+
+	logger := h.Logger.WithFields(map[string]interface{}{
+		"requestId":         GetRequestID(ctx),
+		"invocationId":      event.InvocationID,
+		"deliveryStreamArn": event.DeliveryStreamArn,
+	})
+
+	logger.Debug("Received %d Firehose records", len(event.Records))
+
+	records := make([]events.KinesisFirehoseResponseRecord, len(event.Records))
+
+	// Firehose records are independent, so they have no ordering key. Errors
+	// are recorded in the response rather than failing the invocation.
+	keys := make([]string, len(event.Records))
+	errs := processBatch(keys, h.recordConcurrency, false, func(i int) (err error) {
+		records[i], err = h.transformRecord(ctx, event.Records[i], logger)
+		return err
+	})
+
+	// Firehose does not retry records marked as failed, so records that were
+	// not started before the deadline fail the invocation instead. Firehose
+	// then retries the entire batch.
+	skipped := 0
+	for _, err := range errs {
+		if err != nil {
+			skipped++
+		}
+	}
+
+	if skipped > 0 {
+		return nil, fmt.Errorf("failed to transform %d Firehose records (%w)", skipped, errDeadlineApproaching)
+	}
+
+	response, err := h.limitResponse(records, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Debug("Firehose records transformed successfully")
+	return response, nil
+}
+
+// transformRecord invokes the handler with the given record. Handler errors
+// mark the record as failed. An error is returned only if the record is not
+// started because the invocation deadline is approaching.
+func (h *firehoseTransformHandler) transformRecord(ctx context.Context, record events.KinesisFirehoseEventRecord, logger nacelle.Logger) (events.KinesisFirehoseResponseRecord, error) {
+	recordLogger := logger.WithFields(map[string]interface{}{
+		"recordId": record.RecordID,
+	})
+
+	recordLogger.Debug("Handling record")
+
+	var result FirehoseTransformResult
+	err := invokeRecordHandler(ctx, h.recordMiddleware, record, recordLogger, func(ctx context.Context, record events.KinesisFirehoseEventRecord, logger nacelle.Logger) (err error) {
+		result, err = h.handler.Handle(ctx, record, logger)
+		return err
+	})
+	if err != nil {
+		if err == errDeadlineApproaching {
+			return events.KinesisFirehoseResponseRecord{}, err
+		}
+
+		recordLogger.Error("Failed to transform Firehose record (%s)", err.Error())
+		result = FirehoseProcessingFailed()
+	}
+
+	if result.Result == "" {
+		result.Result = events.KinesisFirehoseTransformedStateOk
+	}
+
+	return events.KinesisFirehoseResponseRecord{
+		RecordID: record.RecordID,
+		Result:   result.Result,
+		Data:     result.Data,
+		Metadata: events.KinesisFirehoseResponseRecordMetadata{
+			PartitionKeys: result.PartitionKeys,
+		},
+	}, nil
+}
+
+// limitResponse serializes the response for the given records. If the
+// response would exceed the Lambda response size limit, records are marked
+// as failed and their data is removed so that the remaining records can be
+// delivered. Records earlier in the batch are given priority.
+func (h *firehoseTransformHandler) limitResponse(records []events.KinesisFirehoseResponseRecord, logger nacelle.Logger) ([]byte, error) {
+	sizes := make([]int, len(records))
+	failedSizes := make([]int, len(records))
+	failedRecords := make([]events.KinesisFirehoseResponseRecord, len(records))
+
+	// Start with the size of a response in which every record has failed,
+	// which is the smallest response possible for this batch.
+	total := len(`{"records":[]}`)
+	if len(records) > 0 {
+		total += len(records) - 1
+	}
+
+	for i, record := range records {
+		failedRecords[i] = events.KinesisFirehoseResponseRecord{
+			RecordID: record.RecordID,
+			Result:   events.KinesisFirehoseTransformedStateProcessingFailed,
+		}
+
+		size, err := jsonSize(record)
+		if err != nil {
+			return nil, err
+		}
+		sizes[i] = size
+
+		failedSize, err := jsonSize(failedRecords[i])
+		if err != nil {
+			return nil, err
+		}
+		failedSizes[i] = failedSize
+
+		total += failedSize
+	}
+
+	for i := range records {
+		if total-failedSizes[i]+sizes[i] <= h.responseLimit {
+			total += sizes[i] - failedSizes[i]
+			continue
+		}
+
+		recordLogger := logger.WithFields(map[string]interface{}{
+			"recordId": records[i].RecordID,
+		})
+
+		recordLogger.Error("Failing Firehose record to keep the response under %d bytes", h.responseLimit)
+		records[i] = failedRecords[i]
+	}
+
+	serialized, err := json.Marshal(events.KinesisFirehoseResponse{Records: records})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal Firehose response (%w)", err)
+	}
+
+	return serialized, nil
+}
+
+func jsonSize(v interface{}) (int, error) {
+	serialized, err := json.Marshal(v)
+	if err != nil {
+		return 0, fmt.Errorf("failed to marshal Firehose response (%w)", err)
+	}
+
+	return len(serialized), nil
+}
@@ -0,0 +1,174 @@
+package lambdabase
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/go-nacelle/config/v3"
+	"github.com/go-nacelle/nacelle/v2"
+	"github.com/stretchr/testify/require"
+)
+
+var testFirehosePayload = `{
+	"invocationId": "i1",
+	"deliveryStreamArn": "arn:aws:firehose:us-east-1:123456789012:deliverystream/stream",
+	"region": "us-east-1",
+	"records": [
+		{"recordId": "r1", "approximateArrivalTimestamp": 1636675200000, "data": "Zm9v"},
+		{"recordId": "r2", "approximateArrivalTimestamp": 1636675200000, "data": "YmFy"},
+		{"recordId": "r3", "approximateArrivalTimestamp": 1636675200000, "data": "YmF6"},
+		{"recordId": "r4", "approximateArrivalTimestamp": 1636675200000, "data": "Ym9u"}
+	]
+}`
+
+func TestFirehoseTransformInit(t *testing.T) {
+	handler := &testFirehoseTransformHandler{}
+	outer := &firehoseTransformHandler{
+		handler:  handler,
+		Logger:   nacelle.NewNilLogger(),
+		Services: nacelle.NewServiceContainer(),
+	}
+
+	ctx := context.Background()
+
+	cfg := nacelle.NewConfig(nacelle.NewTestEnvSourcer(nil))
+	ctx = config.WithConfig(ctx, cfg)
+
+	err := outer.Init(ctx)
+	require.Nil(t, err)
+	require.True(t, handler.initialized)
+}
+
+func TestFirehoseTransformBadInjection(t *testing.T) {
+	handler := &badInjectionFirehoseTransformHandler{}
+	outer := &firehoseTransformHandler{
+		handler:  handler,
+		Logger:   nacelle.NewNilLogger(),
+		Services: makeBadContainer(),
+	}
+
+	ctx := context.Background()
+
+	cfg := nacelle.NewConfig(nacelle.NewTestEnvSourcer(nil))
+	ctx = config.WithConfig(ctx, cfg)
+
+	err := outer.Init(ctx)
+	require.NotNil(t, err)
+	require.Contains(t, err.Error(), "ServiceA")
+}
+
+func TestFirehoseTransformInitError(t *testing.T) {
+	handler := &testFirehoseTransformHandler{initErr: fmt.Errorf("oops")}
+	outer := &firehoseTransformHandler{
+		handler:  handler,
+		Logger:   nacelle.NewNilLogger(),
+		Services: nacelle.NewServiceContainer(),
+	}
+
+	ctx := context.Background()
+
+	cfg := nacelle.NewConfig(nacelle.NewTestEnvSourcer(nil))
+	ctx = config.WithConfig(ctx, cfg)
+
+	err := outer.Init(ctx)
+	require.EqualError(t, err, "oops")
+}
+
+func TestFirehoseTransformInvoke(t *testing.T) {
+	handler := &testFirehoseTransformHandler{
+		handle: func(record events.KinesisFirehoseEventRecord) (FirehoseTransformResult, error) {
+			switch record.RecordID {
+			case "r1":
+				return FirehoseOk([]byte(strings.ToUpper(string(record.Data)))), nil
+			case "r2":
+				return FirehoseDropped(), nil
+			case "r3":
+				return FirehoseTransformResult{}, fmt.Errorf("oops")
+			}
+
+			return FirehoseTransformResult{Data: record.Data, PartitionKeys: map[string]string{"type": "bon"}}, nil
+		},
+	}
+	outer := &firehoseTransformHandler{
+		handler:           handler,
+		Logger:            nacelle.NewNilLogger(),
+		recordConcurrency: 1,
+		responseLimit:     firehoseResponseLimit,
+	}
+
+	payload, err := outer.Invoke(context.Background(), []byte(testFirehosePayload))
+	require.Nil(t, err)
+
+	response := events.KinesisFirehoseResponse{}
+	require.Nil(t, json.Unmarshal(payload, &response))
+	require.Equal(t, []events.KinesisFirehoseResponseRecord{
+		{RecordID: "r1", Result: "Ok", Data: []byte("FOO")},
+		{RecordID: "r2", Result: "Dropped"},
+		{RecordID: "r3", Result: "ProcessingFailed"},
+		{RecordID: "r4", Result: "Ok", Data: []byte("bon"), Metadata: events.KinesisFirehoseResponseRecordMetadata{PartitionKeys: map[string]string{"type": "bon"}}},
+	}, response.Records)
+}
+
+func TestFirehoseTransformInvokeResponseLimit(t *testing.T) {
+	handler := &testFirehoseTransformHandler{
+		handle: func(record events.KinesisFirehoseEventRecord) (FirehoseTransformResult, error) {
+			if record.RecordID == "r2" {
+				return FirehoseOk([]byte(strings.Repeat("x", 300))), nil
+			}
+
+			return FirehoseOk(record.Data), nil
+		},
+	}
+	outer := &firehoseTransformHandler{
+		handler:           handler,
+		Logger:            nacelle.NewNilLogger(),
+		recordConcurrency: 2,
+		responseLimit:     400,
+	}
+
+	payload, err := outer.Invoke(context.Background(), []byte(testFirehosePayload))
+	require.Nil(t, err)
+	require.LessOrEqual(t, len(payload), 400)
+
+	response := events.KinesisFirehoseResponse{}
+	require.Nil(t, json.Unmarshal(payload, &response))
+	require.Equal(t, []events.KinesisFirehoseResponseRecord{
+		{RecordID: "r1", Result: "Ok", Data: []byte("foo")},
+		{RecordID: "r2", Result: "ProcessingFailed"},
+		{RecordID: "r3", Result: "Ok", Data: []byte("baz")},
+		{RecordID: "r4", Result: "Ok", Data: []byte("bon")},
+	}, response.Records)
+}
+
+//
+// Helpers
+
+type testFirehoseTransformHandler struct {
+	initErr     error
+	initialized bool
+	handle      func(record events.KinesisFirehoseEventRecord) (FirehoseTransformResult, error)
+}
+
+func (h *testFirehoseTransformHandler) Init(ctx context.Context) error {
+	h.initialized = true
+	return h.initErr
+}
+
+func (h *testFirehoseTransformHandler) Handle(ctx context.Context, record events.KinesisFirehoseEventRecord, logger nacelle.Logger) (FirehoseTransformResult, error) {
+	return h.handle(record)
+}
+
+//
+// Bad Injection
+
+type badInjectionFirehoseTransformHandler struct {
+	ServiceA *A `service:"A"`
+}
+
+func (i *badInjectionFirehoseTransformHandler) Handle(ctx context.Context, record events.KinesisFirehoseEventRecord, logger nacelle.Logger) (FirehoseTransformResult, error) {
+	return FirehoseTransformResult{}, nil
+}
@@ -3,7 +3,7 @@ module github.com/go-nacelle/lambdabase
 go 1.18
 
 require (
-	github.com/aws/aws-lambda-go v1.11.1
+	github.com/aws/aws-lambda-go v1.47.0
 	github.com/derision-test/go-mockgen v1.3.7
 	github.com/go-nacelle/config/v3 v3.0.0
 	github.com/go-nacelle/log/v2 v2.0.1
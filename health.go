@@ -1,7 +1,12 @@
 package lambdabase
 
-type healthToken string
+type healthToken struct {
+	id   string
+	name string
+}
+
+const defaultHealthTokenName = "lambda-init"
 
 func (t healthToken) String() string {
-	return "lambda-init"
+	return t.name
 }
@@ -0,0 +1,158 @@
+package lambdabase
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"mime"
+	"net/http"
+	"net/url"
+	"strings"
+	"unicode/utf8"
+)
+
+type httpResponseWriter struct {
+	header     http.Header
+	body       bytes.Buffer
+	statusCode int
+}
+
+func newHTTPResponseWriter() *httpResponseWriter {
+	return &httpResponseWriter{header: http.Header{}}
+}
+
+func (w *httpResponseWriter) Header() http.Header {
+	return w.header
+}
+
+func (w *httpResponseWriter) WriteHeader(statusCode int) {
+	if w.statusCode == 0 {
+		w.statusCode = statusCode
+	}
+}
+
+func (w *httpResponseWriter) Write(data []byte) (int, error) {
+	if w.statusCode == 0 {
+		w.WriteHeader(http.StatusOK)
+	}
+
+	if w.header.Get("Content-Type") == "" && w.body.Len() == 0 && len(data) > 0 {
+		w.header.Set("Content-Type", http.DetectContentType(data))
+	}
+
+	return w.body.Write(data)
+}
+
+func (w *httpResponseWriter) status() int {
+	if w.statusCode == 0 {
+		return http.StatusOK
+	}
+
+	return w.statusCode
+}
+
+// encodedBody returns the response body and whether or not the body is
+// base64-encoded. Bodies that are not valid UTF-8 text must be encoded to
+// survive serialization of the Lambda response.
+func (w *httpResponseWriter) encodedBody() (string, bool) {
+	body := w.body.Bytes()
+	if len(body) == 0 || (isTextContentType(w.header.Get("Content-Type")) && utf8.Valid(body)) {
+		return string(body), false
+	}
+
+	return base64.StdEncoding.EncodeToString(body), true
+}
+
+func (w *httpResponseWriter) singleValueHeaders() map[string]string {
+	headers := make(map[string]string, len(w.header))
+	for name, values := range w.header {
+		headers[name] = strings.Join(values, ", ")
+	}
+
+	return headers
+}
+
+func (w *httpResponseWriter) multiValueHeaders() map[string][]string {
+	headers := make(map[string][]string, len(w.header))
+	for name, values := range w.header {
+		headers[name] = values
+	}
+
+	return headers
+}
+
+func newHTTPRequest(ctx context.Context, method string, u *url.URL, header http.Header, body string, isBase64Encoded bool, remoteAddr string) (*http.Request, error) {
+	decoded := []byte(body)
+	if isBase64Encoded {
+		var err error
+		if decoded, err = base64.StdEncoding.DecodeString(body); err != nil {
+			return nil, fmt.Errorf("failed to decode request body (%w)", err)
+		}
+	}
+
+	r, err := http.NewRequestWithContext(ctx, method, u.String(), bytes.NewReader(decoded))
+	if err != nil {
+		return nil, fmt.Errorf("failed to construct request (%w)", err)
+	}
+
+	r.Header = header
+	r.Host = header.Get("Host")
+	r.RequestURI = u.RequestURI()
+	r.RemoteAddr = remoteAddr
+	return r, nil
+}
+
+func makeHeader(headers map[string]string, multiValueHeaders map[string][]string) http.Header {
+	header := http.Header{}
+	if len(multiValueHeaders) > 0 {
+		for name, values := range multiValueHeaders {
+			for _, value := range values {
+				header.Add(name, value)
+			}
+		}
+
+		return header
+	}
+
+	for name, value := range headers {
+		header.Add(name, value)
+	}
+
+	return header
+}
+
+func makeQuery(parameters map[string]string, multiValueParameters map[string][]string) string {
+	query := url.Values{}
+	if len(multiValueParameters) > 0 {
+		for name, values := range multiValueParameters {
+			query[name] = values
+		}
+
+		return query.Encode()
+	}
+
+	for name, value := range parameters {
+		query.Set(name, value)
+	}
+
+	return query.Encode()
+}
+
+func isTextContentType(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+
+	if strings.HasPrefix(mediaType, "text/") || strings.HasSuffix(mediaType, "+json") || strings.HasSuffix(mediaType, "+xml") {
+		return true
+	}
+
+	switch mediaType {
+	case "application/json", "application/xml", "application/javascript", "application/x-www-form-urlencoded":
+		return true
+	}
+
+	return false
+}
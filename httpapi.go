@@ -0,0 +1,85 @@
+package lambdabase
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/go-nacelle/nacelle/v2"
+)
+
+type httpAPIHandler struct {
+	Logger   nacelle.Logger            `service:"logger"`
+	Services *nacelle.ServiceContainer `service:"services"`
+	handler  http.Handler
+}
+
+func NewHTTPAPIServer(handler http.Handler, configs ...ConfigFunc) *Server {
+	return NewServer(&httpAPIHandler{
+		handler: handler,
+	}, configs...)
+}
+
+func (h *httpAPIHandler) Init(ctx context.Context) error {
+	return doInit(ctx, h.Services, h.handler)
+}
+
+func (h *httpAPIHandler) Invoke(ctx context.Context, payload []byte) ([]byte, error) {
+	event := events.APIGatewayV2HTTPRequest{}
+	if err := json.Unmarshal(payload, &event); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal event (%w)", err)
+	}
+
+	logger := h.Logger.WithFields(map[string]interface{}{
+		"requestId": GetRequestID(ctx),
+	})
+
+	logger.Debug("Received HTTP API request %s %s", event.RequestContext.HTTP.Method, event.RawPath)
+
+	u, err := url.Parse(event.RawPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse request path (%w)", err)
+	}
+	u.RawQuery = event.RawQueryString
+
+	// The v2 payload joins repeated headers with commas and moves the
+	// request cookies out of the headers entirely.
+	header := makeHeader(event.Headers, nil)
+	if len(event.Cookies) > 0 {
+		header.Set("Cookie", strings.Join(event.Cookies, "; "))
+	}
+
+	r, err := newHTTPRequest(
+		withHTTPAPIRequest(ctx, event),
+		event.RequestContext.HTTP.Method,
+		u,
+		header,
+		event.Body,
+		event.IsBase64Encoded,
+		event.RequestContext.HTTP.SourceIP,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	w := newHTTPResponseWriter()
+	h.handler.ServeHTTP(w, r)
+	body, isBase64Encoded := w.encodedBody()
+
+	cookies := w.header.Values("Set-Cookie")
+	w.header.Del("Set-Cookie")
+
+	logger.Debug("HTTP API request handled with status %d", w.status())
+
+	return json.Marshal(events.APIGatewayV2HTTPResponse{
+		StatusCode:      w.status(),
+		Headers:         w.singleValueHeaders(),
+		Body:            body,
+		IsBase64Encoded: isBase64Encoded,
+		Cookies:         cookies,
+	})
+}
@@ -0,0 +1,89 @@
+package lambdabase
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/go-nacelle/config/v3"
+	"github.com/go-nacelle/nacelle/v2"
+	"github.com/stretchr/testify/require"
+)
+
+var testHTTPAPIPayload = `{
+	"version": "2.0",
+	"routeKey": "POST /widgets/{id}",
+	"rawPath": "/widgets/a%2Fb",
+	"rawQueryString": "color=red&color=blue",
+	"cookies": ["session=abc", "theme=dark"],
+	"headers": {"content-type": "application/json", "x-tag": "a,b"},
+	"pathParameters": {"id": "a/b"},
+	"requestContext": {
+		"requestId": "r1",
+		"http": {"method": "POST", "path": "/widgets/a/b", "sourceIp": "10.0.0.1"}
+	},
+	"body": "{\"name\": \"foo\"}",
+	"isBase64Encoded": false
+}`
+
+func TestHTTPAPIInit(t *testing.T) {
+	handler := &testInitHTTPHandler{}
+	outer := &httpAPIHandler{
+		handler:  handler,
+		Logger:   nacelle.NewNilLogger(),
+		Services: nacelle.NewServiceContainer(),
+	}
+
+	ctx := context.Background()
+
+	cfg := nacelle.NewConfig(nacelle.NewTestEnvSourcer(nil))
+	ctx = config.WithConfig(ctx, cfg)
+
+	err := outer.Init(ctx)
+	require.Nil(t, err)
+	require.True(t, handler.initialized)
+}
+
+func TestHTTPAPIInvoke(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		event, ok := GetHTTPAPIRequest(r.Context())
+		session, _ := r.Cookie("session")
+		theme, _ := r.Cookie("theme")
+
+		http.SetCookie(w, &http.Cookie{Name: "a", Value: "1"})
+		http.SetCookie(w, &http.Cookie{Name: "b", Value: "2"})
+		w.Header().Add("X-Echo", "x")
+		w.Header().Add("X-Echo", "y")
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"path": %q, "color": %q, "ok": %v, "id": %q, "session": %q, "theme": %q, "body": %q, "type": %q}`,
+			r.URL.EscapedPath(), fmt.Sprint(r.URL.Query()["color"]), ok, event.PathParameters["id"], session.Value, theme.Value, body, r.Header.Get("Content-Type"))
+	})
+
+	outer := &httpAPIHandler{handler: handler, Logger: nacelle.NewNilLogger()}
+
+	payload, err := outer.Invoke(context.Background(), []byte(testHTTPAPIPayload))
+	require.Nil(t, err)
+
+	response := events.APIGatewayV2HTTPResponse{}
+	require.Nil(t, json.Unmarshal(payload, &response))
+	require.Equal(t, http.StatusOK, response.StatusCode)
+	require.Equal(t, []string{"a=1", "b=2"}, response.Cookies)
+	require.Equal(t, "x, y", response.Headers["X-Echo"])
+	require.NotContains(t, response.Headers, "Set-Cookie")
+	require.False(t, response.IsBase64Encoded)
+	require.JSONEq(t, `{
+		"path": "/widgets/a%2Fb",
+		"color": "[red blue]",
+		"ok": true,
+		"id": "a/b",
+		"session": "abc",
+		"theme": "dark",
+		"body": "{\"name\": \"foo\"}",
+		"type": "application/json"
+	}`, response.Body)
+}
@@ -0,0 +1,62 @@
+package lambdabase
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/go-nacelle/nacelle/v2"
+)
+
+type (
+	KafkaEventHandler interface {
+		Handle(ctx context.Context, event events.KafkaEvent, logger nacelle.Logger) error
+	}
+
+	kafkaEventHandlerInitializer interface {
+		nacelle.Initializer
+		KafkaEventHandler
+	}
+
+	kafkaEventHandler struct {
+		Logger   nacelle.Logger            `service:"logger"`
+		Services *nacelle.ServiceContainer `service:"services"`
+		handler  KafkaEventHandler
+	}
+)
+
+func NewKafkaEventServer(handler KafkaEventHandler, configs ...ConfigFunc) *Server {
+	return NewServer(&kafkaEventHandler{
+		handler: handler,
+	}, configs...)
+}
+
+func (h *kafkaEventHandler) Init(ctx context.Context) error {
+	return doInit(ctx, h.Services, h.handler)
+}
+
+func (h *kafkaEventHandler) Invoke(ctx context.Context, payload []byte) ([]byte, error) {
+	event := events.KafkaEvent{}
+	if err := json.Unmarshal(payload, &event); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal event (%w)", err)
+	}
+
+	logger := h.Logger.WithFields(map[string]interface{}{
+		"requestId": GetRequestID(ctx),
+	})
+
+	n := 0
+	for _, records := range event.Records {
+		n += len(records)
+	}
+
+	logger.Debug("Received %d Kafka records from %d partitions", n, len(event.Records))
+
+	if err := h.handler.Handle(ctx, event, logger); err != nil {
+		return nil, fmt.Errorf("failed to process Kafka event (%w)", err)
+	}
+
+	logger.Debug("Kafka event handled successfully")
+	return nil, nil
+}
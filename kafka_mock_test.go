@@ -0,0 +1,587 @@
+// Code generated by go-mockgen 1.3.5; DO NOT EDIT.
+
+package lambdabase
+
+import (
+	"context"
+	"sync"
+
+	events "github.com/aws/aws-lambda-go/events"
+	v2 "github.com/go-nacelle/log/v2"
+)
+
+// MockKafkaEventHandlerInitializer is a mock implementation of the
+// kafkaEventHandlerInitializer interface (from the package
+// github.com/go-nacelle/lambdabase) used for unit testing.
+type MockKafkaEventHandlerInitializer struct {
+	// HandleFunc is an instance of a mock function object controlling the
+	// behavior of the method Handle.
+	HandleFunc *KafkaEventHandlerInitializerHandleFunc
+	// InitFunc is an instance of a mock function object controlling the
+	// behavior of the method Init.
+	InitFunc *KafkaEventHandlerInitializerInitFunc
+}
+
+// NewMockKafkaEventHandlerInitializer creates a new mock of the
+// kafkaEventHandlerInitializer interface. All methods return zero values
+// for all results, unless overwritten.
+func NewMockKafkaEventHandlerInitializer() *MockKafkaEventHandlerInitializer {
+	return &MockKafkaEventHandlerInitializer{
+		HandleFunc: &KafkaEventHandlerInitializerHandleFunc{
+			defaultHook: func(context.Context, events.KafkaEvent, v2.Logger) (r0 error) {
+				return
+			},
+		},
+		InitFunc: &KafkaEventHandlerInitializerInitFunc{
+			defaultHook: func(context.Context) (r0 error) {
+				return
+			},
+		},
+	}
+}
+
+// NewStrictMockKafkaEventHandlerInitializer creates a new mock of the
+// kafkaEventHandlerInitializer interface. All methods panic on
+// invocation, unless overwritten.
+func NewStrictMockKafkaEventHandlerInitializer() *MockKafkaEventHandlerInitializer {
+	return &MockKafkaEventHandlerInitializer{
+		HandleFunc: &KafkaEventHandlerInitializerHandleFunc{
+			defaultHook: func(context.Context, events.KafkaEvent, v2.Logger) error {
+				panic("unexpected invocation of MockKafkaEventHandlerInitializer.Handle")
+			},
+		},
+		InitFunc: &KafkaEventHandlerInitializerInitFunc{
+			defaultHook: func(context.Context) error {
+				panic("unexpected invocation of MockKafkaEventHandlerInitializer.Init")
+			},
+		},
+	}
+}
+
+// surrogateMockKafkaEventHandlerInitializer is a copy of the
+// kafkaEventHandlerInitializer interface (from the package
+// github.com/go-nacelle/lambdabase). It is redefined here as it is
+// unexported in the source package.
+type surrogateMockKafkaEventHandlerInitializer interface {
+	Handle(context.Context, events.KafkaEvent, v2.Logger) error
+	Init(context.Context) error
+}
+
+// NewMockKafkaEventHandlerInitializerFrom creates a new mock of the
+// MockKafkaEventHandlerInitializer interface. All methods delegate to the
+// given implementation, unless overwritten.
+func NewMockKafkaEventHandlerInitializerFrom(i surrogateMockKafkaEventHandlerInitializer) *MockKafkaEventHandlerInitializer {
+	return &MockKafkaEventHandlerInitializer{
+		HandleFunc: &KafkaEventHandlerInitializerHandleFunc{
+			defaultHook: i.Handle,
+		},
+		InitFunc: &KafkaEventHandlerInitializerInitFunc{
+			defaultHook: i.Init,
+		},
+	}
+}
+
+// KafkaEventHandlerInitializerHandleFunc describes the behavior when the
+// Handle method of the parent MockKafkaEventHandlerInitializer instance
+// is invoked.
+type KafkaEventHandlerInitializerHandleFunc struct {
+	defaultHook func(context.Context, events.KafkaEvent, v2.Logger) error
+	hooks       []func(context.Context, events.KafkaEvent, v2.Logger) error
+	history     []KafkaEventHandlerInitializerHandleFuncCall
+	mutex       sync.Mutex
+}
+
+// Handle delegates to the next hook function in the queue and stores the
+// parameter and result values of this invocation.
+func (m *MockKafkaEventHandlerInitializer) Handle(v0 context.Context, v1 events.KafkaEvent, v2 v2.Logger) error {
+	r0 := m.HandleFunc.nextHook()(v0, v1, v2)
+	m.HandleFunc.appendCall(KafkaEventHandlerInitializerHandleFuncCall{v0, v1, v2, r0})
+	return r0
+}
+
+// SetDefaultHook sets function that is called when the Handle method of the
+// parent MockKafkaEventHandlerInitializer instance is invoked and the
+// hook queue is empty.
+func (f *KafkaEventHandlerInitializerHandleFunc) SetDefaultHook(hook func(context.Context, events.KafkaEvent, v2.Logger) error) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// Handle method of the parent MockKafkaEventHandlerInitializer instance
+// invokes the hook at the front of the queue and discards it. After the
+// queue is empty, the default hook function is invoked for any future
+// action.
+func (f *KafkaEventHandlerInitializerHandleFunc) PushHook(hook func(context.Context, events.KafkaEvent, v2.Logger) error) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultHook with a function that returns the
+// given values.
+func (f *KafkaEventHandlerInitializerHandleFunc) SetDefaultReturn(r0 error) {
+	f.SetDefaultHook(func(context.Context, events.KafkaEvent, v2.Logger) error {
+		return r0
+	})
+}
+
+// PushReturn calls PushHook with a function that returns the given values.
+func (f *KafkaEventHandlerInitializerHandleFunc) PushReturn(r0 error) {
+	f.PushHook(func(context.Context, events.KafkaEvent, v2.Logger) error {
+		return r0
+	})
+}
+
+func (f *KafkaEventHandlerInitializerHandleFunc) nextHook() func(context.Context, events.KafkaEvent, v2.Logger) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *KafkaEventHandlerInitializerHandleFunc) appendCall(r0 KafkaEventHandlerInitializerHandleFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of
+// KafkaEventHandlerInitializerHandleFuncCall objects describing the
+// invocations of this function.
+func (f *KafkaEventHandlerInitializerHandleFunc) History() []KafkaEventHandlerInitializerHandleFuncCall {
+	f.mutex.Lock()
+	history := make([]KafkaEventHandlerInitializerHandleFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// KafkaEventHandlerInitializerHandleFuncCall is an object that describes
+// an invocation of method Handle on an instance of
+// MockKafkaEventHandlerInitializer.
+type KafkaEventHandlerInitializerHandleFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 events.KafkaEvent
+	// Arg2 is the value of the 3rd argument passed to this method
+	// invocation.
+	Arg2 v2.Logger
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c KafkaEventHandlerInitializerHandleFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1, c.Arg2}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c KafkaEventHandlerInitializerHandleFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0}
+}
+
+// KafkaEventHandlerInitializerInitFunc describes the behavior when the
+// Init method of the parent MockKafkaEventHandlerInitializer instance is
+// invoked.
+type KafkaEventHandlerInitializerInitFunc struct {
+	defaultHook func(context.Context) error
+	hooks       []func(context.Context) error
+	history     []KafkaEventHandlerInitializerInitFuncCall
+	mutex       sync.Mutex
+}
+
+// Init delegates to the next hook function in the queue and stores the
+// parameter and result values of this invocation.
+func (m *MockKafkaEventHandlerInitializer) Init(v0 context.Context) error {
+	r0 := m.InitFunc.nextHook()(v0)
+	m.InitFunc.appendCall(KafkaEventHandlerInitializerInitFuncCall{v0, r0})
+	return r0
+}
+
+// SetDefaultHook sets function that is called when the Init method of the
+// parent MockKafkaEventHandlerInitializer instance is invoked and the
+// hook queue is empty.
+func (f *KafkaEventHandlerInitializerInitFunc) SetDefaultHook(hook func(context.Context) error) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// Init method of the parent MockKafkaEventHandlerInitializer instance
+// invokes the hook at the front of the queue and discards it. After the
+// queue is empty, the default hook function is invoked for any future
+// action.
+func (f *KafkaEventHandlerInitializerInitFunc) PushHook(hook func(context.Context) error) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultHook with a function that returns the
+// given values.
+func (f *KafkaEventHandlerInitializerInitFunc) SetDefaultReturn(r0 error) {
+	f.SetDefaultHook(func(context.Context) error {
+		return r0
+	})
+}
+
+// PushReturn calls PushHook with a function that returns the given values.
+func (f *KafkaEventHandlerInitializerInitFunc) PushReturn(r0 error) {
+	f.PushHook(func(context.Context) error {
+		return r0
+	})
+}
+
+func (f *KafkaEventHandlerInitializerInitFunc) nextHook() func(context.Context) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *KafkaEventHandlerInitializerInitFunc) appendCall(r0 KafkaEventHandlerInitializerInitFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of KafkaEventHandlerInitializerInitFuncCall
+// objects describing the invocations of this function.
+func (f *KafkaEventHandlerInitializerInitFunc) History() []KafkaEventHandlerInitializerInitFuncCall {
+	f.mutex.Lock()
+	history := make([]KafkaEventHandlerInitializerInitFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// KafkaEventHandlerInitializerInitFuncCall is an object that describes an
+// invocation of method Init on an instance of
+// MockKafkaEventHandlerInitializer.
+type KafkaEventHandlerInitializerInitFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c KafkaEventHandlerInitializerInitFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c KafkaEventHandlerInitializerInitFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0}
+}
+
+// MockKafkaRecordHandlerInitializer is a mock implementation of the
+// kafkaRecordHandlerInitializer interface (from the package
+// github.com/go-nacelle/lambdabase) used for unit testing.
+type MockKafkaRecordHandlerInitializer struct {
+	// HandleFunc is an instance of a mock function object controlling the
+	// behavior of the method Handle.
+	HandleFunc *KafkaRecordHandlerInitializerHandleFunc
+	// InitFunc is an instance of a mock function object controlling the
+	// behavior of the method Init.
+	InitFunc *KafkaRecordHandlerInitializerInitFunc
+}
+
+// NewMockKafkaRecordHandlerInitializer creates a new mock of the
+// kafkaRecordHandlerInitializer interface. All methods return zero values
+// for all results, unless overwritten.
+func NewMockKafkaRecordHandlerInitializer() *MockKafkaRecordHandlerInitializer {
+	return &MockKafkaRecordHandlerInitializer{
+		HandleFunc: &KafkaRecordHandlerInitializerHandleFunc{
+			defaultHook: func(context.Context, KafkaMessage, v2.Logger) (r0 error) {
+				return
+			},
+		},
+		InitFunc: &KafkaRecordHandlerInitializerInitFunc{
+			defaultHook: func(context.Context) (r0 error) {
+				return
+			},
+		},
+	}
+}
+
+// NewStrictMockKafkaRecordHandlerInitializer creates a new mock of the
+// kafkaRecordHandlerInitializer interface. All methods panic on
+// invocation, unless overwritten.
+func NewStrictMockKafkaRecordHandlerInitializer() *MockKafkaRecordHandlerInitializer {
+	return &MockKafkaRecordHandlerInitializer{
+		HandleFunc: &KafkaRecordHandlerInitializerHandleFunc{
+			defaultHook: func(context.Context, KafkaMessage, v2.Logger) error {
+				panic("unexpected invocation of MockKafkaRecordHandlerInitializer.Handle")
+			},
+		},
+		InitFunc: &KafkaRecordHandlerInitializerInitFunc{
+			defaultHook: func(context.Context) error {
+				panic("unexpected invocation of MockKafkaRecordHandlerInitializer.Init")
+			},
+		},
+	}
+}
+
+// surrogateMockKafkaRecordHandlerInitializer is a copy of the
+// kafkaRecordHandlerInitializer interface (from the package
+// github.com/go-nacelle/lambdabase). It is redefined here as it is
+// unexported in the source package.
+type surrogateMockKafkaRecordHandlerInitializer interface {
+	Handle(context.Context, KafkaMessage, v2.Logger) error
+	Init(context.Context) error
+}
+
+// NewMockKafkaRecordHandlerInitializerFrom creates a new mock of the
+// MockKafkaRecordHandlerInitializer interface. All methods delegate to
+// the given implementation, unless overwritten.
+func NewMockKafkaRecordHandlerInitializerFrom(i surrogateMockKafkaRecordHandlerInitializer) *MockKafkaRecordHandlerInitializer {
+	return &MockKafkaRecordHandlerInitializer{
+		HandleFunc: &KafkaRecordHandlerInitializerHandleFunc{
+			defaultHook: i.Handle,
+		},
+		InitFunc: &KafkaRecordHandlerInitializerInitFunc{
+			defaultHook: i.Init,
+		},
+	}
+}
+
+// KafkaRecordHandlerInitializerHandleFunc describes the behavior when the
+// Handle method of the parent MockKafkaRecordHandlerInitializer instance
+// is invoked.
+type KafkaRecordHandlerInitializerHandleFunc struct {
+	defaultHook func(context.Context, KafkaMessage, v2.Logger) error
+	hooks       []func(context.Context, KafkaMessage, v2.Logger) error
+	history     []KafkaRecordHandlerInitializerHandleFuncCall
+	mutex       sync.Mutex
+}
+
+// Handle delegates to the next hook function in the queue and stores the
+// parameter and result values of this invocation.
+func (m *MockKafkaRecordHandlerInitializer) Handle(v0 context.Context, v1 KafkaMessage, v2 v2.Logger) error {
+	r0 := m.HandleFunc.nextHook()(v0, v1, v2)
+	m.HandleFunc.appendCall(KafkaRecordHandlerInitializerHandleFuncCall{v0, v1, v2, r0})
+	return r0
+}
+
+// SetDefaultHook sets function that is called when the Handle method of the
+// parent MockKafkaRecordHandlerInitializer instance is invoked and the
+// hook queue is empty.
+func (f *KafkaRecordHandlerInitializerHandleFunc) SetDefaultHook(hook func(context.Context, KafkaMessage, v2.Logger) error) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// Handle method of the parent MockKafkaRecordHandlerInitializer instance
+// invokes the hook at the front of the queue and discards it. After the
+// queue is empty, the default hook function is invoked for any future
+// action.
+func (f *KafkaRecordHandlerInitializerHandleFunc) PushHook(hook func(context.Context, KafkaMessage, v2.Logger) error) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultHook with a function that returns the
+// given values.
+func (f *KafkaRecordHandlerInitializerHandleFunc) SetDefaultReturn(r0 error) {
+	f.SetDefaultHook(func(context.Context, KafkaMessage, v2.Logger) error {
+		return r0
+	})
+}
+
+// PushReturn calls PushHook with a function that returns the given values.
+func (f *KafkaRecordHandlerInitializerHandleFunc) PushReturn(r0 error) {
+	f.PushHook(func(context.Context, KafkaMessage, v2.Logger) error {
+		return r0
+	})
+}
+
+func (f *KafkaRecordHandlerInitializerHandleFunc) nextHook() func(context.Context, KafkaMessage, v2.Logger) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *KafkaRecordHandlerInitializerHandleFunc) appendCall(r0 KafkaRecordHandlerInitializerHandleFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of
+// KafkaRecordHandlerInitializerHandleFuncCall objects describing the
+// invocations of this function.
+func (f *KafkaRecordHandlerInitializerHandleFunc) History() []KafkaRecordHandlerInitializerHandleFuncCall {
+	f.mutex.Lock()
+	history := make([]KafkaRecordHandlerInitializerHandleFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// KafkaRecordHandlerInitializerHandleFuncCall is an object that describes
+// an invocation of method Handle on an instance of
+// MockKafkaRecordHandlerInitializer.
+type KafkaRecordHandlerInitializerHandleFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 KafkaMessage
+	// Arg2 is the value of the 3rd argument passed to this method
+	// invocation.
+	Arg2 v2.Logger
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c KafkaRecordHandlerInitializerHandleFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1, c.Arg2}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c KafkaRecordHandlerInitializerHandleFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0}
+}
+
+// KafkaRecordHandlerInitializerInitFunc describes the behavior when the
+// Init method of the parent MockKafkaRecordHandlerInitializer instance is
+// invoked.
+type KafkaRecordHandlerInitializerInitFunc struct {
+	defaultHook func(context.Context) error
+	hooks       []func(context.Context) error
+	history     []KafkaRecordHandlerInitializerInitFuncCall
+	mutex       sync.Mutex
+}
+
+// Init delegates to the next hook function in the queue and stores the
+// parameter and result values of this invocation.
+func (m *MockKafkaRecordHandlerInitializer) Init(v0 context.Context) error {
+	r0 := m.InitFunc.nextHook()(v0)
+	m.InitFunc.appendCall(KafkaRecordHandlerInitializerInitFuncCall{v0, r0})
+	return r0
+}
+
+// SetDefaultHook sets function that is called when the Init method of the
+// parent MockKafkaRecordHandlerInitializer instance is invoked and the
+// hook queue is empty.
+func (f *KafkaRecordHandlerInitializerInitFunc) SetDefaultHook(hook func(context.Context) error) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// Init method of the parent MockKafkaRecordHandlerInitializer instance
+// invokes the hook at the front of the queue and discards it. After the
+// queue is empty, the default hook function is invoked for any future
+// action.
+func (f *KafkaRecordHandlerInitializerInitFunc) PushHook(hook func(context.Context) error) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultHook with a function that returns the
+// given values.
+func (f *KafkaRecordHandlerInitializerInitFunc) SetDefaultReturn(r0 error) {
+	f.SetDefaultHook(func(context.Context) error {
+		return r0
+	})
+}
+
+// PushReturn calls PushHook with a function that returns the given values.
+func (f *KafkaRecordHandlerInitializerInitFunc) PushReturn(r0 error) {
+	f.PushHook(func(context.Context) error {
+		return r0
+	})
+}
+
+func (f *KafkaRecordHandlerInitializerInitFunc) nextHook() func(context.Context) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *KafkaRecordHandlerInitializerInitFunc) appendCall(r0 KafkaRecordHandlerInitializerInitFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of KafkaRecordHandlerInitializerInitFuncCall
+// objects describing the invocations of this function.
+func (f *KafkaRecordHandlerInitializerInitFunc) History() []KafkaRecordHandlerInitializerInitFuncCall {
+	f.mutex.Lock()
+	history := make([]KafkaRecordHandlerInitializerInitFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// KafkaRecordHandlerInitializerInitFuncCall is an object that describes
+// an invocation of method Init on an instance of
+// MockKafkaRecordHandlerInitializer.
+type KafkaRecordHandlerInitializerInitFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c KafkaRecordHandlerInitializerInitFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c KafkaRecordHandlerInitializerInitFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0}
+}
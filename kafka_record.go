@@ -0,0 +1,157 @@
+package lambdabase
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/go-nacelle/nacelle/v2"
+)
+
+type (
+	KafkaRecordHandler interface {
+		Handle(ctx context.Context, message KafkaMessage, logger nacelle.Logger) error
+	}
+
+	kafkaRecordHandlerInitializer interface {
+		nacelle.Initializer
+		KafkaRecordHandler
+	}
+
+	// KafkaMessage is a Kafka record with its key, value, and headers
+	// decoded from the encoding used by the Lambda event payload.
+	KafkaMessage struct {
+		Topic         string
+		Partition     int64
+		Offset        int64
+		Timestamp     time.Time
+		TimestampType string
+		Key           []byte
+		Value         []byte
+		Headers       []KafkaHeader
+	}
+
+	KafkaHeader struct {
+		Key   string
+		Value []byte
+	}
+
+	kafkaRecordHandler struct {
+		Services          *nacelle.ServiceContainer `service:"services"`
+		handler           KafkaRecordHandler
+		recordConcurrency int
+		recordMiddleware  []RecordMiddleware
+	}
+)
+
+func NewKafkaRecordServer(handler KafkaRecordHandler, configs ...ConfigFunc) *Server {
+	options := getOptions(configs)
+
+	return NewKafkaEventServer(&kafkaRecordHandler{
+		handler:           handler,
+		recordConcurrency: options.recordConcurrency,
+		recordMiddleware:  options.recordMiddleware,
+	}, configs...)
+}
+
+func (s *kafkaRecordHandler) Init(ctx context.Context) error {
+	recordConcurrency, err := loadRecordConcurrency(ctx, s.recordConcurrency)
+	if err != nil {
+		return err
+	}
+	s.recordConcurrency = recordConcurrency
+
+	return doInit(ctx, s.Services, s.handler)
+}
+
+func (h *kafkaRecordHandler) Handle(ctx context.Context, event events.KafkaEvent, logger nacelle.Logger) error {
+	keys, records := sortKafkaRecords(event)
+
+	errs := processBatch(keys, h.recordConcurrency, true, func(i int) error {
+		recordLogger := logger.WithFields(map[string]interface{}{
+			"topic":     records[i].Topic,
+			"partition": records[i].Partition,
+			"offset":    records[i].Offset,
+		})
+
+		message, err := decodeKafkaRecord(records[i])
+		if err != nil {
+			return err
+		}
+
+		recordLogger.Debug("Handling record")
+		return invokeRecordHandler(ctx, h.recordMiddleware, message, recordLogger, h.handler.Handle)
+	})
+
+	if i := firstFailure(errs); i >= 0 {
+		return newRecordError("Kafka", "record", fmt.Sprintf("%s-%d@%d", records[i].Topic, records[i].Partition, records[i].Offset), errs[i])
+	}
+
+	logger.Debug("Kafka record handled successfully")
+	return nil
+}
+
+// sortKafkaRecords flattens the records of the given event, which are keyed
+// by topic-partition, into a single list ordered by topic, partition, and
+// offset. The returned keys identify the topic-partition of each record so
+// that records of the same partition are always handled in offset order.
+func sortKafkaRecords(event events.KafkaEvent) ([]string, []events.KafkaRecord) {
+	records := []events.KafkaRecord{}
+	for _, partitionRecords := range event.Records {
+		records = append(records, partitionRecords...)
+	}
+
+	sort.SliceStable(records, func(i, j int) bool {
+		if records[i].Topic != records[j].Topic {
+			return records[i].Topic < records[j].Topic
+		}
+
+		if records[i].Partition != records[j].Partition {
+			return records[i].Partition < records[j].Partition
+		}
+
+		return records[i].Offset < records[j].Offset
+	})
+
+	keys := make([]string, 0, len(records))
+	for _, record := range records {
+		keys = append(keys, fmt.Sprintf("%s-%d", record.Topic, record.Partition))
+	}
+
+	return keys, records
+}
+
+func decodeKafkaRecord(record events.KafkaRecord) (KafkaMessage, error) {
+	key, err := base64.StdEncoding.DecodeString(record.Key)
+	if err != nil {
+		return KafkaMessage{}, &DecodeError{Part: "Kafka record key", Err: err}
+	}
+
+	value, err := base64.StdEncoding.DecodeString(record.Value)
+	if err != nil {
+		return KafkaMessage{}, &DecodeError{Part: "Kafka record value", Err: err}
+	}
+
+	// Each header is delivered as a single-entry map so that duplicate
+	// header keys and header order are preserved.
+	headers := []KafkaHeader{}
+	for _, header := range record.Headers {
+		for key, value := range header {
+			headers = append(headers, KafkaHeader{Key: key, Value: []byte(value)})
+		}
+	}
+
+	return KafkaMessage{
+		Topic:         record.Topic,
+		Partition:     record.Partition,
+		Offset:        record.Offset,
+		Timestamp:     record.Timestamp.Time,
+		TimestampType: record.TimestampType,
+		Key:           key,
+		Value:         value,
+		Headers:       headers,
+	}, nil
+}
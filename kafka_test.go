@@ -0,0 +1,275 @@
+package lambdabase
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-lambda-go/events"
+	mockassert "github.com/derision-test/go-mockgen/testutil/assert"
+	"github.com/go-nacelle/config/v3"
+	"github.com/go-nacelle/nacelle/v2"
+	"github.com/stretchr/testify/require"
+)
+
+var testKafkaPayload = `{
+	"eventSource": "aws:kafka",
+	"eventSourceArn": "arn:aws:kafka:us-east-1:123456789012:cluster/cluster/uuid",
+	"bootstrapServers": "b-1.example.com:9092",
+	"records": {
+		"orders-1": [
+			{"topic": "orders", "partition": 1, "offset": 16, "timestamp": 1545084650987, "timestampType": "CREATE_TIME", "key": "YjE=", "value": "eDM=", "headers": []}
+		],
+		"orders-0": [
+			{"topic": "orders", "partition": 0, "offset": 16, "timestamp": 1545084650987, "timestampType": "CREATE_TIME", "key": "YTE=", "value": "eDI=", "headers": []},
+			{"topic": "orders", "partition": 0, "offset": 15, "timestamp": 1545084650987, "timestampType": "CREATE_TIME", "key": "YTE=", "value": "eDE=", "headers": [{"trace": [116, 49]}, {"trace": [116, 50]}]}
+		]
+	}
+}`
+
+func TestKafkaEventInit(t *testing.T) {
+	handler := NewMockKafkaEventHandlerInitializer()
+	outer := &kafkaEventHandler{
+		handler:  handler,
+		Logger:   nacelle.NewNilLogger(),
+		Services: nacelle.NewServiceContainer(),
+	}
+
+	ctx := context.Background()
+
+	cfg := nacelle.NewConfig(nacelle.NewTestEnvSourcer(nil))
+	ctx = config.WithConfig(ctx, cfg)
+
+	err := outer.Init(ctx)
+	require.Nil(t, err)
+	mockassert.CalledOnceWith(t, handler.InitFunc, mockassert.Values(ctx))
+}
+
+func TestKafkaEventBadInjection(t *testing.T) {
+	handler := &badInjectionKafkaEventHandler{}
+	outer := &kafkaEventHandler{
+		handler:  handler,
+		Logger:   nacelle.NewNilLogger(),
+		Services: makeBadContainer(),
+	}
+
+	ctx := context.Background()
+
+	cfg := nacelle.NewConfig(nacelle.NewTestEnvSourcer(nil))
+	ctx = config.WithConfig(ctx, cfg)
+
+	err := outer.Init(ctx)
+	require.NotNil(t, err)
+	require.Contains(t, err.Error(), "ServiceA")
+}
+
+func TestKafkaEventInitError(t *testing.T) {
+	handler := NewMockKafkaEventHandlerInitializer()
+	handler.InitFunc.SetDefaultReturn(fmt.Errorf("oops"))
+	outer := &kafkaEventHandler{
+		handler:  handler,
+		Logger:   nacelle.NewNilLogger(),
+		Services: makeBadContainer(),
+	}
+
+	ctx := context.Background()
+
+	cfg := nacelle.NewConfig(nacelle.NewTestEnvSourcer(nil))
+	ctx = config.WithConfig(ctx, cfg)
+
+	err := outer.Init(ctx)
+	require.EqualError(t, err, "oops")
+}
+
+func TestKafkaRecordInit(t *testing.T) {
+	handler := NewMockKafkaRecordHandlerInitializer()
+	outer := &kafkaRecordHandler{
+		handler:  handler,
+		Services: nacelle.NewServiceContainer(),
+	}
+
+	ctx := context.Background()
+
+	cfg := nacelle.NewConfig(nacelle.NewTestEnvSourcer(nil))
+	ctx = config.WithConfig(ctx, cfg)
+
+	err := outer.Init(ctx)
+	require.Nil(t, err)
+	mockassert.CalledOnceWith(t, handler.InitFunc, mockassert.Values(ctx))
+}
+
+func TestKafkaRecordBadInjection(t *testing.T) {
+	handler := &badInjectionKafkaRecordHandler{}
+	outer := &kafkaRecordHandler{
+		handler:  handler,
+		Services: makeBadContainer(),
+	}
+
+	ctx := context.Background()
+
+	cfg := nacelle.NewConfig(nacelle.NewTestEnvSourcer(nil))
+	ctx = config.WithConfig(ctx, cfg)
+
+	err := outer.Init(ctx)
+	require.NotNil(t, err)
+	require.Contains(t, err.Error(), "ServiceA")
+}
+
+func TestKafkaRecordInitError(t *testing.T) {
+	handler := NewMockKafkaRecordHandlerInitializer()
+	handler.InitFunc.SetDefaultReturn(fmt.Errorf("oops"))
+	outer := &kafkaRecordHandler{
+		handler:  handler,
+		Services: nacelle.NewServiceContainer(),
+	}
+
+	ctx := context.Background()
+
+	cfg := nacelle.NewConfig(nacelle.NewTestEnvSourcer(nil))
+	ctx = config.WithConfig(ctx, cfg)
+
+	err := outer.Init(ctx)
+	require.EqualError(t, err, "oops")
+}
+
+func TestKafkaEventInvoke(t *testing.T) {
+	handler := NewMockKafkaEventHandlerInitializer()
+	outer := &kafkaEventHandler{
+		handler: handler,
+		Logger:  nacelle.NewNilLogger(),
+	}
+
+	response, err := outer.Invoke(context.Background(), []byte(testKafkaPayload))
+	require.Nil(t, err)
+	require.Nil(t, response)
+	mockassert.CalledOnce(t, handler.HandleFunc)
+
+	event := handler.HandleFunc.History()[0].Arg1
+	require.Equal(t, "aws:kafka", event.EventSource)
+	require.Len(t, event.Records["orders-0"], 2)
+	require.Len(t, event.Records["orders-1"], 1)
+}
+
+func TestKafkaEventInvokeError(t *testing.T) {
+	handler := NewMockKafkaEventHandlerInitializer()
+	outer := &kafkaEventHandler{
+		handler: handler,
+		Logger:  nacelle.NewNilLogger(),
+	}
+
+	handler.HandleFunc.SetDefaultReturn(fmt.Errorf("oops"))
+	_, err := outer.Invoke(context.Background(), []byte(testKafkaPayload))
+	require.EqualError(t, err, "failed to process Kafka event (oops)")
+}
+
+func TestKafkaRecordHandle(t *testing.T) {
+	eventHandler := NewMockKafkaEventHandlerInitializer()
+	_, err := (&kafkaEventHandler{handler: eventHandler, Logger: nacelle.NewNilLogger()}).Invoke(context.Background(), []byte(testKafkaPayload))
+	require.Nil(t, err)
+	event := eventHandler.HandleFunc.History()[0].Arg1
+
+	handler := NewMockKafkaRecordHandlerInitializer()
+	outer := &kafkaRecordHandler{handler: handler, recordConcurrency: 1}
+
+	err = outer.Handle(context.Background(), event, nacelle.NewNilLogger())
+	require.Nil(t, err)
+	mockassert.CalledN(t, handler.HandleFunc, 3)
+
+	messages := []KafkaMessage{}
+	for _, call := range handler.HandleFunc.History() {
+		messages = append(messages, call.Arg1)
+	}
+
+	timestamp := time.Unix(0, 1545084650987*int64(time.Millisecond))
+	require.Equal(t, []KafkaMessage{
+		{
+			Topic:         "orders",
+			Partition:     0,
+			Offset:        15,
+			Timestamp:     timestamp,
+			TimestampType: "CREATE_TIME",
+			Key:           []byte("a1"),
+			Value:         []byte("x1"),
+			Headers:       []KafkaHeader{{Key: "trace", Value: []byte("t1")}, {Key: "trace", Value: []byte("t2")}},
+		},
+		{Topic: "orders", Partition: 0, Offset: 16, Timestamp: timestamp, TimestampType: "CREATE_TIME", Key: []byte("a1"), Value: []byte("x2"), Headers: []KafkaHeader{}},
+		{Topic: "orders", Partition: 1, Offset: 16, Timestamp: timestamp, TimestampType: "CREATE_TIME", Key: []byte("b1"), Value: []byte("x3"), Headers: []KafkaHeader{}},
+	}, messages)
+}
+
+func TestKafkaRecordHandleError(t *testing.T) {
+	event := events.KafkaEvent{
+		Records: map[string][]events.KafkaRecord{
+			"orders-0": {
+				{Topic: "orders", Partition: 0, Offset: 2},
+				{Topic: "orders", Partition: 0, Offset: 1},
+			},
+		},
+	}
+
+	handler := NewMockKafkaRecordHandlerInitializer()
+	handler.HandleFunc.PushReturn(fmt.Errorf("oops"))
+	outer := &kafkaRecordHandler{handler: handler, recordConcurrency: 1}
+
+	err := outer.Handle(context.Background(), event, nacelle.NewNilLogger())
+	require.EqualError(t, err, "failed to process Kafka record orders-0@1 (oops)")
+	mockassert.CalledN(t, handler.HandleFunc, 1)
+}
+
+func TestKafkaRecordHandleDecodeError(t *testing.T) {
+	event := events.KafkaEvent{
+		Records: map[string][]events.KafkaRecord{
+			"orders-0": {{Topic: "orders", Partition: 0, Offset: 1, Value: "not base64"}},
+		},
+	}
+
+	handler := NewMockKafkaRecordHandlerInitializer()
+	outer := &kafkaRecordHandler{handler: handler, recordConcurrency: 1}
+
+	err := outer.Handle(context.Background(), event, nacelle.NewNilLogger())
+	require.NotNil(t, err)
+	require.Contains(t, err.Error(), "failed to decode Kafka record value")
+	mockassert.NotCalled(t, handler.HandleFunc)
+}
+
+func TestKafkaRecordHandleConcurrent(t *testing.T) {
+	event := events.KafkaEvent{
+		Records: map[string][]events.KafkaRecord{
+			"orders-0": {{Topic: "orders", Partition: 0, Offset: 1}, {Topic: "orders", Partition: 0, Offset: 2}},
+			"orders-1": {{Topic: "orders", Partition: 1, Offset: 1}, {Topic: "orders", Partition: 1, Offset: 2}},
+		},
+	}
+
+	handler := NewMockKafkaRecordHandlerInitializer()
+	outer := &kafkaRecordHandler{handler: handler, recordConcurrency: 2}
+
+	err := outer.Handle(context.Background(), event, nacelle.NewNilLogger())
+	require.Nil(t, err)
+
+	offsets := map[int64][]int64{}
+	for _, call := range handler.HandleFunc.History() {
+		offsets[call.Arg1.Partition] = append(offsets[call.Arg1.Partition], call.Arg1.Offset)
+	}
+
+	require.Equal(t, map[int64][]int64{0: {1, 2}, 1: {1, 2}}, offsets)
+}
+
+//
+// Bad Injection
+
+type badInjectionKafkaEventHandler struct {
+	ServiceA *A `service:"A"`
+}
+
+func (i *badInjectionKafkaEventHandler) Handle(ctx context.Context, event events.KafkaEvent, logger nacelle.Logger) error {
+	return nil
+}
+
+type badInjectionKafkaRecordHandler struct {
+	ServiceA *A `service:"A"`
+}
+
+func (i *badInjectionKafkaRecordHandler) Handle(ctx context.Context, message KafkaMessage, logger nacelle.Logger) error {
+	return nil
+}
@@ -0,0 +1,232 @@
+package lambdabase
+
+import (
+	"bytes"
+	"crypto/md5"
+	"encoding/binary"
+	"fmt"
+
+	"github.com/aws/aws-lambda-go/events"
+)
+
+type (
+	// kinesisUserRecord is a record delivered to a Kinesis record handler.
+	// Records packed by the Kinesis Producer Library are expanded into one
+	// user record per sub-record, each of which shares the sequence number
+	// of the Kinesis record that contained it.
+	kinesisUserRecord struct {
+		record            events.KinesisEventRecord
+		aggregated        bool
+		explicitHashKey   string
+		subSequenceNumber int
+		err               error
+	}
+
+	aggregatedRecord struct {
+		partitionKeyTable    []string
+		explicitHashKeyTable []string
+		records              []aggregatedSubRecord
+	}
+
+	aggregatedSubRecord struct {
+		partitionKeyIndex    uint64
+		explicitHashKeyIndex *uint64
+		data                 []byte
+	}
+)
+
+// kplMagic prefixes the data of every record aggregated by the Kinesis
+// Producer Library. The magic is followed by a protobuf-encoded
+// AggregatedRecord message and the MD5 digest of that message.
+var kplMagic = []byte{0xF3, 0x89, 0x9A, 0xC2}
+
+// deaggregateKinesisRecords expands records aggregated by the Kinesis
+// Producer Library into their sub-records. Records without the KPL magic
+// header or with a mismatched MD5 digest are not aggregated and are returned
+// unchanged. Aggregated records that cannot be decoded are returned with an
+// error so that they fail without invoking the handler.
+func deaggregateKinesisRecords(records []events.KinesisEventRecord) []kinesisUserRecord {
+	userRecords := make([]kinesisUserRecord, 0, len(records))
+	for _, record := range records {
+		message, ok := aggregatedRecordMessage(record.Kinesis.Data)
+		if !ok {
+			userRecords = append(userRecords, kinesisUserRecord{record: record})
+			continue
+		}
+
+		aggregated, err := decodeAggregatedRecord(message)
+		if err != nil {
+			userRecords = append(userRecords, kinesisUserRecord{
+				record:     record,
+				aggregated: true,
+				err:        &DecodeError{Part: "aggregated Kinesis record", Err: err},
+			})
+
+			continue
+		}
+
+		for i, subRecord := range aggregated.records {
+			userRecord := record
+			userRecord.Kinesis.Data = subRecord.data
+			userRecord.Kinesis.PartitionKey = aggregated.partitionKeyTable[subRecord.partitionKeyIndex]
+
+			explicitHashKey := ""
+			if subRecord.explicitHashKeyIndex != nil {
+				explicitHashKey = aggregated.explicitHashKeyTable[*subRecord.explicitHashKeyIndex]
+			}
+
+			userRecords = append(userRecords, kinesisUserRecord{
+				record:            userRecord,
+				aggregated:        true,
+				explicitHashKey:   explicitHashKey,
+				subSequenceNumber: i,
+			})
+		}
+	}
+
+	return userRecords
+}
+
+// aggregatedRecordMessage returns the protobuf message of the given KPL
+// aggregated record data if the data has the KPL magic header and a valid
+// MD5 digest.
+func aggregatedRecordMessage(data []byte) ([]byte, bool) {
+	if len(data) < len(kplMagic)+md5.Size || !bytes.HasPrefix(data, kplMagic) {
+		return nil, false
+	}
+
+	message := data[len(kplMagic) : len(data)-md5.Size]
+	digest := md5.Sum(message)
+	if !bytes.Equal(digest[:], data[len(data)-md5.Size:]) {
+		return nil, false
+	}
+
+	return message, true
+}
+
+// decodeAggregatedRecord decodes the following protobuf message, as defined
+// by the Kinesis Producer Library. Tags are not exposed and are skipped.
+//
+//	message AggregatedRecord {
+//	  repeated string partition_key_table     = 1;
+//	  repeated string explicit_hash_key_table = 2;
+//	  repeated Record records                 = 3;
+//	}
+//
+//	message Record {
+//	  required uint64 partition_key_index     = 1;
+//	  optional uint64 explicit_hash_key_index = 2;
+//	  required bytes  data                    = 3;
+//	  repeated Tag    tags                    = 4;
+//	}
+func decodeAggregatedRecord(message []byte) (aggregatedRecord, error) {
+	aggregated := aggregatedRecord{}
+
+	err := decodeProtobufFields(message, func(field uint64, value []byte, _ uint64) error {
+		switch field {
+		case 1:
+			aggregated.partitionKeyTable = append(aggregated.partitionKeyTable, string(value))
+		case 2:
+			aggregated.explicitHashKeyTable = append(aggregated.explicitHashKeyTable, string(value))
+		case 3:
+			subRecord, err := decodeAggregatedSubRecord(value)
+			if err != nil {
+				return err
+			}
+
+			aggregated.records = append(aggregated.records, subRecord)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return aggregatedRecord{}, err
+	}
+
+	for i, subRecord := range aggregated.records {
+		if subRecord.partitionKeyIndex >= uint64(len(aggregated.partitionKeyTable)) {
+			return aggregatedRecord{}, fmt.Errorf("sub-record %d has invalid partition key index %d", i, subRecord.partitionKeyIndex)
+		}
+
+		if subRecord.explicitHashKeyIndex != nil && *subRecord.explicitHashKeyIndex >= uint64(len(aggregated.explicitHashKeyTable)) {
+			return aggregatedRecord{}, fmt.Errorf("sub-record %d has invalid explicit hash key index %d", i, *subRecord.explicitHashKeyIndex)
+		}
+	}
+
+	return aggregated, nil
+}
+
+func decodeAggregatedSubRecord(message []byte) (aggregatedSubRecord, error) {
+	subRecord := aggregatedSubRecord{}
+
+	err := decodeProtobufFields(message, func(field uint64, value []byte, varint uint64) error {
+		switch field {
+		case 1:
+			subRecord.partitionKeyIndex = varint
+		case 2:
+			subRecord.explicitHashKeyIndex = &varint
+		case 3:
+			subRecord.data = value
+		}
+
+		return nil
+	})
+
+	return subRecord, err
+}
+
+// decodeProtobufFields invokes f for each field of the given protobuf
+// message with the field number and either the contents of a length-delimited
+// field or the value of a varint field.
+func decodeProtobufFields(message []byte, f func(field uint64, value []byte, varint uint64) error) error {
+	for len(message) > 0 {
+		key, n := binary.Uvarint(message)
+		if n <= 0 {
+			return fmt.Errorf("malformed field key")
+		}
+		message = message[n:]
+
+		field, wireType := key>>3, key&0x7
+
+		switch wireType {
+		case 0:
+			value, n := binary.Uvarint(message)
+			if n <= 0 {
+				return fmt.Errorf("malformed varint in field %d", field)
+			}
+			message = message[n:]
+
+			if err := f(field, nil, value); err != nil {
+				return err
+			}
+
+		case 1, 5:
+			size := 8
+			if wireType == 5 {
+				size = 4
+			}
+
+			if len(message) < size {
+				return fmt.Errorf("truncated field %d", field)
+			}
+			message = message[size:]
+
+		case 2:
+			length, n := binary.Uvarint(message)
+			if n <= 0 || length > uint64(len(message)-n) {
+				return fmt.Errorf("truncated field %d", field)
+			}
+			value := message[n : n+int(length)]
+			message = message[n+int(length):]
+
+			if err := f(field, value, 0); err != nil {
+				return err
+			}
+
+		default:
+			return fmt.Errorf("unsupported wire type %d in field %d", wireType, field)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,145 @@
+package lambdabase
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/binary"
+	"fmt"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+	mockassert "github.com/derision-test/go-mockgen/testutil/assert"
+	"github.com/go-nacelle/nacelle/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeaggregateKinesisRecords(t *testing.T) {
+	plain := events.KinesisEventRecord{EventID: "ev1", Kinesis: events.KinesisRecord{SequenceNumber: "1", PartitionKey: "p", Data: []byte("plain")}}
+	aggregated := events.KinesisEventRecord{EventID: "ev2", Kinesis: events.KinesisRecord{SequenceNumber: "2", PartitionKey: "outer", Data: makeAggregatedRecord(
+		[]string{"a", "b"},
+		[]string{"12345"},
+		[]testSubRecord{{0, -1, "x"}, {1, 0, "y"}, {0, -1, "z"}},
+	)}}
+
+	userRecords := deaggregateKinesisRecords([]events.KinesisEventRecord{plain, aggregated})
+	require.Len(t, userRecords, 4)
+	require.Equal(t, kinesisUserRecord{record: plain}, userRecords[0])
+
+	for i, expected := range []struct {
+		partitionKey    string
+		explicitHashKey string
+		data            string
+	}{{"a", "", "x"}, {"b", "12345", "y"}, {"a", "", "z"}} {
+		userRecord := userRecords[i+1]
+		require.True(t, userRecord.aggregated)
+		require.Nil(t, userRecord.err)
+		require.Equal(t, i, userRecord.subSequenceNumber)
+		require.Equal(t, expected.explicitHashKey, userRecord.explicitHashKey)
+		require.Equal(t, expected.partitionKey, userRecord.record.Kinesis.PartitionKey)
+		require.Equal(t, []byte(expected.data), userRecord.record.Kinesis.Data)
+		require.Equal(t, "2", userRecord.record.Kinesis.SequenceNumber)
+		require.Equal(t, "ev2", userRecord.record.EventID)
+	}
+}
+
+func TestDeaggregateKinesisRecordsChecksumMismatch(t *testing.T) {
+	data := makeAggregatedRecord([]string{"a"}, nil, []testSubRecord{{0, -1, "x"}})
+	data[len(data)-1] ^= 0xFF
+
+	record := events.KinesisEventRecord{EventID: "ev1", Kinesis: events.KinesisRecord{Data: data}}
+	userRecords := deaggregateKinesisRecords([]events.KinesisEventRecord{record})
+	require.Equal(t, []kinesisUserRecord{{record: record}}, userRecords)
+}
+
+func TestDeaggregateKinesisRecordsInvalidIndex(t *testing.T) {
+	data := makeAggregatedRecord([]string{"a"}, nil, []testSubRecord{{3, -1, "x"}})
+
+	userRecords := deaggregateKinesisRecords([]events.KinesisEventRecord{{Kinesis: events.KinesisRecord{Data: data}}})
+	require.Len(t, userRecords, 1)
+	require.EqualError(t, userRecords[0].err, "failed to decode aggregated Kinesis record (sub-record 0 has invalid partition key index 3)")
+}
+
+func TestKinesisRecordHandleDeaggregate(t *testing.T) {
+	records := []events.KinesisEventRecord{
+		{EventID: "ev1", Kinesis: events.KinesisRecord{SequenceNumber: "1", Data: makeAggregatedRecord([]string{"a"}, nil, []testSubRecord{{0, -1, "x"}, {0, -1, "y"}})}},
+		{EventID: "ev2", Kinesis: events.KinesisRecord{SequenceNumber: "2", PartitionKey: "b", Data: []byte("z")}},
+	}
+
+	handler := NewMockKinesisRecordHandlerInitializer()
+	outer := &kinesisRecordHandler{handler: handler, deaggregate: true}
+
+	err := outer.Handle(context.Background(), records, nacelle.NewNilLogger())
+	require.Nil(t, err)
+	mockassert.CalledN(t, handler.HandleFunc, 3)
+
+	data := []string{}
+	for _, call := range handler.HandleFunc.History() {
+		data = append(data, string(call.Arg1.Kinesis.Data))
+	}
+	require.Equal(t, []string{"x", "y", "z"}, data)
+}
+
+func TestKinesisRecordHandleDeaggregateWithResponse(t *testing.T) {
+	records := []events.KinesisEventRecord{
+		{EventID: "ev1", Kinesis: events.KinesisRecord{SequenceNumber: "1", Data: makeAggregatedRecord([]string{"a"}, nil, []testSubRecord{{0, -1, "x"}, {0, -1, "y"}})}},
+		{EventID: "ev2", Kinesis: events.KinesisRecord{SequenceNumber: "2", PartitionKey: "b", Data: []byte("z")}},
+	}
+
+	handler := NewMockKinesisRecordHandlerInitializer()
+	handler.HandleFunc.PushReturn(nil)
+	handler.HandleFunc.PushReturn(fmt.Errorf("oops"))
+	outer := &kinesisRecordHandler{handler: handler, deaggregate: true, reportBatchItemFailures: true}
+
+	response, err := outer.HandleWithResponse(context.Background(), records, nacelle.NewNilLogger())
+	require.Nil(t, err)
+	require.Equal(t, []events.KinesisBatchItemFailure{{ItemIdentifier: "1"}}, response.BatchItemFailures)
+	mockassert.CalledN(t, handler.HandleFunc, 2)
+}
+
+//
+// Helpers
+
+type testSubRecord struct {
+	partitionKeyIndex    uint64
+	explicitHashKeyIndex int
+	data                 string
+}
+
+func makeAggregatedRecord(partitionKeys, explicitHashKeys []string, subRecords []testSubRecord) []byte {
+	message := []byte{}
+	for _, key := range partitionKeys {
+		message = appendProtobufBytes(message, 1, []byte(key))
+	}
+	for _, key := range explicitHashKeys {
+		message = appendProtobufBytes(message, 2, []byte(key))
+	}
+	for _, subRecord := range subRecords {
+		encoded := appendProtobufVarint(nil, 1, subRecord.partitionKeyIndex)
+		if subRecord.explicitHashKeyIndex >= 0 {
+			encoded = appendProtobufVarint(encoded, 2, uint64(subRecord.explicitHashKeyIndex))
+		}
+		encoded = appendProtobufBytes(encoded, 3, []byte(subRecord.data))
+		message = appendProtobufBytes(message, 3, encoded)
+	}
+
+	digest := md5.Sum(message)
+	data := append([]byte{}, kplMagic...)
+	data = append(data, message...)
+	return append(data, digest[:]...)
+}
+
+func appendProtobufVarint(buf []byte, field, value uint64) []byte {
+	buf = appendUvarint(buf, field<<3)
+	return appendUvarint(buf, value)
+}
+
+func appendProtobufBytes(buf []byte, field uint64, value []byte) []byte {
+	buf = appendUvarint(buf, field<<3|2)
+	buf = appendUvarint(buf, uint64(len(value)))
+	return append(buf, value...)
+}
+
+func appendUvarint(buf []byte, value uint64) []byte {
+	encoded := make([]byte, binary.MaxVarintLen64)
+	return append(buf, encoded[:binary.PutUvarint(encoded, value)]...)
+}
@@ -14,6 +14,10 @@ type (
 		Handle(ctx context.Context, batch []events.KinesisEventRecord, logger nacelle.Logger) error
 	}
 
+	KinesisEventResponseHandler interface {
+		HandleWithResponse(ctx context.Context, batch []events.KinesisEventRecord, logger nacelle.Logger) (*events.KinesisEventResponse, error)
+	}
+
 	kinesisEventHandlerInitializer interface {
 		nacelle.Initializer
 		KinesisEventHandler
@@ -26,10 +30,10 @@ type (
 	}
 )
 
-func NewKinesisEventServer(handler KinesisEventHandler) *Server {
+func NewKinesisEventServer(handler KinesisEventHandler, configs ...ConfigFunc) *Server {
 	return NewServer(&kinesisEventHandler{
 		handler: handler,
-	})
+	}, configs...)
 }
 
 func (h *kinesisEventHandler) Init(ctx context.Context) error {
@@ -39,7 +43,7 @@ func (h *kinesisEventHandler) Init(ctx context.Context) error {
 func (h *kinesisEventHandler) Invoke(ctx context.Context, payload []byte) ([]byte, error) {
 	event := &events.KinesisEvent{}
 	if err := json.Unmarshal(payload, &event); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal event (%s)", err.Error())
+		return nil, fmt.Errorf("failed to unmarshal event (%w)", err)
 	}
 
 	logger := h.Logger.WithFields(map[string]interface{}{
@@ -48,10 +52,35 @@ func (h *kinesisEventHandler) Invoke(ctx context.Context, payload []byte) ([]byt
 
 	logger.Debug("Received %d Kinesis records", len(event.Records))
 
+	if responseHandler, ok := h.handler.(KinesisEventResponseHandler); ok {
+		return h.invokeWithResponse(ctx, responseHandler, event.Records, logger)
+	}
+
 	if err := h.handler.Handle(ctx, event.Records, logger); err != nil {
-		return nil, fmt.Errorf("failed to process Kinesis event (%s)", err.Error())
+		return nil, fmt.Errorf("failed to process Kinesis event (%w)", err)
 	}
 
 	logger.Debug("Kinesis event handled successfully")
 	return nil, nil
 }
+
+func (h *kinesisEventHandler) invokeWithResponse(ctx context.Context, handler KinesisEventResponseHandler, batch []events.KinesisEventRecord, logger nacelle.Logger) ([]byte, error) {
+	response, err := handler.HandleWithResponse(ctx, batch, logger)
+	if err != nil {
+		return nil, fmt.Errorf("failed to process Kinesis event (%w)", err)
+	}
+
+	if response == nil {
+		logger.Debug("Kinesis event handled successfully")
+		return nil, nil
+	}
+
+	logger.Debug("Kinesis event handled with %d failed records", len(response.BatchItemFailures))
+
+	serialized, err := json.Marshal(response)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal Kinesis event response (%w)", err)
+	}
+
+	return serialized, nil
+}
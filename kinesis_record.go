@@ -2,7 +2,6 @@ package lambdabase
 
 import (
 	"context"
-	"fmt"
 
 	"github.com/aws/aws-lambda-go/events"
 	"github.com/go-nacelle/nacelle/v2"
@@ -19,35 +18,129 @@ type (
 	}
 
 	kinesisRecordHandler struct {
-		Logger   nacelle.Logger            `service:"logger"`
-		Services *nacelle.ServiceContainer `service:"services"`
-		handler  KinesisRecordHandler
+		Logger                  nacelle.Logger            `service:"logger"`
+		Services                *nacelle.ServiceContainer `service:"services"`
+		handler                 KinesisRecordHandler
+		reportBatchItemFailures bool
+		recordConcurrency       int
+		deaggregate             bool
+		recordMiddleware        []RecordMiddleware
 	}
 )
 
-func NewKinesisRecordServer(handler KinesisRecordHandler) *Server {
+func NewKinesisRecordServer(handler KinesisRecordHandler, configs ...ConfigFunc) *Server {
+	options := getOptions(configs)
+
 	return NewKinesisEventServer(&kinesisRecordHandler{
-		handler: handler,
-	})
+		handler:                 handler,
+		reportBatchItemFailures: options.reportBatchItemFailures,
+		recordConcurrency:       options.recordConcurrency,
+		deaggregate:             options.deaggregateKinesisRecords,
+		recordMiddleware:        options.recordMiddleware,
+	}, configs...)
 }
 
 func (s *kinesisRecordHandler) Init(ctx context.Context) error {
+	recordConcurrency, err := loadRecordConcurrency(ctx, s.recordConcurrency)
+	if err != nil {
+		return err
+	}
+	s.recordConcurrency = recordConcurrency
+
+	reportBatchItemFailures, err := loadReportBatchItemFailures(ctx, s.reportBatchItemFailures)
+	if err != nil {
+		return err
+	}
+	s.reportBatchItemFailures = reportBatchItemFailures
+
 	return doInit(ctx, s.Services, s.handler)
 }
 
 func (h *kinesisRecordHandler) Handle(ctx context.Context, records []events.KinesisEventRecord, logger nacelle.Logger) error {
-	for _, record := range records {
-		recordLogger := logger.WithFields(map[string]interface{}{
-			"eventId": record.EventID,
-		})
-
-		logger.Debug("Handling record")
+	userRecords, errs := h.handleRecords(ctx, records, logger)
 
-		if err := h.handler.Handle(ctx, record, recordLogger); err != nil {
-			return fmt.Errorf("failed to process Kinesis record %s (%s)", record.EventID, err.Error())
-		}
+	if i := firstFailure(errs); i >= 0 {
+		return newRecordError("Kinesis", "record", userRecords[i].record.EventID, errs[i])
 	}
 
 	logger.Debug("Kinesis record handled successfully")
 	return nil
 }
+
+func (h *kinesisRecordHandler) HandleWithResponse(ctx context.Context, records []events.KinesisEventRecord, logger nacelle.Logger) (*events.KinesisEventResponse, error) {
+	if !h.reportBatchItemFailures {
+		return nil, h.Handle(ctx, records, logger)
+	}
+
+	response := &events.KinesisEventResponse{
+		BatchItemFailures: []events.KinesisBatchItemFailure{},
+	}
+
+	userRecords, errs := h.handleRecords(ctx, records, logger)
+
+	for i, err := range errs {
+		if err != nil && err != errRecordSkipped {
+			recordLogger := logger.WithFields(map[string]interface{}{
+				"eventId": userRecords[i].record.EventID,
+			})
+
+			recordLogger.Error("Failed to process Kinesis record (%s)", err.Error())
+		}
+	}
+
+	// Lambda retries the batch starting from the reported sequence number,
+	// so only the earliest record that failed or was skipped is reported.
+	// Sub-records of an aggregated record share its sequence number, so
+	// the entire aggregated record is retried.
+	for i, err := range errs {
+		if err != nil {
+			sequenceNumber := userRecords[i].record.Kinesis.SequenceNumber
+			logger.Warning("Checkpointing Kinesis batch at sequence number %s", sequenceNumber)
+
+			response.BatchItemFailures = append(response.BatchItemFailures, events.KinesisBatchItemFailure{
+				ItemIdentifier: sequenceNumber,
+			})
+
+			break
+		}
+	}
+
+	return response, nil
+}
+
+func (h *kinesisRecordHandler) handleRecords(ctx context.Context, records []events.KinesisEventRecord, logger nacelle.Logger) ([]kinesisUserRecord, []error) {
+	userRecords := make([]kinesisUserRecord, 0, len(records))
+	if h.deaggregate {
+		userRecords = deaggregateKinesisRecords(records)
+	} else {
+		for _, record := range records {
+			userRecords = append(userRecords, kinesisUserRecord{record: record})
+		}
+	}
+
+	keys := make([]string, 0, len(userRecords))
+	for _, userRecord := range userRecords {
+		keys = append(keys, userRecord.record.Kinesis.PartitionKey)
+	}
+
+	return userRecords, processBatch(keys, h.recordConcurrency, true, func(i int) error {
+		userRecord := userRecords[i]
+		if userRecord.err != nil {
+			return userRecord.err
+		}
+
+		fields := map[string]interface{}{
+			"eventId": userRecord.record.EventID,
+		}
+
+		if userRecord.aggregated {
+			fields["partitionKey"] = userRecord.record.Kinesis.PartitionKey
+			fields["explicitHashKey"] = userRecord.explicitHashKey
+			fields["subSequenceNumber"] = userRecord.subSequenceNumber
+		}
+
+		recordLogger := logger.WithFields(fields)
+		recordLogger.Debug("Handling record")
+		return invokeRecordHandler(ctx, h.recordMiddleware, userRecord.record, recordLogger, h.handler.Handle)
+	})
+}
@@ -18,6 +18,7 @@ var testKinesisPayload = `{
 			"eventID": "ev1",
 			"kinesis": {
 				"PartitionKey": "foo",
+				"SequenceNumber": "1",
 				"Data": "WyJ4MSIsICJ5MSIsICJ6MSJdCg=="
 			}
 		},
@@ -25,6 +26,7 @@ var testKinesisPayload = `{
 			"eventID": "ev2",
 			"kinesis": {
 				"PartitionKey": "bar",
+				"SequenceNumber": "2",
 				"Data": "WyJ4MiIsICJ5MiIsICJ6MiJdCg=="
 			}
 		},
@@ -32,6 +34,7 @@ var testKinesisPayload = `{
 			"eventID": "ev3",
 			"kinesis": {
 				"PartitionKey": "baz",
+				"SequenceNumber": "3",
 				"Data": "WyJ4MyIsICJ5MyIsICJ6MyJdCg=="
 			}
 		}
@@ -42,22 +45,25 @@ var testKinesisRecords = []events.KinesisEventRecord{
 	{
 		EventID: "ev1",
 		Kinesis: events.KinesisRecord{
-			PartitionKey: "foo",
-			Data:         []byte{91, 34, 120, 49, 34, 44, 32, 34, 121, 49, 34, 44, 32, 34, 122, 49, 34, 93, 10},
+			PartitionKey:   "foo",
+			SequenceNumber: "1",
+			Data:           []byte{91, 34, 120, 49, 34, 44, 32, 34, 121, 49, 34, 44, 32, 34, 122, 49, 34, 93, 10},
 		},
 	},
 	{
 		EventID: "ev2",
 		Kinesis: events.KinesisRecord{
-			PartitionKey: "bar",
-			Data:         []byte{91, 34, 120, 50, 34, 44, 32, 34, 121, 50, 34, 44, 32, 34, 122, 50, 34, 93, 10},
+			PartitionKey:   "bar",
+			SequenceNumber: "2",
+			Data:           []byte{91, 34, 120, 50, 34, 44, 32, 34, 121, 50, 34, 44, 32, 34, 122, 50, 34, 93, 10},
 		},
 	},
 	{
 		EventID: "ev3",
 		Kinesis: events.KinesisRecord{
-			PartitionKey: "baz",
-			Data:         []byte{91, 34, 120, 51, 34, 44, 32, 34, 121, 51, 34, 44, 32, 34, 122, 51, 34, 93, 10},
+			PartitionKey:   "baz",
+			SequenceNumber: "3",
+			Data:           []byte{91, 34, 120, 51, 34, 44, 32, 34, 121, 51, 34, 44, 32, 34, 122, 51, 34, 93, 10},
 		},
 	},
 }
@@ -195,6 +201,21 @@ func TestKinesisEventInvokeError(t *testing.T) {
 	require.EqualError(t, err, "failed to process Kinesis event (oops)")
 }
 
+func TestKinesisEventInvokeWithResponse(t *testing.T) {
+	handler := NewMockKinesisRecordHandlerInitializer()
+	handler.HandleFunc.PushReturn(nil)
+	handler.HandleFunc.PushReturn(fmt.Errorf("oops"))
+	outer := &kinesisEventHandler{
+		handler: &kinesisRecordHandler{handler: handler, reportBatchItemFailures: true},
+		Logger:  nacelle.NewNilLogger(),
+	}
+
+	response, err := outer.Invoke(context.Background(), []byte(testKinesisPayload))
+	require.Nil(t, err)
+	require.JSONEq(t, `{"batchItemFailures": [{"itemIdentifier": "2"}]}`, string(response))
+	mockassert.CalledN(t, handler.HandleFunc, 2)
+}
+
 func TestKinesisRecordHandle(t *testing.T) {
 	handler := NewMockKinesisRecordHandlerInitializer()
 	outer := &kinesisRecordHandler{handler: handler}
@@ -218,6 +239,77 @@ func TestKinesisRecordHandleError(t *testing.T) {
 	mockassert.CalledN(t, handler.HandleFunc, 2)
 }
 
+func TestKinesisRecordHandleWithResponse(t *testing.T) {
+	handler := NewMockKinesisRecordHandlerInitializer()
+	outer := &kinesisRecordHandler{handler: handler, reportBatchItemFailures: true}
+
+	response, err := outer.HandleWithResponse(context.Background(), testKinesisRecords, nacelle.NewNilLogger())
+	require.Nil(t, err)
+	require.Empty(t, response.BatchItemFailures)
+	mockassert.CalledN(t, handler.HandleFunc, 3)
+}
+
+func TestKinesisRecordHandleWithResponseDisabled(t *testing.T) {
+	handler := NewMockKinesisRecordHandlerInitializer()
+	handler.HandleFunc.PushReturn(fmt.Errorf("oops"))
+	outer := &kinesisRecordHandler{handler: handler}
+
+	response, err := outer.HandleWithResponse(context.Background(), testKinesisRecords, nacelle.NewNilLogger())
+	require.EqualError(t, err, "failed to process Kinesis record ev1 (oops)")
+	require.Nil(t, response)
+	mockassert.CalledN(t, handler.HandleFunc, 1)
+}
+
+func TestKinesisRecordHandleConcurrent(t *testing.T) {
+	handler := NewMockKinesisRecordHandlerInitializer()
+	handler.HandleFunc.SetDefaultHook(func(ctx context.Context, record events.KinesisEventRecord, logger nacelle.Logger) error {
+		if record.EventID == "ev2" {
+			return fmt.Errorf("oops")
+		}
+
+		return nil
+	})
+	outer := &kinesisRecordHandler{handler: handler, reportBatchItemFailures: true, recordConcurrency: 3}
+
+	response, err := outer.HandleWithResponse(context.Background(), testKinesisRecords, nacelle.NewNilLogger())
+	require.Nil(t, err)
+	require.Equal(t, []events.KinesisBatchItemFailure{{ItemIdentifier: "2"}}, response.BatchItemFailures)
+}
+
+func TestTypedKinesisRecordHandle(t *testing.T) {
+	handler := &testTypedKinesisRecordHandler{}
+	outer := &kinesisRecordHandler{
+		handler: &typedKinesisRecordHandler[[]string]{handler: handler, codec: JSONCodec{}},
+	}
+
+	err := outer.Handle(context.Background(), testKinesisRecords, nacelle.NewNilLogger())
+	require.Nil(t, err)
+	require.Equal(t, [][]string{{"x1", "y1", "z1"}, {"x2", "y2", "z2"}, {"x3", "y3", "z3"}}, handler.values)
+}
+
+func TestTypedKinesisRecordHandleDecodeError(t *testing.T) {
+	handler := &testTypedKinesisRecordHandler{}
+	outer := &typedKinesisRecordHandler[[]string]{handler: handler, codec: JSONCodec{}}
+
+	record := events.KinesisEventRecord{EventID: "ev1", Kinesis: events.KinesisRecord{Data: []byte(`{}`)}}
+	err := outer.Handle(context.Background(), record, nacelle.NewNilLogger())
+	require.NotNil(t, err)
+	require.Contains(t, err.Error(), "failed to decode Kinesis record data")
+	require.Empty(t, handler.values)
+}
+
+//
+// Typed
+
+type testTypedKinesisRecordHandler struct {
+	values [][]string
+}
+
+func (h *testTypedKinesisRecordHandler) Handle(ctx context.Context, value []string, record events.KinesisEventRecord, logger nacelle.Logger) error {
+	h.values = append(h.values, value)
+	return nil
+}
+
 //
 // Bad Injection
 
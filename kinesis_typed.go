@@ -0,0 +1,42 @@
+package lambdabase
+
+import (
+	"context"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/go-nacelle/nacelle/v2"
+)
+
+type (
+	TypedKinesisRecordHandler[T any] interface {
+		Handle(ctx context.Context, value T, record events.KinesisEventRecord, logger nacelle.Logger) error
+	}
+
+	typedKinesisRecordHandler[T any] struct {
+		Services *nacelle.ServiceContainer `service:"services"`
+		handler  TypedKinesisRecordHandler[T]
+		codec    Codec
+	}
+)
+
+func NewTypedKinesisServer[T any](handler TypedKinesisRecordHandler[T], configs ...ConfigFunc) *Server {
+	options := getOptions(configs)
+
+	return NewKinesisRecordServer(&typedKinesisRecordHandler[T]{
+		handler: handler,
+		codec:   options.codec,
+	}, configs...)
+}
+
+func (h *typedKinesisRecordHandler[T]) Init(ctx context.Context) error {
+	return doInit(ctx, h.Services, h.handler)
+}
+
+func (h *typedKinesisRecordHandler[T]) Handle(ctx context.Context, record events.KinesisEventRecord, logger nacelle.Logger) error {
+	var value T
+	if err := h.codec.Unmarshal(record.Kinesis.Data, &value); err != nil {
+		return &DecodeError{Part: "Kinesis record data", Err: err}
+	}
+
+	return h.handler.Handle(ctx, value, record, logger)
+}
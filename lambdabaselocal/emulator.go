@@ -0,0 +1,386 @@
+// Package lambdabaselocal runs a lambdabase server locally against a flow of
+// messages. An emulator reads messages from a source, batches them into the
+// events delivered by an SQS, Kinesis, or DynamoDB event source mapping, and
+// applies the retry and partial batch failure semantics of that mapping to
+// the response of the function.
+//
+// The function may be a server run by another process and reached with
+// rpcclient.Dial, or a server run in-process by lambdabasetest.New.
+package lambdabaselocal
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/go-nacelle/lambdabase/rpcclient"
+)
+
+type (
+	// Function is a Lambda function invoked by an emulator. Both
+	// *rpcclient.Client and *lambdabasetest.Harness implement this
+	// interface.
+	Function interface {
+		Invoke(payload []byte, configs ...rpcclient.InvokeConfigFunc) (*rpcclient.Response, error)
+	}
+
+	// Emulator delivers messages from a source to a function in batches.
+	Emulator struct {
+		function  Function
+		source    Source
+		options   *options
+		messages  chan *Message
+		sourceErr error
+		pending   []*record
+		sequence  int64
+	}
+
+	// record is a message in flight between the source and the function.
+	record struct {
+		message      *Message
+		id           string
+		partitionKey string
+		arrival      time.Time
+		firstAttempt time.Time
+		attempts     int
+		visibleAt    time.Time
+	}
+
+	// batchItemFailures is the response shared by events.SQSEventResponse,
+	// events.KinesisEventResponse, and events.DynamoDBEventResponse. The
+	// item identifier is a pointer to distinguish a missing identifier.
+	batchItemFailures struct {
+		BatchItemFailures []struct {
+			ItemIdentifier *string `json:"itemIdentifier"`
+		} `json:"batchItemFailures"`
+	}
+)
+
+// maxPayloadSize is the maximum size of a synchronous invocation payload.
+const maxPayloadSize = 6 * 1024 * 1024
+
+// NewEmulator creates an emulator that delivers messages from the given
+// source to the given function.
+func NewEmulator(function Function, source Source, configs ...ConfigFunc) *Emulator {
+	return &Emulator{
+		function: function,
+		source:   source,
+		options:  getOptions(configs),
+	}
+}
+
+// Run delivers messages until the source is exhausted and every message has
+// either been processed or discarded after exhausting its retries. Run returns
+// an error if the source or the function cannot be reached, or ctx.Err() if
+// the context is canceled first.
+func (e *Emulator) Run(ctx context.Context) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	e.messages = make(chan *Message)
+	go e.read(ctx)
+
+	for {
+		batch, err := e.nextBatch(ctx)
+		if err != nil || len(batch) == 0 {
+			return err
+		}
+
+		if err := e.invoke(batch); err != nil {
+			return err
+		}
+	}
+}
+
+// read sends messages from the source to the emulator until the source is
+// exhausted or the context is canceled.
+func (e *Emulator) read(ctx context.Context) {
+	defer close(e.messages)
+
+	for {
+		message, err := e.source.Next(ctx)
+		if err != nil {
+			if err != io.EOF && ctx.Err() == nil {
+				e.sourceErr = fmt.Errorf("failed to read from source (%w)", err)
+			}
+
+			return
+		}
+
+		select {
+		case e.messages <- message:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// nextBatch blocks until a batch is full, the batch window elapses after the
+// first record of the batch was received, or the source is exhausted. Records
+// awaiting a retry are delivered once they become visible. An empty batch is
+// returned once there are no remaining records.
+func (e *Emulator) nextBatch(ctx context.Context) ([]*record, error) {
+	var batch []*record
+	var window <-chan time.Time
+
+	for {
+		batch = append(batch, e.takeVisible(e.options.batchSize-len(batch))...)
+		if len(batch) >= e.options.batchSize {
+			return batch, nil
+		}
+
+		if e.messages == nil && len(e.pending) == 0 {
+			if len(batch) > 0 {
+				return batch, nil
+			}
+
+			return nil, e.sourceErr
+		}
+
+		if len(batch) > 0 && window == nil {
+			timer := time.NewTimer(e.options.batchWindow)
+			defer timer.Stop()
+			window = timer.C
+		}
+
+		message, elapsed, err := e.receive(ctx, window)
+		if err != nil {
+			return nil, err
+		}
+		if elapsed {
+			return batch, nil
+		}
+		if message == nil {
+			continue
+		}
+
+		r, err := e.newRecord(message)
+		if err != nil {
+			e.options.logger.Error("Failed to read message %s (%s)", message.ID, err.Error())
+			e.discard(message)
+			continue
+		}
+
+		batch = append(batch, r)
+	}
+}
+
+// receive blocks until a message is read from the source, a pending record
+// becomes visible, or the given batch window elapses. A nil message is
+// returned if no message was read.
+func (e *Emulator) receive(ctx context.Context, window <-chan time.Time) (*Message, bool, error) {
+	messages := e.messages
+	if e.options.eventSource.ordered() && len(e.pending) > 0 {
+		// Later records of a stream are not delivered until the failed
+		// records before them have been retried
+		messages = nil
+	}
+
+	var wake <-chan time.Time
+	if visibleAt, ok := e.nextVisible(); ok {
+		timer := time.NewTimer(time.Until(visibleAt))
+		defer timer.Stop()
+		wake = timer.C
+	}
+
+	select {
+	case message, ok := <-messages:
+		if !ok {
+			e.messages = nil
+		}
+
+		return message, false, nil
+
+	case <-wake:
+		return nil, false, nil
+	case <-window:
+		return nil, true, nil
+	case <-ctx.Done():
+		return nil, false, ctx.Err()
+	}
+}
+
+// takeVisible removes and returns up to n pending records that are due to be
+// retried, in order.
+func (e *Emulator) takeVisible(n int) []*record {
+	var (
+		now       = time.Now()
+		visible   []*record
+		remaining []*record
+	)
+
+	for _, r := range e.pending {
+		if len(visible) < n && !r.visibleAt.After(now) {
+			visible = append(visible, r)
+		} else {
+			remaining = append(remaining, r)
+		}
+	}
+
+	e.pending = remaining
+	return visible
+}
+
+// nextVisible returns the earliest time at which a pending record is due to
+// be retried.
+func (e *Emulator) nextVisible() (time.Time, bool) {
+	var next time.Time
+	for _, r := range e.pending {
+		if next.IsZero() || r.visibleAt.Before(next) {
+			next = r.visibleAt
+		}
+	}
+
+	return next, !next.IsZero()
+}
+
+// invoke delivers the given batch to the function and schedules the retry of
+// the records that failed.
+func (e *Emulator) invoke(batch []*record) error {
+	now := time.Now()
+	for _, r := range batch {
+		r.attempts++
+		if r.firstAttempt.IsZero() {
+			r.firstAttempt = now
+		}
+	}
+
+	payload, err := json.Marshal(e.makeEvent(batch))
+	if err != nil {
+		return fmt.Errorf("failed to marshal event (%w)", err)
+	}
+
+	e.options.logger.Debug("Invoking function with %d records", len(batch))
+
+	response, err := e.function.Invoke(payload)
+	if err != nil {
+		return fmt.Errorf("failed to invoke function (%w)", err)
+	}
+
+	failed := e.failedRecords(batch, response)
+	if len(failed) == 0 {
+		e.options.logger.Info("Processed batch of %d records", len(batch))
+		return nil
+	}
+
+	e.options.logger.Warning("Failed to process %d of %d records", len(failed), len(batch))
+
+	if e.options.eventSource.ordered() {
+		e.retryStream(failed)
+	} else {
+		e.retryQueue(failed)
+	}
+
+	return nil
+}
+
+// failedRecords returns the records of the batch that must be retried. The
+// entire batch fails if the function returns an error, or if partial batch
+// failures are reported and the response is malformed or names an unknown
+// record. The records of a stream after the first failed record are retried
+// along with it.
+func (e *Emulator) failedRecords(batch []*record, response *rpcclient.Response) []*record {
+	if response.Error != nil {
+		e.options.logger.Error("Function returned %s (%s)", response.Error.Type, response.Error.Message)
+		return batch
+	}
+
+	if !e.options.reportBatchItemFailures {
+		return nil
+	}
+
+	failures := batchItemFailures{}
+	if len(response.Payload) > 0 {
+		if err := json.Unmarshal(response.Payload, &failures); err != nil {
+			e.options.logger.Error("Function returned a malformed batch item failure response (%s)", err.Error())
+			return batch
+		}
+	}
+
+	ids := map[string]struct{}{}
+	for _, failure := range failures.BatchItemFailures {
+		if failure.ItemIdentifier == nil || *failure.ItemIdentifier == "" {
+			e.options.logger.Error("Function returned a batch item failure without an item identifier")
+			return batch
+		}
+
+		ids[*failure.ItemIdentifier] = struct{}{}
+	}
+
+	var failed []*record
+	for _, r := range batch {
+		if _, ok := ids[r.id]; ok {
+			failed = append(failed, r)
+			delete(ids, r.id)
+		}
+	}
+
+	for id := range ids {
+		e.options.logger.Error("Function returned a batch item failure for unknown item %s", id)
+		return batch
+	}
+
+	if len(failed) > 0 && e.options.eventSource.ordered() {
+		for i, r := range batch {
+			if r == failed[0] {
+				return batch[i:]
+			}
+		}
+	}
+
+	return failed
+}
+
+// retryQueue makes the given failed queue messages visible again after the
+// visibility timeout. Messages that have reached the maximum receive count
+// are discarded.
+func (e *Emulator) retryQueue(failed []*record) {
+	visibleAt := time.Now().Add(e.options.visibilityTimeout)
+
+	for _, r := range failed {
+		if e.options.maxReceiveCount > 0 && r.attempts >= e.options.maxReceiveCount {
+			e.options.logger.Warning("Message %s reached the maximum receive count of %d", r.message.ID, e.options.maxReceiveCount)
+			e.discard(r.message)
+			continue
+		}
+
+		r.visibleAt = visibleAt
+		e.pending = append(e.pending, r)
+	}
+}
+
+// retryStream schedules the retry of the given failed stream records after
+// the retry delay. The records are discarded once the first of them has
+// exhausted its retry attempts, and the stream advances past them.
+func (e *Emulator) retryStream(failed []*record) {
+	if e.options.maxRetryAttempts >= 0 && failed[0].attempts > e.options.maxRetryAttempts {
+		e.options.logger.Warning("Records %s through %s exhausted %d retry attempts", failed[0].id, failed[len(failed)-1].id, e.options.maxRetryAttempts)
+
+		for _, r := range failed {
+			e.discard(r.message)
+		}
+
+		return
+	}
+
+	visibleAt := time.Now().Add(e.options.retryDelay)
+	for _, r := range failed {
+		r.visibleAt = visibleAt
+	}
+
+	e.pending = append(append([]*record(nil), failed...), e.pending...)
+}
+
+// discard sends the given message to the dead-letter queue, if one is
+// configured.
+func (e *Emulator) discard(message *Message) {
+	if e.options.deadLetterQueue == nil {
+		e.options.logger.Warning("Discarding message %s", message.ID)
+		return
+	}
+
+	e.options.logger.Warning("Sending message %s to the dead-letter queue", message.ID)
+	e.options.deadLetterQueue.send(message)
+}
@@ -0,0 +1,308 @@
+package lambdabaselocal
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/go-nacelle/lambdabase"
+	"github.com/go-nacelle/lambdabase/lambdabasetest"
+	"github.com/go-nacelle/lambdabase/rpcclient"
+	"github.com/go-nacelle/nacelle/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEmulatorSQSBatches(t *testing.T) {
+	handler := &sqsBatchHandler{}
+	harness := lambdabasetest.Start(t, lambdabase.NewSQSEventServer(handler))
+	queue := sendAll(NewQueue(), "a", "b", "c", "d", "e")
+
+	require.Nil(t, NewEmulator(harness, queue, WithBatchSize(2), WithBatchWindow(time.Second)).Run(context.Background()))
+	require.Equal(t, [][]string{{"a", "b"}, {"c", "d"}, {"e"}}, handler.batches)
+}
+
+func TestEmulatorSQSBatchWindow(t *testing.T) {
+	handler := &sqsBatchHandler{}
+	harness := lambdabasetest.Start(t, lambdabase.NewSQSEventServer(handler))
+	queue := NewQueue()
+	queue.Send([]byte("a"))
+
+	go func() {
+		<-time.After(200 * time.Millisecond)
+		sendAll(queue, "b")
+	}()
+
+	require.Nil(t, NewEmulator(harness, queue, WithBatchWindow(50*time.Millisecond)).Run(context.Background()))
+	require.Equal(t, [][]string{{"a"}, {"b"}}, handler.batches)
+}
+
+func TestEmulatorSQSPartialFailures(t *testing.T) {
+	handler := &sqsRecordHandler{failures: map[string]int{"b": 1, "poison": -1}}
+	harness := lambdabasetest.Start(t, lambdabase.NewSQSRecordServer(handler, lambdabase.WithReportBatchItemFailures(true)))
+	queue := sendAll(NewQueue(), "a", "b", "poison")
+	deadLetterQueue := NewQueue()
+
+	emulator := NewEmulator(harness, queue,
+		WithReportBatchItemFailures(true),
+		WithVisibilityTimeout(10*time.Millisecond),
+		WithMaxReceiveCount(3),
+		WithDeadLetterQueue(deadLetterQueue),
+	)
+
+	require.Nil(t, emulator.Run(context.Background()))
+	require.Equal(t, []string{"a", "b"}, handler.processed)
+	require.Equal(t, []string{"1", "2", "3"}, handler.receiveCounts["poison"])
+	require.Equal(t, []string{"1", "2"}, handler.receiveCounts["b"])
+
+	messages := deadLetterQueue.Messages()
+	require.Len(t, messages, 1)
+	require.Equal(t, "poison", string(messages[0].Body))
+}
+
+func TestEmulatorSQSFunctionError(t *testing.T) {
+	handler := &sqsRecordHandler{failures: map[string]int{"b": 1}}
+	harness := lambdabasetest.Start(t, lambdabase.NewSQSRecordServer(handler))
+	queue := sendAll(NewQueue(), "a", "b")
+
+	// Without partial batch failures, the entire batch is redelivered
+	emulator := NewEmulator(harness, queue, WithBatchWindow(time.Second), WithVisibilityTimeout(10*time.Millisecond))
+	require.Nil(t, emulator.Run(context.Background()))
+	require.Equal(t, []string{"1", "2"}, handler.receiveCounts["a"])
+	require.Equal(t, []string{"a", "a", "b"}, handler.processed)
+}
+
+func TestEmulatorInvalidBatchItemFailures(t *testing.T) {
+	for _, payload := range []string{
+		`{"batchItemFailures":[{"itemIdentifier":"unknown"}]}`,
+		`{"batchItemFailures":[{"itemIdentifier":""}]}`,
+		`{"batchItemFailures":[{}]}`,
+		`not json`,
+	} {
+		attempts := 0
+		handler := lambdabase.LambdaHandlerFunc(func(ctx context.Context, _ []byte) ([]byte, error) {
+			if attempts++; attempts == 1 {
+				return []byte(payload), nil
+			}
+
+			return nil, nil
+		})
+
+		harness := lambdabasetest.Start(t, lambdabase.NewServer(&wrappedHandler{LambdaHandlerFunc: handler}))
+		queue := sendAll(NewQueue(), "a")
+
+		emulator := NewEmulator(harness, queue, WithReportBatchItemFailures(true), WithVisibilityTimeout(time.Millisecond))
+		require.Nil(t, emulator.Run(context.Background()))
+		require.Equal(t, 2, attempts, "payload %s", payload)
+	}
+}
+
+func TestEmulatorKinesisRetriesFromFailure(t *testing.T) {
+	handler := &kinesisRecordHandler{failures: map[string]int{"c": 2}}
+	harness := lambdabasetest.Start(t, lambdabase.NewKinesisRecordServer(handler, lambdabase.WithReportBatchItemFailures(true)))
+	function := &kinesisFunction{Function: harness}
+	queue := sendAll(NewQueue(), "a", "b", "c", "d", "e")
+
+	emulator := NewEmulator(function, queue,
+		WithEventSource(EventSourceKinesis),
+		WithBatchSize(4),
+		WithBatchWindow(time.Second),
+		WithReportBatchItemFailures(true),
+		WithRetryDelay(10*time.Millisecond),
+	)
+
+	require.Nil(t, emulator.Run(context.Background()))
+	require.Equal(t, []string{"a", "b", "c", "d", "e"}, handler.processed)
+
+	// Each retry starts at the failed record and includes the records after it
+	require.Equal(t, [][]string{{"a", "b", "c", "d"}, {"c", "d", "e"}, {"c", "d", "e"}}, function.batches)
+}
+
+func TestEmulatorKinesisMaxRetryAttempts(t *testing.T) {
+	handler := &kinesisRecordHandler{failures: map[string]int{"b": -1}}
+	harness := lambdabasetest.Start(t, lambdabase.NewKinesisRecordServer(handler))
+	function := &kinesisFunction{Function: harness}
+	queue := sendAll(NewQueue(), "a", "b")
+	deadLetterQueue := NewQueue()
+
+	emulator := NewEmulator(function, queue,
+		WithEventSource(EventSourceKinesis),
+		WithBatchWindow(time.Second),
+		WithMaxRetryAttempts(2),
+		WithRetryDelay(time.Millisecond),
+		WithDeadLetterQueue(deadLetterQueue),
+	)
+
+	require.Nil(t, emulator.Run(context.Background()))
+	require.Len(t, function.batches, 3)
+	require.Equal(t, 2, deadLetterQueue.Len())
+}
+
+func TestEmulatorKinesisPartitionKeys(t *testing.T) {
+	handler := &kinesisRecordHandler{}
+	harness := lambdabasetest.Start(t, lambdabase.NewKinesisRecordServer(handler))
+	function := &kinesisFunction{Function: harness}
+	queue := sendAll(NewQueue(), `{"user":"a"}`, `{"user":"b"}`, `{}`)
+
+	emulator := NewEmulator(function, queue, WithEventSource(EventSourceKinesis), WithBatchWindow(time.Second))
+	require.Nil(t, emulator.Run(context.Background()))
+	require.Equal(t, []string{defaultPartitionKey, defaultPartitionKey, defaultPartitionKey}, function.partitionKeys)
+}
+
+func TestEmulatorKinesisPartitionKeyField(t *testing.T) {
+	handler := &kinesisRecordHandler{}
+	harness := lambdabasetest.Start(t, lambdabase.NewKinesisRecordServer(handler))
+	function := &kinesisFunction{Function: harness}
+	deadLetterQueue := NewQueue()
+	queue := sendAll(NewQueue(), `{"user":"a"}`, `{"user":"b"}`, `{}`)
+
+	emulator := NewEmulator(function, queue,
+		WithEventSource(EventSourceKinesis),
+		WithBatchWindow(time.Second),
+		WithPartitionKeyField("user"),
+		WithDeadLetterQueue(deadLetterQueue),
+	)
+
+	require.Nil(t, emulator.Run(context.Background()))
+	require.Equal(t, []string{"a", "b"}, function.partitionKeys)
+	require.Equal(t, 1, deadLetterQueue.Len())
+}
+
+func TestEmulatorDynamoDB(t *testing.T) {
+	handler := &dynamoDBRecordHandler{}
+	harness := lambdabasetest.Start(t, lambdabase.NewDynamoDBRecordServer(handler))
+	queue := sendAll(NewQueue(),
+		`{"eventName":"MODIFY","dynamodb":{"Keys":{"id":{"S":"1"}}}}`,
+		`not json`,
+		`{"dynamodb":{"Keys":{"id":{"S":"2"}}}}`,
+	)
+	deadLetterQueue := NewQueue()
+
+	emulator := NewEmulator(harness, queue, WithEventSource(EventSourceDynamoDB), WithBatchWindow(time.Second), WithDeadLetterQueue(deadLetterQueue))
+	require.Nil(t, emulator.Run(context.Background()))
+	require.Len(t, handler.records, 2)
+	require.Equal(t, "MODIFY", handler.records[0].EventName)
+	require.Equal(t, "INSERT", handler.records[1].EventName)
+	require.Equal(t, "2", handler.records[1].Change.Keys["id"].String())
+	require.Less(t, handler.records[0].Change.SequenceNumber, handler.records[1].Change.SequenceNumber)
+	require.Equal(t, 1, deadLetterQueue.Len())
+}
+
+func TestEmulatorCanceled(t *testing.T) {
+	harness := lambdabasetest.Start(t, lambdabase.NewSQSEventServer(&sqsBatchHandler{}))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	require.ErrorIs(t, NewEmulator(harness, NewQueue()).Run(ctx), context.DeadlineExceeded)
+}
+
+func sendAll(queue *Queue, bodies ...string) *Queue {
+	for _, body := range bodies {
+		queue.Send([]byte(body))
+	}
+
+	queue.Close()
+	return queue
+}
+
+type sqsBatchHandler struct {
+	batches [][]string
+}
+
+func (h *sqsBatchHandler) Handle(ctx context.Context, batch []events.SQSMessage, logger nacelle.Logger) error {
+	var bodies []string
+	for _, message := range batch {
+		bodies = append(bodies, message.Body)
+	}
+
+	h.batches = append(h.batches, bodies)
+	return nil
+}
+
+// sqsRecordHandler fails each message whose body is a key of failures that
+// many times, or on every attempt if the value is negative.
+type sqsRecordHandler struct {
+	mutex         sync.Mutex
+	failures      map[string]int
+	processed     []string
+	receiveCounts map[string][]string
+}
+
+func (h *sqsRecordHandler) Handle(ctx context.Context, message events.SQSMessage, logger nacelle.Logger) error {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	if h.receiveCounts == nil {
+		h.receiveCounts = map[string][]string{}
+	}
+	h.receiveCounts[message.Body] = append(h.receiveCounts[message.Body], message.Attributes["ApproximateReceiveCount"])
+
+	if remaining := h.failures[message.Body]; remaining != 0 {
+		h.failures[message.Body] = remaining - 1
+		return fmt.Errorf("oops")
+	}
+
+	h.processed = append(h.processed, message.Body)
+	return nil
+}
+
+type kinesisRecordHandler struct {
+	failures  map[string]int
+	processed []string
+}
+
+func (h *kinesisRecordHandler) Handle(ctx context.Context, record events.KinesisEventRecord, logger nacelle.Logger) error {
+	data := string(record.Kinesis.Data)
+	if remaining := h.failures[data]; remaining != 0 {
+		h.failures[data] = remaining - 1
+		return fmt.Errorf("oops")
+	}
+
+	h.processed = append(h.processed, data)
+	return nil
+}
+
+type dynamoDBRecordHandler struct {
+	records []events.DynamoDBEventRecord
+}
+
+func (h *dynamoDBRecordHandler) Handle(ctx context.Context, record events.DynamoDBEventRecord, logger nacelle.Logger) error {
+	h.records = append(h.records, record)
+	return nil
+}
+
+// kinesisFunction records the data of each batch delivered to a function and
+// the partition key of each record.
+type kinesisFunction struct {
+	Function
+	batches       [][]string
+	partitionKeys []string
+}
+
+func (f *kinesisFunction) Invoke(payload []byte, configs ...rpcclient.InvokeConfigFunc) (*rpcclient.Response, error) {
+	event := events.KinesisEvent{}
+	if err := json.Unmarshal(payload, &event); err != nil {
+		return nil, err
+	}
+
+	var batch []string
+	for _, record := range event.Records {
+		batch = append(batch, string(record.Kinesis.Data))
+		f.partitionKeys = append(f.partitionKeys, record.Kinesis.PartitionKey)
+	}
+
+	f.batches = append(f.batches, batch)
+	return f.Function.Invoke(payload, configs...)
+}
+
+type wrappedHandler struct {
+	lambdabase.LambdaHandlerFunc
+}
+
+func (h *wrappedHandler) Init(ctx context.Context) error {
+	return nil
+}
@@ -0,0 +1,129 @@
+package lambdabaselocal
+
+import (
+	"time"
+
+	"github.com/go-nacelle/nacelle/v2"
+)
+
+type (
+	options struct {
+		eventSource             EventSource
+		batchSize               int
+		batchWindow             time.Duration
+		reportBatchItemFailures bool
+		visibilityTimeout       time.Duration
+		maxReceiveCount         int
+		maxRetryAttempts        int
+		retryDelay              time.Duration
+		partitionKeyField       string
+		deadLetterQueue         *Queue
+		logger                  nacelle.Logger
+	}
+
+	// ConfigFunc is a function used to configure an emulator.
+	ConfigFunc func(*options)
+)
+
+const (
+	defaultQueueBatchSize    = 10
+	defaultStreamBatchSize   = 100
+	defaultVisibilityTimeout = 30 * time.Second
+	defaultRetryDelay        = time.Second
+)
+
+// WithEventSource sets the type of event source mapping to emulate. The
+// default is EventSourceSQS.
+func WithEventSource(eventSource EventSource) ConfigFunc {
+	return func(o *options) { o.eventSource = eventSource }
+}
+
+// WithBatchSize sets the maximum number of records delivered in one event.
+// The default is 10 for SQS and 100 for Kinesis and DynamoDB.
+func WithBatchSize(batchSize int) ConfigFunc {
+	return func(o *options) { o.batchSize = batchSize }
+}
+
+// WithBatchWindow sets the maximum time to gather records after the first
+// record of a batch is received. By default, a batch is delivered as soon
+// as records are available.
+func WithBatchWindow(batchWindow time.Duration) ConfigFunc {
+	return func(o *options) { o.batchWindow = batchWindow }
+}
+
+// WithReportBatchItemFailures sets whether the function response is read for
+// partial batch failures. This should match the lambdabase server option of
+// the same name. If false, a batch either succeeds or fails entirely.
+func WithReportBatchItemFailures(reportBatchItemFailures bool) ConfigFunc {
+	return func(o *options) { o.reportBatchItemFailures = reportBatchItemFailures }
+}
+
+// WithVisibilityTimeout sets the time after which a failed SQS message is
+// delivered again. The default is 30 seconds.
+func WithVisibilityTimeout(visibilityTimeout time.Duration) ConfigFunc {
+	return func(o *options) { o.visibilityTimeout = visibilityTimeout }
+}
+
+// WithMaxReceiveCount sets the number of times an SQS message is delivered
+// before it is discarded or sent to the dead-letter queue. By default, failed
+// messages are delivered until they succeed.
+func WithMaxReceiveCount(maxReceiveCount int) ConfigFunc {
+	return func(o *options) { o.maxReceiveCount = maxReceiveCount }
+}
+
+// WithMaxRetryAttempts sets the number of times a failed Kinesis or DynamoDB
+// batch is retried before its records are discarded or sent to the
+// dead-letter queue. By default, failed batches are retried until they
+// succeed.
+func WithMaxRetryAttempts(maxRetryAttempts int) ConfigFunc {
+	return func(o *options) { o.maxRetryAttempts = maxRetryAttempts }
+}
+
+// WithRetryDelay sets the time after which a failed Kinesis or DynamoDB batch
+// is retried. The default is one second.
+func WithRetryDelay(retryDelay time.Duration) ConfigFunc {
+	return func(o *options) { o.retryDelay = retryDelay }
+}
+
+// WithPartitionKeyField sets the name of a top-level string field of the
+// JSON body of each message that holds the partition key of its Kinesis
+// record. A message that sets its own partition key takes precedence. By
+// default, every record has the same partition key, as the emulated stream
+// has a single shard.
+func WithPartitionKeyField(field string) ConfigFunc {
+	return func(o *options) { o.partitionKeyField = field }
+}
+
+// WithDeadLetterQueue sets the queue that receives messages discarded after
+// exhausting their retries or failing to decode.
+func WithDeadLetterQueue(queue *Queue) ConfigFunc {
+	return func(o *options) { o.deadLetterQueue = queue }
+}
+
+// WithLogger sets the logger of the emulator. The default logger discards all
+// messages.
+func WithLogger(logger nacelle.Logger) ConfigFunc {
+	return func(o *options) { o.logger = logger }
+}
+
+func getOptions(configs []ConfigFunc) *options {
+	options := &options{
+		eventSource:       EventSourceSQS,
+		visibilityTimeout: defaultVisibilityTimeout,
+		maxRetryAttempts:  -1,
+		retryDelay:        defaultRetryDelay,
+		logger:            nacelle.NewNilLogger(),
+	}
+	for _, f := range configs {
+		f(options)
+	}
+
+	if options.batchSize <= 0 {
+		options.batchSize = defaultQueueBatchSize
+		if options.eventSource.ordered() {
+			options.batchSize = defaultStreamBatchSize
+		}
+	}
+
+	return options
+}
@@ -0,0 +1,164 @@
+package lambdabaselocal
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/go-nacelle/lambdabase/lambdabasetest/fixtures"
+)
+
+// EventSource is the type of event source mapping emulated by an emulator.
+type EventSource string
+
+// defaultPartitionKey is the partition key of Kinesis records that do not
+// supply their own. The emulated stream has a single shard, so one key keeps
+// every record in the same ordered group.
+const defaultPartitionKey = "lambdabase-local"
+
+const (
+	// EventSourceSQS delivers messages as records of an events.SQSEvent.
+	// Failed messages are redelivered individually after the visibility
+	// timeout.
+	EventSourceSQS EventSource = "sqs"
+
+	// EventSourceKinesis delivers messages in order as records of an
+	// events.KinesisEvent. A failed batch is retried from the first failed
+	// record before any later record is delivered.
+	EventSourceKinesis EventSource = "kinesis"
+
+	// EventSourceDynamoDB delivers messages in order as records of an
+	// events.DynamoDBEvent. Each message body must be the JSON encoding of an
+	// events.DynamoDBEventRecord. Retries behave as for Kinesis.
+	EventSourceDynamoDB EventSource = "dynamodb"
+)
+
+// ordered returns true if records of the event source are delivered in
+// order, as they are by a stream.
+func (s EventSource) ordered() bool {
+	return s == EventSourceKinesis || s == EventSourceDynamoDB
+}
+
+// newRecord assigns an item identifier to a message read from the source.
+// Records of a stream are given increasing sequence numbers.
+func (e *Emulator) newRecord(message *Message) (*record, error) {
+	r := &record{message: message, id: message.ID, arrival: time.Now()}
+	if !e.options.eventSource.ordered() {
+		return r, nil
+	}
+
+	e.sequence++
+	r.id = fmt.Sprintf("%056d", e.sequence)
+
+	switch e.options.eventSource {
+	case EventSourceKinesis:
+		partitionKey, err := e.partitionKey(message)
+		if err != nil {
+			return nil, err
+		}
+		r.partitionKey = partitionKey
+
+	case EventSourceDynamoDB:
+		if err := json.Unmarshal(message.Body, &events.DynamoDBEventRecord{}); err != nil {
+			return nil, fmt.Errorf("failed to decode DynamoDB record %s (%w)", message.ID, err)
+		}
+	}
+
+	return r, nil
+}
+
+// partitionKey returns the partition key of the Kinesis record of the given
+// message. A message whose body lacks the configured partition key field is
+// rejected rather than assigned the default key.
+func (e *Emulator) partitionKey(message *Message) (string, error) {
+	if message.PartitionKey != "" {
+		return message.PartitionKey, nil
+	}
+
+	if e.options.partitionKeyField == "" {
+		return defaultPartitionKey, nil
+	}
+
+	fields := map[string]interface{}{}
+	if err := json.Unmarshal(message.Body, &fields); err != nil {
+		return "", fmt.Errorf("failed to decode partition key of Kinesis record %s (%w)", message.ID, err)
+	}
+
+	partitionKey, ok := fields[e.options.partitionKeyField].(string)
+	if !ok || partitionKey == "" {
+		return "", fmt.Errorf("Kinesis record %s has no string field %q", message.ID, e.options.partitionKeyField)
+	}
+
+	return partitionKey, nil
+}
+
+// makeEvent builds the event delivered to the function for the given batch.
+func (e *Emulator) makeEvent(batch []*record) interface{} {
+	switch e.options.eventSource {
+	case EventSourceKinesis:
+		event := fixtures.NewKinesisEvent()
+		for _, r := range batch {
+			event.Add(fixtures.NewKinesisRecord().
+				WithData(r.message.Body).
+				WithPartitionKey(r.partitionKey).
+				WithSequenceNumber(r.id).
+				WithArrivalTime(r.arrival))
+		}
+
+		return event.Build()
+
+	case EventSourceDynamoDB:
+		event := events.DynamoDBEvent{Records: make([]events.DynamoDBEventRecord, 0, len(batch))}
+		for _, r := range batch {
+			event.Records = append(event.Records, makeDynamoDBRecord(r))
+		}
+
+		return event
+
+	default:
+		event := fixtures.NewSQSEvent()
+		for _, r := range batch {
+			event.Add(fixtures.NewSQSMessage().
+				WithMessageID(r.message.ID).
+				WithBody(string(r.message.Body)).
+				WithReceiveCount(r.attempts).
+				WithAttribute("SentTimestamp", strconv.FormatInt(r.arrival.UnixMilli(), 10)).
+				WithAttribute("ApproximateFirstReceiveTimestamp", strconv.FormatInt(r.firstAttempt.UnixMilli(), 10)))
+		}
+
+		return event.Build()
+	}
+}
+
+// makeDynamoDBRecord decodes the stream record of the given message and fills
+// in the fields that identify it within the emulated stream.
+func makeDynamoDBRecord(r *record) events.DynamoDBEventRecord {
+	// The body was validated when the record was read from the source
+	dynamoRecord := events.DynamoDBEventRecord{}
+	_ = json.Unmarshal(r.message.Body, &dynamoRecord)
+
+	dynamoRecord.EventID = r.id[len(r.id)-32:]
+	dynamoRecord.EventSource = "aws:dynamodb"
+	dynamoRecord.EventSourceArn = fixtures.DefaultDynamoDBStreamARN
+	dynamoRecord.Change.SequenceNumber = r.id
+
+	if dynamoRecord.AWSRegion == "" {
+		dynamoRecord.AWSRegion = fixtures.Region
+	}
+	if dynamoRecord.EventVersion == "" {
+		dynamoRecord.EventVersion = "1.1"
+	}
+	if dynamoRecord.EventName == "" {
+		dynamoRecord.EventName = string(events.DynamoDBOperationTypeInsert)
+	}
+	if dynamoRecord.Change.StreamViewType == "" {
+		dynamoRecord.Change.StreamViewType = string(events.DynamoDBStreamViewTypeNewAndOldImages)
+	}
+	if dynamoRecord.Change.ApproximateCreationDateTime.IsZero() {
+		dynamoRecord.Change.ApproximateCreationDateTime = events.SecondsEpochTime{Time: r.arrival}
+	}
+
+	return dynamoRecord
+}
@@ -0,0 +1,237 @@
+package lambdabaselocal
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type (
+	// Message is a single record read from a source.
+	Message struct {
+		// ID identifies the message. It is used as the message ID of SQS
+		// records.
+		ID string
+
+		// PartitionKey is the partition key of Kinesis records. If empty,
+		// the partition key is read from the body of the message as
+		// configured by WithPartitionKeyField, or is the partition key
+		// shared by every record of the emulated shard.
+		PartitionKey string
+
+		// Body is the payload of the message. It is used as the body of
+		// SQS records, the data of Kinesis records, and is decoded as the
+		// stream record of DynamoDB records.
+		Body []byte
+	}
+
+	// Source produces the messages delivered to a function.
+	Source interface {
+		// Next blocks until a message is available. It returns io.EOF
+		// once the source is exhausted.
+		Next(ctx context.Context) (*Message, error)
+	}
+
+	directorySource struct {
+		dir          string
+		pollInterval time.Duration
+		seen         map[string]struct{}
+		pending      []string
+	}
+
+	readerSource struct {
+		scanner *bufio.Scanner
+	}
+
+	// Queue is an in-memory queue of messages. A queue is a source that
+	// blocks until a message is sent or the queue is closed. A queue can
+	// also be used as the dead-letter queue of an emulator.
+	Queue struct {
+		mutex    sync.Mutex
+		messages []*Message
+		closed   bool
+		ready    chan struct{}
+	}
+)
+
+// NewDirectorySource creates a source that reads each JSON file in the given
+// directory as a message, in file name order. The ID of a message is the name
+// of its file. If pollInterval is non-zero, the directory is scanned for new
+// files at that interval. Otherwise, the source is exhausted once the files
+// present at the first scan have been read.
+func NewDirectorySource(dir string, pollInterval time.Duration) Source {
+	return &directorySource{
+		dir:          dir,
+		pollInterval: pollInterval,
+		seen:         map[string]struct{}{},
+	}
+}
+
+func (s *directorySource) Next(ctx context.Context) (*Message, error) {
+	for len(s.pending) == 0 {
+		if err := s.scan(); err != nil {
+			return nil, err
+		}
+
+		if len(s.pending) > 0 {
+			break
+		}
+
+		if s.pollInterval == 0 {
+			return nil, io.EOF
+		}
+
+		select {
+		case <-time.After(s.pollInterval):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	name := s.pending[0]
+	s.pending = s.pending[1:]
+
+	body, err := os.ReadFile(filepath.Join(s.dir, name))
+	if err != nil {
+		return nil, err
+	}
+
+	return &Message{ID: name, Body: bytes.TrimSpace(body)}, nil
+}
+
+func (s *directorySource) scan() error {
+	// Unlike filepath.Glob, os.ReadDir fails if the directory is missing
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || filepath.Ext(name) != ".json" {
+			continue
+		}
+		if _, ok := s.seen[name]; ok {
+			continue
+		}
+
+		s.seen[name] = struct{}{}
+		s.pending = append(s.pending, name)
+	}
+
+	return nil
+}
+
+// NewReaderSource creates a source that reads each non-empty line of the
+// given reader, such as os.Stdin, as a message. Messages are given random
+// IDs. The source is exhausted at the end of the reader.
+func NewReaderSource(r io.Reader) Source {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxPayloadSize)
+	return &readerSource{scanner: scanner}
+}
+
+func (s *readerSource) Next(ctx context.Context) (*Message, error) {
+	for s.scanner.Scan() {
+		if line := bytes.TrimSpace(s.scanner.Bytes()); len(line) > 0 {
+			return &Message{ID: uuid.New().String(), Body: append([]byte(nil), line...)}, nil
+		}
+	}
+
+	if err := s.scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return nil, io.EOF
+}
+
+// NewQueue creates an empty queue.
+func NewQueue() *Queue {
+	return &Queue{ready: make(chan struct{})}
+}
+
+// Send appends a message with the given body to the queue and returns its
+// random ID. Messages sent to a closed queue are discarded.
+func (q *Queue) Send(body []byte) string {
+	id := uuid.New().String()
+	q.send(&Message{ID: id, Body: body})
+	return id
+}
+
+func (q *Queue) send(message *Message) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	if q.closed {
+		return
+	}
+
+	q.messages = append(q.messages, message)
+	q.notify()
+}
+
+// Close marks the queue as closed. A closed queue is exhausted once its
+// remaining messages have been read.
+func (q *Queue) Close() {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	q.closed = true
+	q.notify()
+}
+
+// Len returns the number of messages in the queue.
+func (q *Queue) Len() int {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	return len(q.messages)
+}
+
+// Messages returns a copy of the messages in the queue without removing
+// them.
+func (q *Queue) Messages() []*Message {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	return append([]*Message(nil), q.messages...)
+}
+
+func (q *Queue) Next(ctx context.Context) (*Message, error) {
+	for {
+		q.mutex.Lock()
+		if len(q.messages) > 0 {
+			message := q.messages[0]
+			q.messages = q.messages[1:]
+			q.mutex.Unlock()
+			return message, nil
+		}
+
+		closed, ready := q.closed, q.ready
+		q.mutex.Unlock()
+
+		if closed {
+			return nil, io.EOF
+		}
+
+		select {
+		case <-ready:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// notify wakes the readers blocked on the queue. The caller must hold the
+// queue mutex.
+func (q *Queue) notify() {
+	close(q.ready)
+	q.ready = make(chan struct{})
+}
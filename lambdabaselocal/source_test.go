@@ -0,0 +1,101 @@
+package lambdabaselocal
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDirectorySource(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "02.json", `{"id": 2}`)
+	writeFile(t, dir, "01.json", "{\"id\": 1}\n")
+	writeFile(t, dir, "03.txt", `ignored`)
+
+	source := NewDirectorySource(dir, 0)
+	require.Equal(t, []*Message{
+		{ID: "01.json", Body: []byte(`{"id": 1}`)},
+		{ID: "02.json", Body: []byte(`{"id": 2}`)},
+	}, readAll(t, source))
+}
+
+func TestDirectorySourcePoll(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "01.json", `{"id": 1}`)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	source := NewDirectorySource(dir, 10*time.Millisecond)
+	message, err := source.Next(ctx)
+	require.Nil(t, err)
+	require.Equal(t, "01.json", message.ID)
+
+	go func() {
+		<-time.After(50 * time.Millisecond)
+		_ = os.WriteFile(filepath.Join(dir, "00.json"), []byte(`{"id": 0}`), 0644)
+	}()
+
+	message, err = source.Next(ctx)
+	require.Nil(t, err)
+	require.Equal(t, "00.json", message.ID)
+
+	cancel()
+	_, err = source.Next(ctx)
+	require.ErrorIs(t, err, context.Canceled)
+}
+
+func TestReaderSource(t *testing.T) {
+	messages := readAll(t, NewReaderSource(strings.NewReader("foo\n\n  bar  \nbaz")))
+	require.Len(t, messages, 3)
+
+	var bodies []string
+	for _, message := range messages {
+		require.NotEmpty(t, message.ID)
+		bodies = append(bodies, string(message.Body))
+	}
+
+	require.Equal(t, []string{"foo", "bar", "baz"}, bodies)
+}
+
+func TestQueue(t *testing.T) {
+	queue := NewQueue()
+	id := queue.Send([]byte("foo"))
+	require.Equal(t, 1, queue.Len())
+
+	go func() {
+		<-time.After(10 * time.Millisecond)
+		queue.Send([]byte("bar"))
+		queue.Close()
+		queue.Send([]byte("baz"))
+	}()
+
+	messages := readAll(t, queue)
+	require.Len(t, messages, 2)
+	require.Equal(t, id, messages[0].ID)
+	require.Equal(t, "bar", string(messages[1].Body))
+	require.Equal(t, 0, queue.Len())
+}
+
+func readAll(t *testing.T, source Source) []*Message {
+	var messages []*Message
+	for {
+		message, err := source.Next(context.Background())
+		if err == io.EOF {
+			return messages
+		}
+
+		require.Nil(t, err)
+		messages = append(messages, message)
+	}
+}
+
+func writeFile(t *testing.T, dir, name, content string) {
+	require.Nil(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0644))
+}
@@ -0,0 +1,120 @@
+package fixtures
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"fmt"
+
+	"github.com/aws/aws-lambda-go/events"
+)
+
+// CloudWatchLogsEventBuilder builds a CloudWatch Logs subscription event from
+// a batch of log events.
+type CloudWatchLogsEventBuilder struct {
+	data events.CloudwatchLogsData
+}
+
+const (
+	// DefaultCloudWatchLogGroup is the log group of CloudWatch Logs events.
+	DefaultCloudWatchLogGroup = "/aws/lambda/lambdabase-function"
+
+	cloudWatchLogEventIDPrefix = "3195310660696698337880902507980421114328961542429"
+)
+
+// NewCloudWatchLogsEvent creates a data message of the default log group
+// containing no log events.
+func NewCloudWatchLogsEvent() *CloudWatchLogsEventBuilder {
+	return &CloudWatchLogsEventBuilder{data: events.CloudwatchLogsData{
+		Owner:               AccountID,
+		LogGroup:            DefaultCloudWatchLogGroup,
+		LogStream:           "2024/01/15/[$LATEST]3c6f4e1b2a8d4f0e9b7c5a3d1e2f4a6b",
+		SubscriptionFilters: []string{"lambdabase-filter"},
+		MessageType:         "DATA_MESSAGE",
+		LogEvents:           []events.CloudwatchLogsLogEvent{},
+	}}
+}
+
+// NewCloudWatchLogsBatch creates a data message containing n log events.
+func NewCloudWatchLogsBatch(n int) *CloudWatchLogsEventBuilder {
+	b := NewCloudWatchLogsEvent()
+	for i := 1; i <= n; i++ {
+		b.AddLogEvent(fmt.Sprintf("log message %d", i))
+	}
+
+	return b
+}
+
+// WithLogGroup sets the log group of the event.
+func (b *CloudWatchLogsEventBuilder) WithLogGroup(logGroup string) *CloudWatchLogsEventBuilder {
+	b.data.LogGroup = logGroup
+	return b
+}
+
+// WithLogStream sets the log stream of the event.
+func (b *CloudWatchLogsEventBuilder) WithLogStream(logStream string) *CloudWatchLogsEventBuilder {
+	b.data.LogStream = logStream
+	return b
+}
+
+// WithSubscriptionFilters sets the names of the subscription filters that
+// matched the log events.
+func (b *CloudWatchLogsEventBuilder) WithSubscriptionFilters(filters ...string) *CloudWatchLogsEventBuilder {
+	b.data.SubscriptionFilters = filters
+	return b
+}
+
+// AsControlMessage makes the event a control message, which CloudWatch Logs
+// sends to check that the destination is reachable.
+func (b *CloudWatchLogsEventBuilder) AsControlMessage() *CloudWatchLogsEventBuilder {
+	b.data.MessageType = "CONTROL_MESSAGE"
+	b.data.LogGroup = ""
+	b.data.LogStream = ""
+	b.data.SubscriptionFilters = []string{}
+	b.data.LogEvents = []events.CloudwatchLogsLogEvent{{
+		ID:        "",
+		Timestamp: Time.UnixMilli(),
+		Message:   "CWL CONTROL MESSAGE: Checking health of destination Lambda function.",
+	}}
+	return b
+}
+
+// AddLogEvent appends a log event with the given message. The ID and
+// timestamp of the log event are derived from its position in the event.
+func (b *CloudWatchLogsEventBuilder) AddLogEvent(message string) *CloudWatchLogsEventBuilder {
+	n := len(b.data.LogEvents) + 1
+
+	b.data.LogEvents = append(b.data.LogEvents, events.CloudwatchLogsLogEvent{
+		ID:        recordDigits(cloudWatchLogEventIDPrefix, 56, n),
+		Timestamp: recordTime(n).UnixMilli(),
+		Message:   message,
+	})
+	return b
+}
+
+// Data returns the decoded payload of the event.
+func (b *CloudWatchLogsEventBuilder) Data() events.CloudwatchLogsData {
+	data := b.data
+	data.LogEvents = append([]events.CloudwatchLogsLogEvent{}, b.data.LogEvents...)
+	return data
+}
+
+// Build returns the event. The payload is compressed and encoded as it is in
+// events sent by CloudWatch Logs.
+func (b *CloudWatchLogsEventBuilder) Build() events.CloudwatchLogsEvent {
+	buf := &bytes.Buffer{}
+	writer := gzip.NewWriter(buf)
+	_, _ = writer.Write(mustMarshal(b.Data()))
+	_ = writer.Close()
+
+	return events.CloudwatchLogsEvent{
+		AWSLogs: events.CloudwatchLogsRawData{
+			Data: base64.StdEncoding.EncodeToString(buf.Bytes()),
+		},
+	}
+}
+
+// JSON returns the JSON encoding of the event.
+func (b *CloudWatchLogsEventBuilder) JSON() []byte {
+	return mustMarshal(b.Build())
+}
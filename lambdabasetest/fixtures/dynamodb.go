@@ -0,0 +1,216 @@
+package fixtures
+
+import (
+	"fmt"
+
+	"github.com/aws/aws-lambda-go/events"
+)
+
+type (
+	// DynamoDBRecordBuilder builds a record of a DynamoDB stream event.
+	DynamoDBRecordBuilder struct {
+		n      int
+		record events.DynamoDBEventRecord
+	}
+
+	// DynamoDBEventBuilder builds a DynamoDB stream event from a batch of
+	// records.
+	DynamoDBEventBuilder struct {
+		records []*DynamoDBRecordBuilder
+	}
+
+	// DynamoDBImage is an item of a DynamoDB table as it appears in a
+	// stream record.
+	DynamoDBImage = map[string]events.DynamoDBAttributeValue
+)
+
+// DefaultDynamoDBStreamARN is the ARN of the table stream that delivers
+// DynamoDB records.
+const DefaultDynamoDBStreamARN = "arn:aws:dynamodb:" + Region + ":" + AccountID + ":table/lambdabase-table/stream/2024-01-01T00:00:00.000"
+
+// NewDynamoDBRecord creates a record for the insertion of an item into a table
+// whose stream captures new and old images.
+func NewDynamoDBRecord() *DynamoDBRecordBuilder {
+	return newDynamoDBRecord(1)
+}
+
+func newDynamoDBRecord(n int) *DynamoDBRecordBuilder {
+	keys := DynamoDBImage{
+		"id": events.NewStringAttribute(fmt.Sprintf("item-%d", n)),
+	}
+
+	return &DynamoDBRecordBuilder{n: n, record: events.DynamoDBEventRecord{
+		AWSRegion:      Region,
+		EventID:        fmt.Sprintf("%032x", n),
+		EventName:      string(events.DynamoDBOperationTypeInsert),
+		EventSource:    "aws:dynamodb",
+		EventVersion:   "1.1",
+		EventSourceArn: DefaultDynamoDBStreamARN,
+		Change: events.DynamoDBStreamRecord{
+			ApproximateCreationDateTime: events.SecondsEpochTime{Time: recordTime(n)},
+			Keys:                        keys,
+			NewImage: DynamoDBImage{
+				"id":    keys["id"],
+				"count": events.NewNumberAttribute(fmt.Sprintf("%d", n)),
+			},
+			SequenceNumber: recordDigits("1", 21, n),
+			StreamViewType: string(events.DynamoDBStreamViewTypeNewAndOldImages),
+		},
+	}}
+}
+
+// WithEventName sets the operation of the record to INSERT, MODIFY, or REMOVE.
+func (b *DynamoDBRecordBuilder) WithEventName(eventName events.DynamoDBOperationType) *DynamoDBRecordBuilder {
+	b.record.EventName = string(eventName)
+	return b
+}
+
+// WithKeys sets the primary key attributes of the item.
+func (b *DynamoDBRecordBuilder) WithKeys(keys DynamoDBImage) *DynamoDBRecordBuilder {
+	b.record.Change.Keys = keys
+	return b
+}
+
+// WithNewImage sets the item as it appeared after the modification.
+func (b *DynamoDBRecordBuilder) WithNewImage(image DynamoDBImage) *DynamoDBRecordBuilder {
+	b.record.Change.NewImage = image
+	return b
+}
+
+// WithOldImage sets the item as it appeared before the modification.
+func (b *DynamoDBRecordBuilder) WithOldImage(image DynamoDBImage) *DynamoDBRecordBuilder {
+	b.record.Change.OldImage = image
+	return b
+}
+
+// Insert makes the record the insertion of the given item.
+func (b *DynamoDBRecordBuilder) Insert(image DynamoDBImage) *DynamoDBRecordBuilder {
+	return b.WithEventName(events.DynamoDBOperationTypeInsert).WithOldImage(nil).WithNewImage(image)
+}
+
+// Modify makes the record the modification of the given item.
+func (b *DynamoDBRecordBuilder) Modify(oldImage, newImage DynamoDBImage) *DynamoDBRecordBuilder {
+	return b.WithEventName(events.DynamoDBOperationTypeModify).WithOldImage(oldImage).WithNewImage(newImage)
+}
+
+// Remove makes the record the removal of the given item.
+func (b *DynamoDBRecordBuilder) Remove(image DynamoDBImage) *DynamoDBRecordBuilder {
+	return b.WithEventName(events.DynamoDBOperationTypeRemove).WithOldImage(image).WithNewImage(nil)
+}
+
+// WithStreamViewType sets the images captured by the stream. Images that the
+// stream view type does not capture are omitted from the built record.
+func (b *DynamoDBRecordBuilder) WithStreamViewType(streamViewType events.DynamoDBStreamViewType) *DynamoDBRecordBuilder {
+	b.record.Change.StreamViewType = string(streamViewType)
+	return b
+}
+
+// WithSequenceNumber sets the sequence number of the record.
+func (b *DynamoDBRecordBuilder) WithSequenceNumber(sequenceNumber string) *DynamoDBRecordBuilder {
+	b.record.Change.SequenceNumber = sequenceNumber
+	return b
+}
+
+// WithStreamARN sets the ARN of the table stream that delivered the record.
+func (b *DynamoDBRecordBuilder) WithStreamARN(arn string) *DynamoDBRecordBuilder {
+	b.record.EventSourceArn = arn
+	return b
+}
+
+// WithTTLExpiry marks the record as the removal of an item by the Time to Live
+// process of the table.
+func (b *DynamoDBRecordBuilder) WithTTLExpiry() *DynamoDBRecordBuilder {
+	b.record.UserIdentity = &events.DynamoDBUserIdentity{
+		Type:        "Service",
+		PrincipalID: "dynamodb.amazonaws.com",
+	}
+	return b
+}
+
+// Build returns the record.
+func (b *DynamoDBRecordBuilder) Build() events.DynamoDBEventRecord {
+	record := b.record
+
+	switch events.DynamoDBStreamViewType(record.Change.StreamViewType) {
+	case events.DynamoDBStreamViewTypeKeysOnly:
+		record.Change.NewImage = nil
+		record.Change.OldImage = nil
+	case events.DynamoDBStreamViewTypeNewImage:
+		record.Change.OldImage = nil
+	case events.DynamoDBStreamViewTypeOldImage:
+		record.Change.NewImage = nil
+	}
+
+	record.Change.SizeBytes = int64(len(mustMarshal(record.Change.Keys)) + len(mustMarshal(record.Change.NewImage)) + len(mustMarshal(record.Change.OldImage)))
+	return record
+}
+
+// JSON returns the JSON encoding of the record.
+func (b *DynamoDBRecordBuilder) JSON() []byte {
+	return mustMarshal(b.Build())
+}
+
+// renumber derives the identifiers and default item of the record from the
+// given number.
+func (b *DynamoDBRecordBuilder) renumber(n int) {
+	old, new := newDynamoDBRecord(b.n).record, newDynamoDBRecord(n).record
+
+	renumber(&b.record.EventID, old.EventID, new.EventID)
+	renumber(&b.record.Change.ApproximateCreationDateTime, old.Change.ApproximateCreationDateTime, new.Change.ApproximateCreationDateTime)
+	renumber(&b.record.Change.Keys, old.Change.Keys, new.Change.Keys)
+	renumber(&b.record.Change.NewImage, old.Change.NewImage, new.Change.NewImage)
+	renumber(&b.record.Change.SequenceNumber, old.Change.SequenceNumber, new.Change.SequenceNumber)
+
+	b.n = n
+}
+
+// NewDynamoDBEvent creates an event containing the given records.
+func NewDynamoDBEvent(records ...*DynamoDBRecordBuilder) *DynamoDBEventBuilder {
+	return (&DynamoDBEventBuilder{}).Add(records...)
+}
+
+// NewDynamoDBBatch creates an event containing n records for the insertion of
+// distinct items with increasing sequence numbers.
+func NewDynamoDBBatch(n int) *DynamoDBEventBuilder {
+	b := &DynamoDBEventBuilder{}
+	for i := 1; i <= n; i++ {
+		b.records = append(b.records, newDynamoDBRecord(i))
+	}
+
+	return b
+}
+
+// Add appends the given records to the event. Each record is renumbered by
+// its position in the event.
+func (b *DynamoDBEventBuilder) Add(records ...*DynamoDBRecordBuilder) *DynamoDBEventBuilder {
+	for _, record := range records {
+		record.renumber(len(b.records) + 1)
+		b.records = append(b.records, record)
+	}
+
+	return b
+}
+
+// ForEach calls f with the index and builder of each record of the event.
+func (b *DynamoDBEventBuilder) ForEach(f func(i int, record *DynamoDBRecordBuilder)) *DynamoDBEventBuilder {
+	for i, record := range b.records {
+		f(i, record)
+	}
+
+	return b
+}
+
+// Build returns the event.
+func (b *DynamoDBEventBuilder) Build() events.DynamoDBEvent {
+	records := make([]events.DynamoDBEventRecord, 0, len(b.records))
+	for _, record := range b.records {
+		records = append(records, record.Build())
+	}
+
+	return events.DynamoDBEvent{Records: records}
+}
+
+// JSON returns the JSON encoding of the event.
+func (b *DynamoDBEventBuilder) JSON() []byte {
+	return mustMarshal(b.Build())
+}
@@ -0,0 +1,74 @@
+package fixtures
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/aws/aws-lambda-go/events"
+)
+
+// EventBridgeEventBuilder builds an EventBridge event.
+type EventBridgeEventBuilder struct {
+	event events.CloudWatchEvent
+}
+
+// DefaultEventBridgeRuleARN is the ARN of the rule that sends scheduled
+// events.
+const DefaultEventBridgeRuleARN = "arn:aws:events:" + Region + ":" + AccountID + ":rule/lambdabase-schedule"
+
+// NewEventBridgeEvent creates a custom event with the given source and
+// detail type and an empty detail.
+func NewEventBridgeEvent(source, detailType string) *EventBridgeEventBuilder {
+	return &EventBridgeEventBuilder{event: events.CloudWatchEvent{
+		Version:    "0",
+		ID:         recordUUID(1),
+		DetailType: detailType,
+		Source:     source,
+		AccountID:  AccountID,
+		Time:       Time,
+		Region:     Region,
+		Resources:  []string{},
+		Detail:     json.RawMessage(`{}`),
+	}}
+}
+
+// NewScheduledEvent creates an event sent by the default schedule rule.
+func NewScheduledEvent() *EventBridgeEventBuilder {
+	return NewEventBridgeEvent("aws.events", "Scheduled Event").WithResources(DefaultEventBridgeRuleARN)
+}
+
+// WithID sets the ID of the event.
+func (b *EventBridgeEventBuilder) WithID(id string) *EventBridgeEventBuilder {
+	b.event.ID = id
+	return b
+}
+
+// WithTime sets the time of the event.
+func (b *EventBridgeEventBuilder) WithTime(t time.Time) *EventBridgeEventBuilder {
+	b.event.Time = t
+	return b
+}
+
+// WithResources sets the ARNs of the resources involved in the event.
+func (b *EventBridgeEventBuilder) WithResources(resources ...string) *EventBridgeEventBuilder {
+	b.event.Resources = resources
+	return b
+}
+
+// WithDetail sets the detail of the event to the JSON encoding of v.
+func (b *EventBridgeEventBuilder) WithDetail(v interface{}) *EventBridgeEventBuilder {
+	b.event.Detail = mustMarshal(v)
+	return b
+}
+
+// Build returns the event.
+func (b *EventBridgeEventBuilder) Build() events.CloudWatchEvent {
+	event := b.event
+	event.Resources = append([]string{}, b.event.Resources...)
+	return event
+}
+
+// JSON returns the JSON encoding of the event.
+func (b *EventBridgeEventBuilder) JSON() []byte {
+	return mustMarshal(b.Build())
+}
@@ -0,0 +1,168 @@
+package fixtures
+
+import (
+	"fmt"
+
+	"github.com/aws/aws-lambda-go/events"
+)
+
+type (
+	// FirehoseRecordBuilder builds a record of a Firehose transformation
+	// event.
+	FirehoseRecordBuilder struct {
+		n      int
+		record events.KinesisFirehoseEventRecord
+	}
+
+	// FirehoseEventBuilder builds a Firehose transformation event from a
+	// batch of records.
+	FirehoseEventBuilder struct {
+		event   events.KinesisFirehoseEvent
+		records []*FirehoseRecordBuilder
+	}
+)
+
+// DefaultFirehoseDeliveryStreamARN is the ARN of the delivery stream that
+// sends Firehose records.
+const DefaultFirehoseDeliveryStreamARN = "arn:aws:firehose:" + Region + ":" + AccountID + ":deliverystream/lambdabase-delivery-stream"
+
+const firehoseRecordIDPrefix = "4954698668313554428650745793632162567570019247115"
+
+// NewFirehoseRecord creates a record put directly to the delivery stream.
+func NewFirehoseRecord() *FirehoseRecordBuilder {
+	return newFirehoseRecord(1)
+}
+
+func newFirehoseRecord(n int) *FirehoseRecordBuilder {
+	return &FirehoseRecordBuilder{n: n, record: events.KinesisFirehoseEventRecord{
+		RecordID:                    recordDigits(firehoseRecordIDPrefix, 56, n),
+		ApproximateArrivalTimestamp: events.MilliSecondsEpochTime{Time: recordTime(n)},
+		Data:                        []byte(fmt.Sprintf(`{"id":%d}`, n)),
+	}}
+}
+
+// WithRecordID sets the ID of the record.
+func (b *FirehoseRecordBuilder) WithRecordID(id string) *FirehoseRecordBuilder {
+	b.record.RecordID = id
+	return b
+}
+
+// WithData sets the data of the record.
+func (b *FirehoseRecordBuilder) WithData(data []byte) *FirehoseRecordBuilder {
+	b.record.Data = data
+	return b
+}
+
+// WithJSONData sets the data of the record to the JSON encoding of v.
+func (b *FirehoseRecordBuilder) WithJSONData(v interface{}) *FirehoseRecordBuilder {
+	return b.WithData(mustMarshal(v))
+}
+
+// WithKinesisMetadata sets the metadata of a record read by the delivery
+// stream from a Kinesis data stream.
+func (b *FirehoseRecordBuilder) WithKinesisMetadata(partitionKey string) *FirehoseRecordBuilder {
+	b.record.KinesisFirehoseRecordMetadata = events.KinesisFirehoseRecordMetadata{
+		ShardID:                     DefaultKinesisShardID,
+		PartitionKey:                partitionKey,
+		SequenceNumber:              recordDigits(kinesisSequencePrefix, 56, b.n),
+		ApproximateArrivalTimestamp: b.record.ApproximateArrivalTimestamp,
+	}
+	return b
+}
+
+// Build returns the record.
+func (b *FirehoseRecordBuilder) Build() events.KinesisFirehoseEventRecord {
+	return b.record
+}
+
+// JSON returns the JSON encoding of the record.
+func (b *FirehoseRecordBuilder) JSON() []byte {
+	return mustMarshal(b.Build())
+}
+
+// renumber derives the identifiers of the record from the given number.
+func (b *FirehoseRecordBuilder) renumber(n int) {
+	old, new := newFirehoseRecord(b.n).record, newFirehoseRecord(n).record
+
+	renumber(&b.record.RecordID, old.RecordID, new.RecordID)
+	renumber(&b.record.ApproximateArrivalTimestamp, old.ApproximateArrivalTimestamp, new.ApproximateArrivalTimestamp)
+	renumber(&b.record.Data, old.Data, new.Data)
+
+	b.n = n
+}
+
+// NewFirehoseEvent creates an event of the default delivery stream containing
+// the given records.
+func NewFirehoseEvent(records ...*FirehoseRecordBuilder) *FirehoseEventBuilder {
+	return (&FirehoseEventBuilder{
+		event: events.KinesisFirehoseEvent{
+			InvocationID:      recordUUID(0),
+			DeliveryStreamArn: DefaultFirehoseDeliveryStreamARN,
+			Region:            Region,
+		},
+	}).Add(records...)
+}
+
+// NewFirehoseBatch creates an event containing n records with distinct IDs.
+func NewFirehoseBatch(n int) *FirehoseEventBuilder {
+	b := NewFirehoseEvent()
+	for i := 1; i <= n; i++ {
+		b.records = append(b.records, newFirehoseRecord(i))
+	}
+
+	return b
+}
+
+// WithDeliveryStreamARN sets the ARN of the delivery stream.
+func (b *FirehoseEventBuilder) WithDeliveryStreamARN(arn string) *FirehoseEventBuilder {
+	b.event.DeliveryStreamArn = arn
+	return b
+}
+
+// WithSourceKinesisStream makes the delivery stream read from the default
+// Kinesis data stream. Each record is given Kinesis metadata with a distinct
+// partition key.
+func (b *FirehoseEventBuilder) WithSourceKinesisStream() *FirehoseEventBuilder {
+	b.event.SourceKinesisStreamArn = DefaultKinesisStreamARN
+	for _, record := range b.records {
+		record.WithKinesisMetadata(fmt.Sprintf("partition-key-%d", record.n))
+	}
+
+	return b
+}
+
+// Add appends the given records to the event. Each record is renumbered by
+// its position in the event.
+func (b *FirehoseEventBuilder) Add(records ...*FirehoseRecordBuilder) *FirehoseEventBuilder {
+	for _, record := range records {
+		record.renumber(len(b.records) + 1)
+		b.records = append(b.records, record)
+	}
+
+	return b
+}
+
+// ForEach calls f with the index and builder of each record of the event.
+func (b *FirehoseEventBuilder) ForEach(f func(i int, record *FirehoseRecordBuilder)) *FirehoseEventBuilder {
+	for i, record := range b.records {
+		f(i, record)
+	}
+
+	return b
+}
+
+// Build returns the event.
+func (b *FirehoseEventBuilder) Build() events.KinesisFirehoseEvent {
+	event := b.event
+	event.Records = make([]events.KinesisFirehoseEventRecord, 0, len(b.records))
+	for _, record := range b.records {
+		event.Records = append(event.Records, record.Build())
+	}
+
+	return event
+}
+
+// JSON returns the JSON encoding of the event.
+func (b *FirehoseEventBuilder) JSON() []byte {
+	return mustMarshal(b.Build())
+}
@@ -0,0 +1,75 @@
+// Package fixtures builds realistic events for the event sources served by
+// lambdabase. Each builder produces both the event value and its JSON
+// encoding as delivered by Lambda. Builders are populated with values in the
+// shape AWS sends them, so a test only sets the fields it cares about.
+//
+// Records of a batch are numbered from one. The number of a record is used to
+// derive its identifiers, such as the message ID of an SQS message or the
+// sequence number of a Kinesis record, so that batches are deterministic.
+// Records added to an event are renumbered by their position in the event,
+// which updates each derived value that was not set explicitly.
+package fixtures
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"time"
+)
+
+const (
+	// Region is the default AWS region of events.
+	Region = "us-east-1"
+
+	// AccountID is the default AWS account ID of events.
+	AccountID = "123456789012"
+)
+
+// Time is the default time at which the first record of a batch was sent.
+// Each subsequent record was sent one second later.
+var Time = time.Date(2024, time.January, 15, 12, 0, 0, 0, time.UTC)
+
+// recordTime returns the time at which the record with the given number was
+// sent.
+func recordTime(n int) time.Time {
+	return Time.Add(time.Duration(n-1) * time.Second)
+}
+
+// recordUUID returns a UUID derived from the given record number.
+func recordUUID(n int) string {
+	return fmt.Sprintf("%08x-5f1a-4c3b-9d2e-%012x", n, n)
+}
+
+// recordDigits returns a decimal identifier of the given length whose final
+// digits are the given record number. Identifiers of later records compare
+// greater than identifiers of earlier records.
+func recordDigits(prefix string, length, n int) string {
+	return fmt.Sprintf("%s%0*d", prefix, length-len(prefix), n)
+}
+
+// renumber replaces the value of a field derived from the old number of a
+// record with the value derived from its new number, unless the field was set
+// to another value after the record was created.
+func renumber[T any](field *T, old, new T) {
+	if reflect.DeepEqual(*field, old) {
+		*field = new
+	}
+}
+
+func mustMarshal(v interface{}) []byte {
+	data, err := json.Marshal(v)
+	if err != nil {
+		panic(fmt.Sprintf("fixtures: failed to marshal %T (%s)", v, err.Error()))
+	}
+
+	return data
+}
+
+func copyMap[K comparable, V any](m map[K]V) map[K]V {
+	copied := make(map[K]V, len(m))
+	for key, value := range m {
+		copied[key] = value
+	}
+
+	return copied
+}
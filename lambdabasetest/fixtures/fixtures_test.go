@@ -0,0 +1,228 @@
+package fixtures
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/go-nacelle/lambdabase"
+	"github.com/go-nacelle/lambdabase/lambdabasetest"
+	"github.com/go-nacelle/nacelle/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSQSBatch(t *testing.T) {
+	event := NewSQSBatch(3).ForEach(func(i int, message *SQSMessageBuilder) {
+		message.WithJSONBody(map[string]int{"index": i})
+	}).Build()
+
+	require.Len(t, event.Records, 3)
+	for i, message := range event.Records {
+		require.Equal(t, fmt.Sprintf(`{"index":%d}`, i), message.Body)
+		require.Equal(t, DefaultSQSQueueARN, message.EventSourceARN)
+		require.Equal(t, "1", message.Attributes["ApproximateReceiveCount"])
+	}
+
+	require.NotEqual(t, event.Records[0].MessageId, event.Records[1].MessageId)
+	requireJSONRoundTrip(t, NewSQSBatch(3).JSON(), &events.SQSEvent{})
+}
+
+func TestSQSMessageGroupID(t *testing.T) {
+	message := NewSQSMessage().WithMessageGroupID("group").WithReceiveCount(3).Build()
+	require.Equal(t, "group", message.Attributes["MessageGroupId"])
+	require.Equal(t, "3", message.Attributes["ApproximateReceiveCount"])
+	require.Equal(t, DefaultSQSQueueARN+".fifo", message.EventSourceARN)
+
+	// The deduplication ID follows the body regardless of the option order
+	message = NewSQSMessage().WithMessageGroupID("group").WithBody("foo").Build()
+	require.Equal(t, NewSQSMessage().WithBody("foo").Build().Md5OfBody, message.Attributes["MessageDeduplicationId"])
+
+	message = NewSQSMessage().WithMessageGroupID("group").WithAttribute("MessageDeduplicationId", "d1").WithBody("foo").Build()
+	require.Equal(t, "d1", message.Attributes["MessageDeduplicationId"])
+
+	message = NewSQSMessage().Build()
+	require.NotContains(t, message.Attributes, "MessageDeduplicationId")
+}
+
+func TestSQSEventRenumbersMessages(t *testing.T) {
+	event := NewSQSEvent(NewSQSMessage(), NewSQSMessage()).Add(NewSQSMessage().WithMessageID("m3")).Build()
+	require.Len(t, event.Records, 3)
+
+	require.NotEqual(t, event.Records[0].MessageId, event.Records[1].MessageId)
+	require.NotEqual(t, event.Records[0].ReceiptHandle, event.Records[1].ReceiptHandle)
+	require.Equal(t, NewSQSBatch(2).Build().Records[1], event.Records[1])
+	require.Equal(t, "m3", event.Records[2].MessageId)
+
+	// Explicitly set values are kept
+	event = NewSQSEvent(NewSQSMessage(), NewSQSMessage().WithBody("foo").WithMessageGroupID("group")).Build()
+	require.Equal(t, "foo", event.Records[1].Body)
+	require.Equal(t, NewSQSBatch(2).Build().Records[1].MessageId, event.Records[1].MessageId)
+	require.Less(t, event.Records[0].Attributes["SentTimestamp"], event.Records[1].Attributes["SentTimestamp"])
+}
+
+func TestKinesisBatch(t *testing.T) {
+	event := NewKinesisBatch(3).Build()
+	require.Len(t, event.Records, 3)
+
+	for i := 1; i < len(event.Records); i++ {
+		require.Less(t, event.Records[i-1].Kinesis.SequenceNumber, event.Records[i].Kinesis.SequenceNumber)
+	}
+
+	requireJSONRoundTrip(t, NewKinesisBatch(3).JSON(), &events.KinesisEvent{})
+}
+
+func TestKinesisEventRenumbersRecords(t *testing.T) {
+	event := NewKinesisEvent(NewKinesisRecord(), NewKinesisRecord().WithPartitionKey("pk")).Build()
+	require.Len(t, event.Records, 2)
+
+	require.Less(t, event.Records[0].Kinesis.SequenceNumber, event.Records[1].Kinesis.SequenceNumber)
+	require.NotEqual(t, event.Records[0].EventID, event.Records[1].EventID)
+	require.Equal(t, "pk", event.Records[1].Kinesis.PartitionKey)
+
+	event = NewKinesisEvent(NewKinesisRecord(), NewKinesisRecord().WithSequenceNumber("1")).Build()
+	require.Equal(t, "1", event.Records[1].Kinesis.SequenceNumber)
+}
+
+func TestDynamoDBStreamViewType(t *testing.T) {
+	image := DynamoDBImage{
+		"id":   events.NewStringAttribute("1"),
+		"name": events.NewStringAttribute("foo"),
+	}
+
+	record := NewDynamoDBRecord().
+		Modify(image, image).
+		WithStreamViewType(events.DynamoDBStreamViewTypeNewImage).
+		Build()
+
+	require.Equal(t, "MODIFY", record.EventName)
+	require.Equal(t, image, DynamoDBImage(record.Change.NewImage))
+	require.Empty(t, record.Change.OldImage)
+
+	requireJSONRoundTrip(t, NewDynamoDBBatch(3).JSON(), &events.DynamoDBEvent{})
+}
+
+func TestDynamoDBEventRenumbersRecords(t *testing.T) {
+	event := NewDynamoDBEvent(NewDynamoDBRecord(), NewDynamoDBRecord()).Build()
+	require.Len(t, event.Records, 2)
+
+	require.NotEqual(t, event.Records[0].EventID, event.Records[1].EventID)
+	require.Less(t, event.Records[0].Change.SequenceNumber, event.Records[1].Change.SequenceNumber)
+	require.Equal(t, NewDynamoDBBatch(2).Build().Records[1], event.Records[1])
+}
+
+func TestSNSBatch(t *testing.T) {
+	event := NewSNSBatch(2).ForEach(func(i int, record *SNSRecordBuilder) {
+		record.WithSubject("subject").WithMessageAttribute("index", fmt.Sprintf("%d", i))
+	}).Build()
+
+	require.Len(t, event.Records, 2)
+	require.Equal(t, "subject", event.Records[0].SNS.Subject)
+	require.Equal(t, map[string]interface{}{"Type": "String", "Value": "1"}, event.Records[1].SNS.MessageAttributes["index"])
+	require.NotEqual(t, event.Records[0].SNS.MessageID, event.Records[1].SNS.MessageID)
+
+	requireJSONRoundTrip(t, NewSNSBatch(2).JSON(), &events.SNSEvent{})
+}
+
+func TestS3Key(t *testing.T) {
+	record := NewS3Record().WithKey("uploads/hello world+1.txt").Build()
+	require.Equal(t, "uploads/hello+world%2B1.txt", record.S3.Object.Key)
+	require.Equal(t, "uploads/hello world+1.txt", record.S3.Object.URLDecodedKey)
+
+	requireJSONRoundTrip(t, NewS3Batch(2).JSON(), &events.S3Event{})
+}
+
+func TestKafkaBatch(t *testing.T) {
+	event := NewKafkaBatch(2).Add(NewKafkaRecord().WithPartition(1).WithHeader("h", []byte("v"))).Build()
+	require.Len(t, event.Records["lambdabase-topic-0"], 2)
+	require.Len(t, event.Records["lambdabase-topic-1"], 1)
+
+	record := event.Records["lambdabase-topic-0"][1]
+	require.Equal(t, int64(1), record.Offset)
+
+	value, err := base64.StdEncoding.DecodeString(record.Value)
+	require.Nil(t, err)
+	require.Equal(t, `{"id":2}`, string(value))
+
+	requireJSONRoundTrip(t, NewKafkaBatch(2).JSON(), &events.KafkaEvent{})
+}
+
+func TestCloudWatchLogsEvent(t *testing.T) {
+	builder := NewCloudWatchLogsBatch(2)
+	encoded, err := base64.StdEncoding.DecodeString(builder.Build().AWSLogs.Data)
+	require.Nil(t, err)
+
+	reader, err := gzip.NewReader(bytes.NewReader(encoded))
+	require.Nil(t, err)
+	payload, err := io.ReadAll(reader)
+	require.Nil(t, err)
+
+	data := events.CloudwatchLogsData{}
+	require.Nil(t, json.Unmarshal(payload, &data))
+	require.Equal(t, builder.Data(), data)
+	require.Equal(t, "log message 2", data.LogEvents[1].Message)
+}
+
+func TestFirehoseSourceKinesisStream(t *testing.T) {
+	event := NewFirehoseBatch(2).WithSourceKinesisStream().Build()
+	require.Equal(t, DefaultKinesisStreamARN, event.SourceKinesisStreamArn)
+	require.Equal(t, "partition-key-2", event.Records[1].KinesisFirehoseRecordMetadata.PartitionKey)
+
+	requireJSONRoundTrip(t, NewFirehoseBatch(2).JSON(), &events.KinesisFirehoseEvent{})
+}
+
+func TestScheduledEvent(t *testing.T) {
+	event := NewScheduledEvent().Build()
+	require.Equal(t, "aws.events", event.Source)
+	require.Equal(t, "Scheduled Event", event.DetailType)
+	require.Equal(t, []string{DefaultEventBridgeRuleARN}, event.Resources)
+
+	requireJSONRoundTrip(t, NewEventBridgeEvent("app", "Created").WithDetail(map[string]int{"id": 1}).JSON(), &events.CloudWatchEvent{})
+}
+
+func TestFixturesInvokeServer(t *testing.T) {
+	handler := &sqsHandler{}
+	harness := lambdabasetest.Start(t, lambdabase.NewSQSRecordServer(handler, lambdabase.WithReportBatchItemFailures(true)))
+
+	event := NewSQSBatch(3).ForEach(func(i int, message *SQSMessageBuilder) {
+		if i == 1 {
+			message.WithMessageID("poison").WithBody("fail")
+		}
+	})
+
+	response := lambdabasetest.Invoke[events.SQSEventResponse](t, harness, event.Build())
+	require.Equal(t, []events.SQSBatchItemFailure{{ItemIdentifier: "poison"}}, response.BatchItemFailures)
+	require.ElementsMatch(t, []string{`{"id":1}`, `{"id":3}`}, handler.bodies)
+}
+
+// requireJSONRoundTrip asserts that the given JSON decodes into v and encodes
+// back to the same document.
+func requireJSONRoundTrip(t *testing.T, payload []byte, v interface{}) {
+	require.Nil(t, json.Unmarshal(payload, v))
+
+	encoded, err := json.Marshal(v)
+	require.Nil(t, err)
+	require.JSONEq(t, string(payload), string(encoded))
+}
+
+type sqsHandler struct {
+	mutex  sync.Mutex
+	bodies []string
+}
+
+func (h *sqsHandler) Handle(ctx context.Context, message events.SQSMessage, logger nacelle.Logger) error {
+	if message.Body == "fail" {
+		return fmt.Errorf("oops")
+	}
+
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	h.bodies = append(h.bodies, message.Body)
+	return nil
+}
@@ -0,0 +1,202 @@
+package fixtures
+
+import (
+	"encoding/base64"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-lambda-go/events"
+)
+
+type (
+	// KafkaRecordBuilder builds a record of a Kafka event.
+	KafkaRecordBuilder struct {
+		n      int
+		record events.KafkaRecord
+	}
+
+	// KafkaEventBuilder builds a Kafka event from a batch of records.
+	KafkaEventBuilder struct {
+		event   events.KafkaEvent
+		records []*KafkaRecordBuilder
+	}
+)
+
+const (
+	// DefaultKafkaClusterARN is the ARN of the MSK cluster that delivers
+	// Kafka records.
+	DefaultKafkaClusterARN = "arn:aws:kafka:" + Region + ":" + AccountID + ":cluster/lambdabase-cluster/6dd73ca6-1dd0-4c73-9d50-06d9b9d0ff4b-2"
+
+	// DefaultKafkaTopic is the topic of Kafka records.
+	DefaultKafkaTopic = "lambdabase-topic"
+)
+
+// NewKafkaRecord creates a record at the first offset of the first partition
+// of the default topic.
+func NewKafkaRecord() *KafkaRecordBuilder {
+	return newKafkaRecord(1)
+}
+
+func newKafkaRecord(n int) *KafkaRecordBuilder {
+	b := &KafkaRecordBuilder{n: n, record: events.KafkaRecord{
+		Topic:         DefaultKafkaTopic,
+		Partition:     0,
+		Offset:        int64(n - 1),
+		Timestamp:     events.MilliSecondsEpochTime{Time: recordTime(n)},
+		TimestampType: "CREATE_TIME",
+		Headers:       []map[string]events.JSONNumberBytes{},
+	}}
+
+	return b.WithKey([]byte(fmt.Sprintf("key-%d", n))).WithValue([]byte(fmt.Sprintf(`{"id":%d}`, n)))
+}
+
+// WithTopic sets the topic of the record.
+func (b *KafkaRecordBuilder) WithTopic(topic string) *KafkaRecordBuilder {
+	b.record.Topic = topic
+	return b
+}
+
+// WithPartition sets the partition of the record.
+func (b *KafkaRecordBuilder) WithPartition(partition int64) *KafkaRecordBuilder {
+	b.record.Partition = partition
+	return b
+}
+
+// WithOffset sets the offset of the record within its partition.
+func (b *KafkaRecordBuilder) WithOffset(offset int64) *KafkaRecordBuilder {
+	b.record.Offset = offset
+	return b
+}
+
+// WithTimestamp sets the creation time of the record.
+func (b *KafkaRecordBuilder) WithTimestamp(t time.Time) *KafkaRecordBuilder {
+	b.record.Timestamp = events.MilliSecondsEpochTime{Time: t}
+	return b
+}
+
+// WithKey sets the key of the record. A nil key is omitted.
+func (b *KafkaRecordBuilder) WithKey(key []byte) *KafkaRecordBuilder {
+	b.record.Key = encodeKafkaBytes(key)
+	return b
+}
+
+// WithValue sets the value of the record. A nil value is omitted.
+func (b *KafkaRecordBuilder) WithValue(value []byte) *KafkaRecordBuilder {
+	b.record.Value = encodeKafkaBytes(value)
+	return b
+}
+
+// WithJSONValue sets the value of the record to the JSON encoding of v.
+func (b *KafkaRecordBuilder) WithJSONValue(v interface{}) *KafkaRecordBuilder {
+	return b.WithValue(mustMarshal(v))
+}
+
+// WithHeader appends a header to the record.
+func (b *KafkaRecordBuilder) WithHeader(key string, value []byte) *KafkaRecordBuilder {
+	b.record.Headers = append(b.record.Headers, map[string]events.JSONNumberBytes{key: value})
+	return b
+}
+
+// Build returns the record.
+func (b *KafkaRecordBuilder) Build() events.KafkaRecord {
+	record := b.record
+	record.Headers = append([]map[string]events.JSONNumberBytes{}, b.record.Headers...)
+	return record
+}
+
+// JSON returns the JSON encoding of the record.
+func (b *KafkaRecordBuilder) JSON() []byte {
+	return mustMarshal(b.Build())
+}
+
+func encodeKafkaBytes(data []byte) string {
+	if data == nil {
+		return ""
+	}
+
+	return base64.StdEncoding.EncodeToString(data)
+}
+
+// renumber derives the offset, timestamp, key, and value of the record from
+// the given number.
+func (b *KafkaRecordBuilder) renumber(n int) {
+	old, new := newKafkaRecord(b.n).record, newKafkaRecord(n).record
+
+	renumber(&b.record.Offset, old.Offset, new.Offset)
+	renumber(&b.record.Timestamp, old.Timestamp, new.Timestamp)
+	renumber(&b.record.Key, old.Key, new.Key)
+	renumber(&b.record.Value, old.Value, new.Value)
+
+	b.n = n
+}
+
+// NewKafkaEvent creates an event of the default MSK cluster containing the
+// given records.
+func NewKafkaEvent(records ...*KafkaRecordBuilder) *KafkaEventBuilder {
+	return (&KafkaEventBuilder{
+		event: events.KafkaEvent{
+			EventSource:      "aws:kafka",
+			EventSourceARN:   DefaultKafkaClusterARN,
+			BootstrapServers: "b-1.lambdabase-cluster.abc123.c2.kafka." + Region + ".amazonaws.com:9092,b-2.lambdabase-cluster.abc123.c2.kafka." + Region + ".amazonaws.com:9092",
+		},
+	}).Add(records...)
+}
+
+// NewKafkaBatch creates an event containing n records at consecutive offsets
+// of the first partition of the default topic.
+func NewKafkaBatch(n int) *KafkaEventBuilder {
+	b := NewKafkaEvent()
+	for i := 1; i <= n; i++ {
+		b.records = append(b.records, newKafkaRecord(i))
+	}
+
+	return b
+}
+
+// WithSelfManagedCluster makes the event an event of a self-managed Kafka
+// cluster with the given bootstrap servers.
+func (b *KafkaEventBuilder) WithSelfManagedCluster(bootstrapServers string) *KafkaEventBuilder {
+	b.event.EventSource = "SelfManagedKafka"
+	b.event.EventSourceARN = ""
+	b.event.BootstrapServers = bootstrapServers
+	return b
+}
+
+// Add appends the given records to the event. Each record is renumbered by
+// its position in the event.
+func (b *KafkaEventBuilder) Add(records ...*KafkaRecordBuilder) *KafkaEventBuilder {
+	for _, record := range records {
+		record.renumber(len(b.records) + 1)
+		b.records = append(b.records, record)
+	}
+
+	return b
+}
+
+// ForEach calls f with the index and builder of each record of the event.
+func (b *KafkaEventBuilder) ForEach(f func(i int, record *KafkaRecordBuilder)) *KafkaEventBuilder {
+	for i, record := range b.records {
+		f(i, record)
+	}
+
+	return b
+}
+
+// Build returns the event. Records are grouped by topic partition in the
+// order they were added.
+func (b *KafkaEventBuilder) Build() events.KafkaEvent {
+	event := b.event
+	event.Records = map[string][]events.KafkaRecord{}
+	for _, builder := range b.records {
+		record := builder.Build()
+		key := fmt.Sprintf("%s-%d", record.Topic, record.Partition)
+		event.Records[key] = append(event.Records[key], record)
+	}
+
+	return event
+}
+
+// JSON returns the JSON encoding of the event.
+func (b *KafkaEventBuilder) JSON() []byte {
+	return mustMarshal(b.Build())
+}
@@ -0,0 +1,180 @@
+package fixtures
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-lambda-go/events"
+)
+
+type (
+	// KinesisRecordBuilder builds a record of a Kinesis event.
+	KinesisRecordBuilder struct {
+		n       int
+		shardID string
+		record  events.KinesisEventRecord
+	}
+
+	// KinesisEventBuilder builds a Kinesis event from a batch of records.
+	KinesisEventBuilder struct {
+		records []*KinesisRecordBuilder
+	}
+)
+
+const (
+	// DefaultKinesisStreamARN is the ARN of the stream that delivers Kinesis
+	// records.
+	DefaultKinesisStreamARN = "arn:aws:kinesis:" + Region + ":" + AccountID + ":stream/lambdabase-stream"
+
+	// DefaultKinesisShardID is the ID of the shard of Kinesis records.
+	DefaultKinesisShardID = "shardId-000000000000"
+
+	kinesisSequencePrefix = "4959033827149025660855969253836157109592157598913"
+)
+
+// NewKinesisRecord creates a record of the default stream and shard.
+func NewKinesisRecord() *KinesisRecordBuilder {
+	return newKinesisRecord(1)
+}
+
+func newKinesisRecord(n int) *KinesisRecordBuilder {
+	b := &KinesisRecordBuilder{
+		n:       n,
+		shardID: DefaultKinesisShardID,
+		record: events.KinesisEventRecord{
+			AwsRegion:         Region,
+			EventName:         "aws:kinesis:record",
+			EventSource:       "aws:kinesis",
+			EventSourceArn:    DefaultKinesisStreamARN,
+			EventVersion:      "1.0",
+			InvokeIdentityArn: "arn:aws:iam::" + AccountID + ":role/lambdabase-role",
+			Kinesis: events.KinesisRecord{
+				ApproximateArrivalTimestamp: events.SecondsEpochTime{Time: recordTime(n)},
+				Data:                        []byte(fmt.Sprintf(`{"id":%d}`, n)),
+				PartitionKey:                fmt.Sprintf("partition-key-%d", n),
+				KinesisSchemaVersion:        "1.0",
+			},
+		},
+	}
+
+	return b.WithSequenceNumber(recordDigits(kinesisSequencePrefix, 56, n))
+}
+
+// WithData sets the data of the record.
+func (b *KinesisRecordBuilder) WithData(data []byte) *KinesisRecordBuilder {
+	b.record.Kinesis.Data = data
+	return b
+}
+
+// WithJSONData sets the data of the record to the JSON encoding of v.
+func (b *KinesisRecordBuilder) WithJSONData(v interface{}) *KinesisRecordBuilder {
+	return b.WithData(mustMarshal(v))
+}
+
+// WithPartitionKey sets the partition key of the record.
+func (b *KinesisRecordBuilder) WithPartitionKey(partitionKey string) *KinesisRecordBuilder {
+	b.record.Kinesis.PartitionKey = partitionKey
+	return b
+}
+
+// WithSequenceNumber sets the sequence number of the record and updates its
+// event ID to match.
+func (b *KinesisRecordBuilder) WithSequenceNumber(sequenceNumber string) *KinesisRecordBuilder {
+	b.record.Kinesis.SequenceNumber = sequenceNumber
+	b.record.EventID = b.shardID + ":" + sequenceNumber
+	return b
+}
+
+// WithShardID sets the shard of the record and updates its event ID to match.
+func (b *KinesisRecordBuilder) WithShardID(shardID string) *KinesisRecordBuilder {
+	b.shardID = shardID
+	return b.WithSequenceNumber(b.record.Kinesis.SequenceNumber)
+}
+
+// WithStreamARN sets the ARN of the stream that delivered the record.
+func (b *KinesisRecordBuilder) WithStreamARN(arn string) *KinesisRecordBuilder {
+	b.record.EventSourceArn = arn
+	return b
+}
+
+// WithArrivalTime sets the time at which the record arrived in the stream.
+func (b *KinesisRecordBuilder) WithArrivalTime(t time.Time) *KinesisRecordBuilder {
+	b.record.Kinesis.ApproximateArrivalTimestamp = events.SecondsEpochTime{Time: t}
+	return b
+}
+
+// Build returns the record.
+func (b *KinesisRecordBuilder) Build() events.KinesisEventRecord {
+	return b.record
+}
+
+// JSON returns the JSON encoding of the record.
+func (b *KinesisRecordBuilder) JSON() []byte {
+	return mustMarshal(b.Build())
+}
+
+// renumber derives the identifiers of the record from the given number.
+func (b *KinesisRecordBuilder) renumber(n int) {
+	old, new := newKinesisRecord(b.n).record.Kinesis, newKinesisRecord(n).record.Kinesis
+
+	renumber(&b.record.Kinesis.ApproximateArrivalTimestamp, old.ApproximateArrivalTimestamp, new.ApproximateArrivalTimestamp)
+	renumber(&b.record.Kinesis.Data, old.Data, new.Data)
+	renumber(&b.record.Kinesis.PartitionKey, old.PartitionKey, new.PartitionKey)
+
+	if b.record.Kinesis.SequenceNumber == old.SequenceNumber {
+		b.WithSequenceNumber(new.SequenceNumber)
+	}
+
+	b.n = n
+}
+
+// NewKinesisEvent creates an event containing the given records.
+func NewKinesisEvent(records ...*KinesisRecordBuilder) *KinesisEventBuilder {
+	return (&KinesisEventBuilder{}).Add(records...)
+}
+
+// NewKinesisBatch creates an event containing n records of the same shard
+// with increasing sequence numbers and distinct partition keys.
+func NewKinesisBatch(n int) *KinesisEventBuilder {
+	b := &KinesisEventBuilder{}
+	for i := 1; i <= n; i++ {
+		b.records = append(b.records, newKinesisRecord(i))
+	}
+
+	return b
+}
+
+// Add appends the given records to the event. Each record is renumbered by
+// its position in the event.
+func (b *KinesisEventBuilder) Add(records ...*KinesisRecordBuilder) *KinesisEventBuilder {
+	for _, record := range records {
+		record.renumber(len(b.records) + 1)
+		b.records = append(b.records, record)
+	}
+
+	return b
+}
+
+// ForEach calls f with the index and builder of each record of the event.
+func (b *KinesisEventBuilder) ForEach(f func(i int, record *KinesisRecordBuilder)) *KinesisEventBuilder {
+	for i, record := range b.records {
+		f(i, record)
+	}
+
+	return b
+}
+
+// Build returns the event.
+func (b *KinesisEventBuilder) Build() events.KinesisEvent {
+	records := make([]events.KinesisEventRecord, 0, len(b.records))
+	for _, record := range b.records {
+		records = append(records, record.Build())
+	}
+
+	return events.KinesisEvent{Records: records}
+}
+
+// JSON returns the JSON encoding of the event.
+func (b *KinesisEventBuilder) JSON() []byte {
+	return mustMarshal(b.Build())
+}
@@ -0,0 +1,181 @@
+package fixtures
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/aws/aws-lambda-go/events"
+)
+
+type (
+	// S3RecordBuilder builds a record of an S3 event notification.
+	S3RecordBuilder struct {
+		n      int
+		record events.S3EventRecord
+	}
+
+	// S3EventBuilder builds an S3 event notification from a batch of
+	// records.
+	S3EventBuilder struct {
+		records []*S3RecordBuilder
+	}
+)
+
+// DefaultS3Bucket is the name of the bucket of S3 event notifications.
+const DefaultS3Bucket = "lambdabase-bucket"
+
+// NewS3Record creates a notification for an object created by a PUT request.
+func NewS3Record() *S3RecordBuilder {
+	return newS3Record(1)
+}
+
+func newS3Record(n int) *S3RecordBuilder {
+	b := &S3RecordBuilder{n: n, record: events.S3EventRecord{
+		EventVersion:      "2.1",
+		EventSource:       "aws:s3",
+		AWSRegion:         Region,
+		EventTime:         recordTime(n),
+		EventName:         "ObjectCreated:Put",
+		PrincipalID:       events.S3UserIdentity{PrincipalID: "AWS:AIDAINPONIXQXHT3IKHL2"},
+		RequestParameters: events.S3RequestParameters{SourceIPAddress: "203.0.113.10"},
+		ResponseElements: map[string]string{
+			"x-amz-request-id": fmt.Sprintf("C3D13FE58DE4C8%02X", n%256),
+			"x-amz-id-2":       "FMyUVURIY8/IgAtTv8xRjskZQpcIZ9KG4V5Wp6S7S/JRWeUWerMUE5JgHvANOjpD",
+		},
+		S3: events.S3Entity{
+			SchemaVersion:   "1.0",
+			ConfigurationID: "lambdabase-notification",
+			Object: events.S3Object{
+				VersionID: "",
+				Sequencer: fmt.Sprintf("0065A51E%08X", n),
+			},
+		},
+	}}
+
+	return b.WithBucket(DefaultS3Bucket).WithKey(fmt.Sprintf("uploads/object-%d.json", n)).WithSize(1024)
+}
+
+// WithEventName sets the type of the notification, such as
+// ObjectRemoved:Delete.
+func (b *S3RecordBuilder) WithEventName(eventName string) *S3RecordBuilder {
+	b.record.EventName = eventName
+	return b
+}
+
+// WithBucket sets the name of the bucket of the object.
+func (b *S3RecordBuilder) WithBucket(name string) *S3RecordBuilder {
+	b.record.S3.Bucket = events.S3Bucket{
+		Name:          name,
+		OwnerIdentity: events.S3UserIdentity{PrincipalID: "A3NL1KOZZKExample"},
+		Arn:           "arn:aws:s3:::" + name,
+	}
+	return b
+}
+
+// WithKey sets the key of the object. The key is URL-encoded in the built
+// record as it is in notifications sent by S3.
+func (b *S3RecordBuilder) WithKey(key string) *S3RecordBuilder {
+	b.record.S3.Object.Key = strings.ReplaceAll(url.QueryEscape(key), "%2F", "/")
+	b.record.S3.Object.URLDecodedKey = key
+
+	sum := md5.Sum([]byte(key))
+	b.record.S3.Object.ETag = hex.EncodeToString(sum[:])
+	return b
+}
+
+// WithSize sets the size of the object in bytes.
+func (b *S3RecordBuilder) WithSize(size int64) *S3RecordBuilder {
+	b.record.S3.Object.Size = size
+	return b
+}
+
+// WithVersionID sets the version of the object in a versioned bucket.
+func (b *S3RecordBuilder) WithVersionID(versionID string) *S3RecordBuilder {
+	b.record.S3.Object.VersionID = versionID
+	return b
+}
+
+// Build returns the record.
+func (b *S3RecordBuilder) Build() events.S3EventRecord {
+	record := b.record
+	record.ResponseElements = copyMap(b.record.ResponseElements)
+	return record
+}
+
+// JSON returns the JSON encoding of the record.
+func (b *S3RecordBuilder) JSON() []byte {
+	return mustMarshal(b.Build())
+}
+
+// renumber derives the identifiers and object key of the record from the
+// given number.
+func (b *S3RecordBuilder) renumber(n int) {
+	old, new := newS3Record(b.n).record, newS3Record(n).record
+
+	renumber(&b.record.EventTime, old.EventTime, new.EventTime)
+	renumber(&b.record.S3.Object.Sequencer, old.S3.Object.Sequencer, new.S3.Object.Sequencer)
+
+	if b.record.ResponseElements["x-amz-request-id"] == old.ResponseElements["x-amz-request-id"] {
+		b.record.ResponseElements["x-amz-request-id"] = new.ResponseElements["x-amz-request-id"]
+	}
+
+	if b.record.S3.Object.URLDecodedKey == old.S3.Object.URLDecodedKey {
+		b.WithKey(new.S3.Object.URLDecodedKey)
+	}
+
+	b.n = n
+}
+
+// NewS3Event creates an event containing the given records.
+func NewS3Event(records ...*S3RecordBuilder) *S3EventBuilder {
+	return (&S3EventBuilder{}).Add(records...)
+}
+
+// NewS3Batch creates an event containing n notifications for distinct objects
+// of the default bucket.
+func NewS3Batch(n int) *S3EventBuilder {
+	b := &S3EventBuilder{}
+	for i := 1; i <= n; i++ {
+		b.records = append(b.records, newS3Record(i))
+	}
+
+	return b
+}
+
+// Add appends the given records to the event. Each record is renumbered by
+// its position in the event.
+func (b *S3EventBuilder) Add(records ...*S3RecordBuilder) *S3EventBuilder {
+	for _, record := range records {
+		record.renumber(len(b.records) + 1)
+		b.records = append(b.records, record)
+	}
+
+	return b
+}
+
+// ForEach calls f with the index and builder of each record of the event.
+func (b *S3EventBuilder) ForEach(f func(i int, record *S3RecordBuilder)) *S3EventBuilder {
+	for i, record := range b.records {
+		f(i, record)
+	}
+
+	return b
+}
+
+// Build returns the event.
+func (b *S3EventBuilder) Build() events.S3Event {
+	records := make([]events.S3EventRecord, 0, len(b.records))
+	for _, record := range b.records {
+		records = append(records, record.Build())
+	}
+
+	return events.S3Event{Records: records}
+}
+
+// JSON returns the JSON encoding of the event.
+func (b *S3EventBuilder) JSON() []byte {
+	return mustMarshal(b.Build())
+}
@@ -0,0 +1,165 @@
+package fixtures
+
+import (
+	"fmt"
+
+	"github.com/aws/aws-lambda-go/events"
+)
+
+type (
+	// SNSRecordBuilder builds a record of an SNS event.
+	SNSRecordBuilder struct {
+		n      int
+		record events.SNSEventRecord
+	}
+
+	// SNSEventBuilder builds an SNS event from a batch of records.
+	SNSEventBuilder struct {
+		records []*SNSRecordBuilder
+	}
+)
+
+// DefaultSNSTopicARN is the ARN of the topic that delivers SNS notifications.
+const DefaultSNSTopicARN = "arn:aws:sns:" + Region + ":" + AccountID + ":lambdabase-topic"
+
+// NewSNSRecord creates a notification of the default topic.
+func NewSNSRecord() *SNSRecordBuilder {
+	return newSNSRecord(1)
+}
+
+func newSNSRecord(n int) *SNSRecordBuilder {
+	return &SNSRecordBuilder{n: n, record: events.SNSEventRecord{
+		EventVersion:         "1.0",
+		EventSubscriptionArn: DefaultSNSTopicARN + ":" + recordUUID(n+1000),
+		EventSource:          "aws:sns",
+		SNS: events.SNSEntity{
+			Signature:         "tcc6faL2yUC6dgZdmrwh1Y4cGa/ebXEkAi6RibDsvpi+tE/1+82j...65r==",
+			MessageID:         recordUUID(n),
+			Type:              "Notification",
+			TopicArn:          DefaultSNSTopicARN,
+			MessageAttributes: map[string]interface{}{},
+			SignatureVersion:  "1",
+			Timestamp:         recordTime(n),
+			SigningCertURL:    "https://sns." + Region + ".amazonaws.com/SimpleNotificationService-ac565b8b1a6c5d002d285f9598aa1d9b.pem",
+			Message:           fmt.Sprintf(`{"id":%d}`, n),
+			UnsubscribeURL:    "https://sns." + Region + ".amazonaws.com/?Action=Unsubscribe&SubscriptionArn=" + DefaultSNSTopicARN + ":" + recordUUID(n+1000),
+		},
+	}}
+}
+
+// WithMessageID sets the ID of the notification.
+func (b *SNSRecordBuilder) WithMessageID(id string) *SNSRecordBuilder {
+	b.record.SNS.MessageID = id
+	return b
+}
+
+// WithMessage sets the message of the notification.
+func (b *SNSRecordBuilder) WithMessage(message string) *SNSRecordBuilder {
+	b.record.SNS.Message = message
+	return b
+}
+
+// WithJSONMessage sets the message of the notification to the JSON encoding
+// of v.
+func (b *SNSRecordBuilder) WithJSONMessage(v interface{}) *SNSRecordBuilder {
+	return b.WithMessage(string(mustMarshal(v)))
+}
+
+// WithSubject sets the subject of the notification.
+func (b *SNSRecordBuilder) WithSubject(subject string) *SNSRecordBuilder {
+	b.record.SNS.Subject = subject
+	return b
+}
+
+// WithMessageAttribute sets a string message attribute of the notification.
+func (b *SNSRecordBuilder) WithMessageAttribute(name, value string) *SNSRecordBuilder {
+	b.record.SNS.MessageAttributes[name] = map[string]interface{}{
+		"Type":  "String",
+		"Value": value,
+	}
+	return b
+}
+
+// WithTopicARN sets the ARN of the topic that published the notification.
+func (b *SNSRecordBuilder) WithTopicARN(arn string) *SNSRecordBuilder {
+	b.record.SNS.TopicArn = arn
+	return b
+}
+
+// Build returns the record.
+func (b *SNSRecordBuilder) Build() events.SNSEventRecord {
+	record := b.record
+	record.SNS.MessageAttributes = copyMap(b.record.SNS.MessageAttributes)
+	return record
+}
+
+// JSON returns the JSON encoding of the record.
+func (b *SNSRecordBuilder) JSON() []byte {
+	return mustMarshal(b.Build())
+}
+
+// renumber derives the identifiers and message of the record from the given
+// number.
+func (b *SNSRecordBuilder) renumber(n int) {
+	old, new := newSNSRecord(b.n).record, newSNSRecord(n).record
+
+	renumber(&b.record.EventSubscriptionArn, old.EventSubscriptionArn, new.EventSubscriptionArn)
+	renumber(&b.record.SNS.MessageID, old.SNS.MessageID, new.SNS.MessageID)
+	renumber(&b.record.SNS.Timestamp, old.SNS.Timestamp, new.SNS.Timestamp)
+	renumber(&b.record.SNS.Message, old.SNS.Message, new.SNS.Message)
+	renumber(&b.record.SNS.UnsubscribeURL, old.SNS.UnsubscribeURL, new.SNS.UnsubscribeURL)
+
+	b.n = n
+}
+
+// NewSNSEvent creates an event containing the given records.
+func NewSNSEvent(records ...*SNSRecordBuilder) *SNSEventBuilder {
+	return (&SNSEventBuilder{}).Add(records...)
+}
+
+// NewSNSBatch creates an event containing n notifications with distinct IDs.
+// Lambda delivers a single notification per event, but a batch is useful to
+// test the handling of each record.
+func NewSNSBatch(n int) *SNSEventBuilder {
+	b := &SNSEventBuilder{}
+	for i := 1; i <= n; i++ {
+		b.records = append(b.records, newSNSRecord(i))
+	}
+
+	return b
+}
+
+// Add appends the given records to the event. Each record is renumbered by
+// its position in the event.
+func (b *SNSEventBuilder) Add(records ...*SNSRecordBuilder) *SNSEventBuilder {
+	for _, record := range records {
+		record.renumber(len(b.records) + 1)
+		b.records = append(b.records, record)
+	}
+
+	return b
+}
+
+// ForEach calls f with the index and builder of each record of the event.
+func (b *SNSEventBuilder) ForEach(f func(i int, record *SNSRecordBuilder)) *SNSEventBuilder {
+	for i, record := range b.records {
+		f(i, record)
+	}
+
+	return b
+}
+
+// Build returns the event.
+func (b *SNSEventBuilder) Build() events.SNSEvent {
+	records := make([]events.SNSEventRecord, 0, len(b.records))
+	for _, record := range b.records {
+		records = append(records, record.Build())
+	}
+
+	return events.SNSEvent{Records: records}
+}
+
+// JSON returns the JSON encoding of the event.
+func (b *SNSEventBuilder) JSON() []byte {
+	return mustMarshal(b.Build())
+}
@@ -0,0 +1,209 @@
+package fixtures
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-lambda-go/events"
+)
+
+type (
+	// SQSMessageBuilder builds a message of an SQS event.
+	SQSMessageBuilder struct {
+		n       int
+		fifo    bool
+		message events.SQSMessage
+	}
+
+	// SQSEventBuilder builds an SQS event from a batch of messages.
+	SQSEventBuilder struct {
+		messages []*SQSMessageBuilder
+	}
+)
+
+// DefaultSQSQueueARN is the ARN of the queue that delivers SQS messages.
+const DefaultSQSQueueARN = "arn:aws:sqs:" + Region + ":" + AccountID + ":lambdabase-queue"
+
+// NewSQSMessage creates a message received for the first time from a
+// standard queue.
+func NewSQSMessage() *SQSMessageBuilder {
+	return newSQSMessage(1)
+}
+
+func newSQSMessage(n int) *SQSMessageBuilder {
+	sent := fmt.Sprintf("%d", recordTime(n).UnixMilli())
+
+	b := &SQSMessageBuilder{n: n, message: events.SQSMessage{
+		MessageId:     recordUUID(n),
+		ReceiptHandle: fmt.Sprintf("AQEBwJnKyrHigUMZj6rYigCgxlaS3SLy0a%06dOZu5B1ZSOW8vw==", n),
+		Attributes: map[string]string{
+			"ApproximateReceiveCount":          "1",
+			"SentTimestamp":                    sent,
+			"SenderId":                         "AIDAIENQZJOLO23YVJ4VO",
+			"ApproximateFirstReceiveTimestamp": sent,
+		},
+		MessageAttributes: map[string]events.SQSMessageAttribute{},
+		EventSourceARN:    DefaultSQSQueueARN,
+		EventSource:       "aws:sqs",
+		AWSRegion:         Region,
+	}}
+
+	return b.WithBody(fmt.Sprintf(`{"id":%d}`, n))
+}
+
+// WithMessageID sets the ID of the message.
+func (b *SQSMessageBuilder) WithMessageID(id string) *SQSMessageBuilder {
+	b.message.MessageId = id
+	return b
+}
+
+// WithBody sets the body of the message and its checksum.
+func (b *SQSMessageBuilder) WithBody(body string) *SQSMessageBuilder {
+	sum := md5.Sum([]byte(body))
+	b.message.Body = body
+	b.message.Md5OfBody = hex.EncodeToString(sum[:])
+	return b
+}
+
+// WithJSONBody sets the body of the message to the JSON encoding of v.
+func (b *SQSMessageBuilder) WithJSONBody(v interface{}) *SQSMessageBuilder {
+	return b.WithBody(string(mustMarshal(v)))
+}
+
+// WithAttribute sets a system attribute of the message, such as
+// ApproximateReceiveCount.
+func (b *SQSMessageBuilder) WithAttribute(name, value string) *SQSMessageBuilder {
+	b.message.Attributes[name] = value
+	return b
+}
+
+// WithReceiveCount sets the number of times the message has been received.
+func (b *SQSMessageBuilder) WithReceiveCount(count int) *SQSMessageBuilder {
+	return b.WithAttribute("ApproximateReceiveCount", fmt.Sprintf("%d", count))
+}
+
+// WithMessageAttribute sets a string message attribute of the message.
+func (b *SQSMessageBuilder) WithMessageAttribute(name, value string) *SQSMessageBuilder {
+	b.message.MessageAttributes[name] = events.SQSMessageAttribute{
+		StringValue:      &value,
+		StringListValues: []string{},
+		BinaryListValues: [][]byte{},
+		DataType:         "String",
+	}
+	return b
+}
+
+// WithMessageGroupID makes the message a message of a FIFO queue in the given
+// message group. Unless set with WithAttribute, the deduplication ID of the
+// message is derived from its body, as with content-based deduplication.
+func (b *SQSMessageBuilder) WithMessageGroupID(groupID string) *SQSMessageBuilder {
+	b.fifo = true
+	b.message.Attributes["MessageGroupId"] = groupID
+	b.message.Attributes["SequenceNumber"] = recordDigits("18849496460467696", 20, b.n)
+
+	if !strings.HasSuffix(b.message.EventSourceARN, ".fifo") {
+		b.message.EventSourceARN += ".fifo"
+	}
+
+	return b
+}
+
+// WithQueueARN sets the ARN of the queue that delivered the message.
+func (b *SQSMessageBuilder) WithQueueARN(arn string) *SQSMessageBuilder {
+	b.message.EventSourceARN = arn
+	return b
+}
+
+// Build returns the message.
+func (b *SQSMessageBuilder) Build() events.SQSMessage {
+	message := b.message
+	message.Attributes = copyMap(b.message.Attributes)
+	message.MessageAttributes = copyMap(b.message.MessageAttributes)
+
+	if _, ok := message.Attributes["MessageDeduplicationId"]; b.fifo && !ok {
+		message.Attributes["MessageDeduplicationId"] = message.Md5OfBody
+	}
+
+	return message
+}
+
+// JSON returns the JSON encoding of the message.
+func (b *SQSMessageBuilder) JSON() []byte {
+	return mustMarshal(b.Build())
+}
+
+// renumber derives the identifiers of the message from the given number.
+func (b *SQSMessageBuilder) renumber(n int) {
+	old, new := newSQSMessage(b.n), newSQSMessage(n)
+	if b.fifo {
+		old.WithMessageGroupID("")
+		new.WithMessageGroupID("")
+	}
+
+	renumber(&b.message.MessageId, old.message.MessageId, new.message.MessageId)
+	renumber(&b.message.ReceiptHandle, old.message.ReceiptHandle, new.message.ReceiptHandle)
+
+	for _, name := range []string{"SentTimestamp", "ApproximateFirstReceiveTimestamp", "SequenceNumber"} {
+		if value, ok := b.message.Attributes[name]; ok && value == old.message.Attributes[name] {
+			b.message.Attributes[name] = new.message.Attributes[name]
+		}
+	}
+
+	if b.message.Body == old.message.Body {
+		b.WithBody(new.message.Body)
+	}
+
+	b.n = n
+}
+
+// NewSQSEvent creates an event containing the given messages.
+func NewSQSEvent(messages ...*SQSMessageBuilder) *SQSEventBuilder {
+	return (&SQSEventBuilder{}).Add(messages...)
+}
+
+// NewSQSBatch creates an event containing n messages with distinct IDs.
+func NewSQSBatch(n int) *SQSEventBuilder {
+	b := &SQSEventBuilder{}
+	for i := 1; i <= n; i++ {
+		b.messages = append(b.messages, newSQSMessage(i))
+	}
+
+	return b
+}
+
+// Add appends the given messages to the event. Each message is renumbered by
+// its position in the event.
+func (b *SQSEventBuilder) Add(messages ...*SQSMessageBuilder) *SQSEventBuilder {
+	for _, message := range messages {
+		message.renumber(len(b.messages) + 1)
+		b.messages = append(b.messages, message)
+	}
+
+	return b
+}
+
+// ForEach calls f with the index and builder of each message of the event.
+func (b *SQSEventBuilder) ForEach(f func(i int, message *SQSMessageBuilder)) *SQSEventBuilder {
+	for i, message := range b.messages {
+		f(i, message)
+	}
+
+	return b
+}
+
+// Build returns the event.
+func (b *SQSEventBuilder) Build() events.SQSEvent {
+	records := make([]events.SQSMessage, 0, len(b.messages))
+	for _, message := range b.messages {
+		records = append(records, message.Build())
+	}
+
+	return events.SQSEvent{Records: records}
+}
+
+// JSON returns the JSON encoding of the event.
+func (b *SQSEventBuilder) JSON() []byte {
+	return mustMarshal(b.Build())
+}
@@ -0,0 +1,246 @@
+// Package lambdabasetest drives a lambdabase server in tests. A harness
+// initializes and runs the server over RPC as the Lambda execution
+// environment would, and invokes it with a populated Lambda context through
+// the rpcclient package.
+package lambdabasetest
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/go-nacelle/config/v3"
+	"github.com/go-nacelle/lambdabase"
+	"github.com/go-nacelle/lambdabase/rpcclient"
+	"github.com/go-nacelle/nacelle/v2"
+)
+
+type (
+	// Harness runs a server and invokes it over RPC.
+	Harness struct {
+		*Client
+		server  *lambdabase.Server
+		errs    chan error
+		stopped bool
+	}
+
+	// Client invokes a server over RPC.
+	Client = rpcclient.Client
+
+	// Response is the result of a single invocation. Error is non-nil if
+	// the handler returned an error or panicked.
+	Response = rpcclient.Response
+
+	options struct {
+		env      map[string]string
+		services map[interface{}]interface{}
+		logger   nacelle.Logger
+		client   []rpcclient.ConfigFunc
+	}
+
+	// ConfigFunc is a function used to configure a harness.
+	ConfigFunc func(*options)
+
+	// InvokeConfigFunc is a function used to configure a single invocation.
+	InvokeConfigFunc = rpcclient.InvokeConfigFunc
+)
+
+// The invocation options of the rpcclient package, re-exported so that tests
+// do not need to import both packages.
+var (
+	WithRequestID          = rpcclient.WithRequestID
+	WithDeadline           = rpcclient.WithDeadline
+	WithInvokedFunctionARN = rpcclient.WithInvokedFunctionARN
+	WithTraceID            = rpcclient.WithTraceID
+	WithClientContext      = rpcclient.WithClientContext
+)
+
+// WithEnv sets environment variables visible to the config loaded by the
+// server and its handler. The harness always sets _LAMBDA_SERVER_PORT.
+func WithEnv(env map[string]string) ConfigFunc {
+	return func(o *options) {
+		for key, value := range env {
+			o.env[key] = value
+		}
+	}
+}
+
+// WithService registers a service that is injected into the handler.
+func WithService(key, value interface{}) ConfigFunc {
+	return func(o *options) { o.services[key] = value }
+}
+
+// WithLogger sets the logger of the server and the handler. The default
+// logger discards all messages.
+func WithLogger(logger nacelle.Logger) ConfigFunc {
+	return func(o *options) { o.logger = logger }
+}
+
+// WithFunctionARN sets the default ARN of the invoked function.
+func WithFunctionARN(arn string) ConfigFunc {
+	return func(o *options) { o.client = append(o.client, rpcclient.WithFunctionARN(arn)) }
+}
+
+// WithTimeout sets the default time between the start of an invocation and
+// its deadline. The default timeout is one minute.
+func WithTimeout(timeout time.Duration) ConfigFunc {
+	return func(o *options) { o.client = append(o.client, rpcclient.WithTimeout(timeout)) }
+}
+
+// Start initializes and runs the given server. The test fails if the server
+// cannot be started, and the server is stopped when the test completes.
+func Start(t testing.TB, server *lambdabase.Server, configs ...ConfigFunc) *Harness {
+	t.Helper()
+
+	harness, err := New(server, configs...)
+	if err != nil {
+		t.Fatalf("failed to start lambda server (%s)", err.Error())
+	}
+
+	t.Cleanup(func() {
+		if err := harness.Stop(); err != nil {
+			t.Errorf("failed to stop lambda server (%s)", err.Error())
+		}
+	})
+
+	return harness
+}
+
+// New initializes and runs the given server. Errors returned from the Init
+// method of the server, including errors from the handler, are returned
+// unchanged. The caller must stop the returned harness.
+func New(server *lambdabase.Server, configs ...ConfigFunc) (*Harness, error) {
+	options := getOptions(configs)
+
+	services := nacelle.NewServiceContainer()
+	health := nacelle.NewHealth()
+	cfg := nacelle.NewConfig(nacelle.NewTestEnvSourcer(options.env))
+
+	for key, value := range map[interface{}]interface{}{
+		"logger":   options.logger,
+		"services": services,
+		"health":   health,
+		"config":   cfg,
+	} {
+		if err := services.Set(key, value); err != nil {
+			return nil, err
+		}
+	}
+
+	for key, value := range options.services {
+		if err := services.Set(key, value); err != nil {
+			return nil, err
+		}
+	}
+
+	server.Config = cfg
+	server.Logger = options.logger
+	server.Services = services
+	server.Health = health
+
+	ctx := config.WithConfig(context.Background(), cfg)
+	if err := server.Init(ctx); err != nil {
+		return nil, err
+	}
+
+	addr := server.Addr()
+	if addr == nil {
+		return nil, fmt.Errorf("lambda server is not listening for RPC commands")
+	}
+
+	errs := make(chan error, 1)
+	go func() { errs <- server.Run(ctx) }()
+
+	client, err := rpcclient.Dial(addr.String(), options.client...)
+	if err != nil {
+		_ = server.Stop(ctx)
+		return nil, err
+	}
+
+	return &Harness{
+		Client: client,
+		server: server,
+		errs:   errs,
+	}, nil
+}
+
+// Server returns the server run by the harness.
+func (h *Harness) Server() *lambdabase.Server {
+	return h.server
+}
+
+// Healthy returns true if every component registered with the health service
+// of the server, including the server itself, is healthy.
+func (h *Harness) Healthy() bool {
+	return h.server.Health.Healthy()
+}
+
+// WaitHealthy blocks until the server reports healthy or the given timeout
+// elapses, and returns false in the latter case.
+func (h *Harness) WaitHealthy(timeout time.Duration) bool {
+	ch, unsubscribe := h.server.Health.Subscribe()
+	defer unsubscribe()
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	for !h.Healthy() {
+		select {
+		case <-ch:
+		case <-timer.C:
+			return h.Healthy()
+		}
+	}
+
+	return true
+}
+
+// Stop stops the server and returns the error returned from its Run method.
+// Calling Stop more than once has no effect.
+func (h *Harness) Stop() error {
+	if h.stopped {
+		return nil
+	}
+	h.stopped = true
+
+	_ = h.Client.Close()
+
+	if err := h.server.Stop(context.Background()); err != nil {
+		return err
+	}
+
+	return <-h.errs
+}
+
+// Invoke calls the harness with the given event and decodes the response
+// payload into a value of type T. The test fails if the invocation fails.
+func Invoke[T any](t testing.TB, h *Harness, event interface{}, configs ...InvokeConfigFunc) T {
+	t.Helper()
+
+	var value T
+	response, err := h.InvokeEvent(event, configs...)
+	if err != nil {
+		t.Fatalf("failed to invoke lambda server (%s)", err.Error())
+	}
+
+	if err := response.Decode(&value); err != nil {
+		t.Fatalf("failed to invoke lambda server (%s)", err.Error())
+	}
+
+	return value
+}
+
+func getOptions(configs []ConfigFunc) *options {
+	options := &options{
+		env:      map[string]string{},
+		services: map[interface{}]interface{}{},
+		logger:   nacelle.NewNilLogger(),
+	}
+	for _, f := range configs {
+		f(options)
+	}
+
+	options.env["_lambda_server_port"] = "0"
+	return options
+}
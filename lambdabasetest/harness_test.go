@@ -0,0 +1,222 @@
+package lambdabasetest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambdacontext"
+	"github.com/go-nacelle/lambdabase"
+	"github.com/go-nacelle/nacelle/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHarnessInvoke(t *testing.T) {
+	deadline := time.Now().Add(time.Hour).Truncate(time.Millisecond)
+	harness := Start(t, lambdabase.NewServer(&contextHandler{}), WithFunctionARN("arn:test"))
+
+	response, err := harness.Invoke([]byte(`{}`), WithRequestID("bonk"), WithDeadline(deadline))
+	require.Nil(t, err)
+	require.Nil(t, response.Error)
+
+	value := map[string]string{}
+	require.Nil(t, response.Decode(&value))
+	require.Equal(t, map[string]string{
+		"requestId":   "bonk",
+		"functionArn": "arn:test",
+		"deadline":    deadline.Format(time.RFC3339Nano),
+	}, value)
+
+	value = Invoke[map[string]string](t, harness, map[string]string{}, WithInvokedFunctionARN("arn:other"))
+	require.Equal(t, "arn:other", value["functionArn"])
+	require.NotEmpty(t, value["requestId"])
+}
+
+func TestHarnessInvokeError(t *testing.T) {
+	handler := lambdabase.LambdaHandlerFunc(func(ctx context.Context, payload []byte) ([]byte, error) {
+		return nil, fmt.Errorf("oops")
+	})
+
+	harness := Start(t, lambdabase.NewServer(&wrappedHandler{LambdaHandlerFunc: handler}))
+
+	response, err := harness.Invoke([]byte(`{}`))
+	require.Nil(t, err)
+	require.NotNil(t, response.Error)
+	require.Equal(t, "oops", response.Error.Message)
+	require.EqualError(t, response.Decode(&struct{}{}), "lambda function returned errorString (oops)")
+}
+
+func TestHarnessInvokeEvent(t *testing.T) {
+	handler := &sqsHandler{}
+	harness := Start(t, lambdabase.NewSQSRecordServer(handler, lambdabase.WithReportBatchItemFailures(true)), WithService("prefix", "test"))
+
+	event := events.SQSEvent{
+		Records: []events.SQSMessage{
+			{MessageId: "m1", Body: "foo"},
+			{MessageId: "m2", Body: "fail"},
+		},
+	}
+
+	response := Invoke[events.SQSEventResponse](t, harness, event)
+	require.Equal(t, []events.SQSBatchItemFailure{{ItemIdentifier: "m2"}}, response.BatchItemFailures)
+	require.Equal(t, []string{"test:foo"}, handler.bodies)
+}
+
+func TestHarnessPanicPolicy(t *testing.T) {
+	panicHandler := lambdabase.LambdaHandlerFunc(func(ctx context.Context, payload []byte) ([]byte, error) {
+		if string(payload) == `"panic"` {
+			panic("oops")
+		}
+
+		return payload, nil
+	})
+
+	for _, recoverPanics := range []bool{false, true} {
+		t.Run(fmt.Sprintf("exit (recovery %v)", recoverPanics), func(t *testing.T) {
+			harness, err := New(lambdabase.NewServer(&wrappedHandler{LambdaHandlerFunc: panicHandler}, lambdabase.WithPanicRecovery(recoverPanics)))
+			require.Nil(t, err)
+
+			response, err := harness.Invoke([]byte(`"panic"`), WithRequestID("bonk"))
+			require.Nil(t, err)
+			require.NotNil(t, response.Error)
+			require.Equal(t, "oops", response.Error.Message)
+			require.True(t, response.Error.ShouldExit)
+
+			require.EqualError(t, harness.Stop(), "lambda handler panicked (oops)")
+		})
+
+		t.Run(fmt.Sprintf("continue (recovery %v)", recoverPanics), func(t *testing.T) {
+			harness := Start(t, lambdabase.NewServer(
+				&wrappedHandler{LambdaHandlerFunc: panicHandler},
+				lambdabase.WithPanicRecovery(recoverPanics),
+				lambdabase.WithPanicPolicy(lambdabase.PanicPolicyContinue),
+			))
+
+			response, err := harness.Invoke([]byte(`"panic"`), WithRequestID("bonk"))
+			require.Nil(t, err)
+			require.NotNil(t, response.Error)
+			require.Equal(t, "oops", response.Error.Message)
+			require.False(t, response.Error.ShouldExit)
+
+			require.Equal(t, "foo", Invoke[string](t, harness, "foo"))
+			require.Nil(t, harness.Stop())
+		})
+	}
+}
+
+func TestHarnessRecordPanicPolicy(t *testing.T) {
+	event := events.SQSEvent{
+		Records: []events.SQSMessage{
+			{MessageId: "m1", Body: "foo"},
+			{MessageId: "m2", Body: "panic"},
+		},
+	}
+
+	t.Run("exit", func(t *testing.T) {
+		handler := &sqsHandler{}
+		harness, err := New(lambdabase.NewSQSRecordServer(handler, lambdabase.WithPanicRecovery(true), lambdabase.WithReportBatchItemFailures(true)), WithService("prefix", "test"))
+		require.Nil(t, err)
+
+		response := Invoke[events.SQSEventResponse](t, harness, event, WithRequestID("bonk"))
+		require.Equal(t, []events.SQSBatchItemFailure{{ItemIdentifier: "m2"}}, response.BatchItemFailures)
+
+		require.EqualError(t, harness.Stop(), "record handler panicked during request bonk")
+	})
+
+	t.Run("continue", func(t *testing.T) {
+		handler := &sqsHandler{}
+		harness := Start(t, lambdabase.NewSQSRecordServer(
+			handler,
+			lambdabase.WithPanicRecovery(true),
+			lambdabase.WithReportBatchItemFailures(true),
+			lambdabase.WithPanicPolicy(lambdabase.PanicPolicyContinue),
+		), WithService("prefix", "test"))
+
+		response := Invoke[events.SQSEventResponse](t, harness, event, WithRequestID("bonk"))
+		require.Equal(t, []events.SQSBatchItemFailure{{ItemIdentifier: "m2"}}, response.BatchItemFailures)
+
+		// A record panic is not attributed to the invocations that follow
+		event := events.SQSEvent{Records: []events.SQSMessage{{MessageId: "m3", Body: "bar"}}}
+		response = Invoke[events.SQSEventResponse](t, harness, event, WithRequestID("quux"))
+		require.Empty(t, response.BatchItemFailures)
+		require.Equal(t, []string{"test:foo", "test:bar"}, handler.bodies)
+		require.Nil(t, harness.Stop())
+	})
+}
+
+func TestHarnessHealth(t *testing.T) {
+	harness := Start(t, lambdabase.NewServer(&contextHandler{}))
+	require.True(t, harness.WaitHealthy(time.Second))
+	require.True(t, harness.Healthy())
+
+	require.Nil(t, harness.Stop())
+	require.Nil(t, harness.Stop())
+}
+
+func TestHarnessInitError(t *testing.T) {
+	_, err := New(lambdabase.NewServer(&contextHandler{initErr: fmt.Errorf("oops")}))
+	require.EqualError(t, err, "oops")
+}
+
+func TestHarnessEnv(t *testing.T) {
+	_, err := New(lambdabase.NewServer(&contextHandler{}), WithEnv(map[string]string{
+		"lambda_panic_policy": "shrug",
+	}))
+	require.NotNil(t, err)
+	require.Contains(t, err.Error(), "lambda_panic_policy")
+}
+
+//
+// Helpers
+
+type contextHandler struct {
+	initErr error
+}
+
+func (h *contextHandler) Init(ctx context.Context) error {
+	return h.initErr
+}
+
+func (h *contextHandler) Invoke(ctx context.Context, payload []byte) ([]byte, error) {
+	lc, _ := lambdacontext.FromContext(ctx)
+	deadline, _ := ctx.Deadline()
+
+	return json.Marshal(map[string]string{
+		"requestId":   lc.AwsRequestID,
+		"functionArn": lc.InvokedFunctionArn,
+		"deadline":    deadline.Format(time.RFC3339Nano),
+	})
+}
+
+type wrappedHandler struct {
+	lambdabase.LambdaHandlerFunc
+}
+
+func (h *wrappedHandler) Init(ctx context.Context) error {
+	return nil
+}
+
+type sqsHandler struct {
+	Prefix string `service:"prefix"`
+	bodies []string
+}
+
+func (h *sqsHandler) Init(ctx context.Context) error {
+	return nil
+}
+
+func (h *sqsHandler) Handle(ctx context.Context, message events.SQSMessage, logger nacelle.Logger) error {
+	if message.Body == "fail" {
+		return fmt.Errorf("oops")
+	}
+
+	if message.Body == "panic" {
+		panic("oops")
+	}
+
+	h.bodies = append(h.bodies, h.Prefix+":"+message.Body)
+	return nil
+}
@@ -0,0 +1,63 @@
+package lambdabase
+
+import "github.com/go-nacelle/nacelle/v2"
+
+// fieldNameLogger renames the fields attached to log messages so that the
+// field names used by this library can match an existing logging schema.
+type fieldNameLogger struct {
+	nacelle.Logger
+	names map[string]string
+}
+
+func newFieldNameLogger(logger nacelle.Logger, names map[string]string) nacelle.Logger {
+	if len(names) == 0 {
+		return logger
+	}
+
+	return &fieldNameLogger{Logger: logger, names: names}
+}
+
+func (l *fieldNameLogger) rename(fields nacelle.LogFields) nacelle.LogFields {
+	renamed := make(nacelle.LogFields, len(fields))
+	for name, value := range fields {
+		if newName, ok := l.names[name]; ok {
+			name = newName
+		}
+
+		renamed[name] = value
+	}
+
+	return renamed
+}
+
+func (l *fieldNameLogger) WithIndirectCaller(frames int) nacelle.Logger {
+	return &fieldNameLogger{Logger: l.Logger.WithIndirectCaller(frames), names: l.names}
+}
+
+func (l *fieldNameLogger) WithFields(fields nacelle.LogFields) nacelle.Logger {
+	return &fieldNameLogger{Logger: l.Logger.WithFields(l.rename(fields)), names: l.names}
+}
+
+func (l *fieldNameLogger) LogWithFields(level nacelle.LogLevel, fields nacelle.LogFields, format string, args ...interface{}) {
+	l.Logger.LogWithFields(level, l.rename(fields), format, args...)
+}
+
+func (l *fieldNameLogger) DebugWithFields(fields nacelle.LogFields, format string, args ...interface{}) {
+	l.Logger.DebugWithFields(l.rename(fields), format, args...)
+}
+
+func (l *fieldNameLogger) InfoWithFields(fields nacelle.LogFields, format string, args ...interface{}) {
+	l.Logger.InfoWithFields(l.rename(fields), format, args...)
+}
+
+func (l *fieldNameLogger) WarningWithFields(fields nacelle.LogFields, format string, args ...interface{}) {
+	l.Logger.WarningWithFields(l.rename(fields), format, args...)
+}
+
+func (l *fieldNameLogger) ErrorWithFields(fields nacelle.LogFields, format string, args ...interface{}) {
+	l.Logger.ErrorWithFields(l.rename(fields), format, args...)
+}
+
+func (l *fieldNameLogger) FatalWithFields(fields nacelle.LogFields, format string, args ...interface{}) {
+	l.Logger.FatalWithFields(l.rename(fields), format, args...)
+}
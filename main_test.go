@@ -1,7 +1,12 @@
 package lambdabase
 
+//go:generate go-mockgen -f github.com/go-nacelle/lambdabase -i cloudWatchLogsHandlerInitializer -o cloudwatch_logs_mock_test.go
 //go:generate go-mockgen -f github.com/go-nacelle/lambdabase -i dynamoDBEventHandlerInitializer -i dynamoDBRecordHandlerInitializer -o dynamodb_mock_test.go
+//go:generate go-mockgen -f github.com/go-nacelle/lambdabase -i kafkaEventHandlerInitializer -i kafkaRecordHandlerInitializer -o kafka_mock_test.go
 //go:generate go-mockgen -f github.com/go-nacelle/lambdabase -i kinesisEventHandlerInitializer -i kinesisRecordHandlerInitializer -o kinesis_mock_test.go
+//go:generate go-mockgen -f github.com/go-nacelle/lambdabase -i s3EventHandlerInitializer -i s3RecordHandlerInitializer -o s3_mock_test.go
+//go:generate go-mockgen -f github.com/go-nacelle/lambdabase -i scheduleHandlerInitializer -o schedule_mock_test.go
+//go:generate go-mockgen -f github.com/go-nacelle/lambdabase -i snsEventHandlerInitializer -i snsRecordHandlerInitializer -o sns_mock_test.go
 //go:generate go-mockgen -f github.com/go-nacelle/lambdabase -i sqsEventHandlerInitializer -i sqsMessageHandlerInitializer -o sqs_mock_test.go
 
 import (
@@ -0,0 +1,69 @@
+package lambdabase
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/go-nacelle/nacelle/v2"
+)
+
+type (
+	// InvokeMiddleware wraps the invocation of a Lambda handler. The payload
+	// and response are the raw bytes received from and sent to Lambda.
+	InvokeMiddleware func(next lambda.Handler) lambda.Handler
+
+	// RecordHandlerFunc handles a single record of an event. The record is
+	// the value passed to the per-record handler of a server, such as an
+	// events.SQSMessage for an SQS record server.
+	RecordHandlerFunc func(ctx context.Context, record interface{}, logger nacelle.Logger) error
+
+	// RecordMiddleware wraps the invocation of a per-record handler. A
+	// middleware may replace the record passed to the next handler with
+	// another value of the same type.
+	RecordMiddleware func(next RecordHandlerFunc) RecordHandlerFunc
+)
+
+func applyInvokeMiddleware(middleware []InvokeMiddleware, handler lambda.Handler) lambda.Handler {
+	for i := len(middleware) - 1; i >= 0; i-- {
+		handler = middleware[i](handler)
+	}
+
+	return handler
+}
+
+// invokeRecordHandler calls handle with the given record through the given
+// record middleware. The first middleware is the outermost. If panic recovery
+// is enabled for the invocation, a panic in the middleware or the handler is
+// returned as an error for the record. If the invocation deadline is
+// approaching, the record is not started and errDeadlineApproaching is
+// returned so that the record is retried.
+func invokeRecordHandler[T any](ctx context.Context, middleware []RecordMiddleware, record T, logger nacelle.Logger, handle func(ctx context.Context, record T, logger nacelle.Logger) error) (err error) {
+	if DeadlineApproaching(ctx) {
+		logger.Warning("Skipping record as the invocation deadline is approaching")
+		return errDeadlineApproaching
+	}
+
+	if state := getPanicState(ctx); state != nil {
+		defer recoverRecordPanic(state, logger, &err)
+	}
+
+	if len(middleware) == 0 {
+		return handle(ctx, record, logger)
+	}
+
+	next := RecordHandlerFunc(func(ctx context.Context, record interface{}, logger nacelle.Logger) error {
+		value, ok := record.(T)
+		if !ok {
+			return fmt.Errorf("record middleware passed a record of type %T (expected %T)", record, value)
+		}
+
+		return handle(ctx, value, logger)
+	})
+
+	for i := len(middleware) - 1; i >= 0; i-- {
+		next = middleware[i](next)
+	}
+
+	return next(ctx, record, logger)
+}
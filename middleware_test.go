@@ -0,0 +1,116 @@
+package lambdabase
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/rpc"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/aws/aws-lambda-go/lambda/messages"
+	mockassert "github.com/derision-test/go-mockgen/testutil/assert"
+	"github.com/go-nacelle/config/v3"
+	"github.com/go-nacelle/nacelle/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServerInvokeMiddleware(t *testing.T) {
+	ctx := context.Background()
+	ctx = config.WithConfig(ctx, testConfig)
+
+	calls := []string{}
+	middleware := func(name string) InvokeMiddleware {
+		return func(next lambda.Handler) lambda.Handler {
+			return LambdaHandlerFunc(func(ctx context.Context, payload []byte) ([]byte, error) {
+				calls = append(calls, name)
+				return next.Invoke(ctx, payload)
+			})
+		}
+	}
+
+	rewrite := func(next lambda.Handler) lambda.Handler {
+		return LambdaHandlerFunc(func(ctx context.Context, payload []byte) ([]byte, error) {
+			return next.Invoke(ctx, []byte(`["rewritten"]`))
+		})
+	}
+
+	server := makeLambdaServer(testHandler, WithInvokeMiddleware(middleware("a"), middleware("b")), WithInvokeMiddleware(rewrite))
+	err := server.Init(ctx)
+	require.Nil(t, err)
+
+	go server.Run(ctx)
+	defer server.Stop(ctx)
+
+	conn, err := net.Dial("tcp", fmt.Sprintf("localhost:%d", getDynamicPort(server.listener)))
+	require.Nil(t, err)
+
+	client := rpc.NewClient(conn)
+	defer client.Close()
+
+	request := &messages.InvokeRequest{
+		Payload:   []byte(`["foo"]`),
+		RequestId: "bonk",
+	}
+
+	response := &messages.InvokeResponse{}
+	err = client.Call("Function.Invoke", request, &response)
+	require.Nil(t, err)
+	require.Equal(t, `["rewritten:bonk"]`, string(response.Payload))
+	require.Equal(t, []string{"a", "b"}, calls)
+}
+
+func TestRecordMiddleware(t *testing.T) {
+	calls := []string{}
+	middleware := func(name string) RecordMiddleware {
+		return func(next RecordHandlerFunc) RecordHandlerFunc {
+			return func(ctx context.Context, record interface{}, logger nacelle.Logger) error {
+				calls = append(calls, fmt.Sprintf("%s:%s", name, record.(events.SQSMessage).MessageId))
+				return next(ctx, record, logger)
+			}
+		}
+	}
+
+	handler := NewMockSqsMessageHandlerInitializer()
+	outer := &sqsMessageHandler{
+		handler:          handler,
+		recordMiddleware: []RecordMiddleware{middleware("a"), middleware("b")},
+	}
+
+	err := outer.Handle(context.Background(), testSQSMessages[:2], nacelle.NewNilLogger())
+	require.Nil(t, err)
+	mockassert.CalledN(t, handler.HandleFunc, 2)
+	require.Equal(t, []string{"a:m1", "b:m1", "a:m2", "b:m2"}, calls)
+}
+
+func TestRecordMiddlewareShortCircuit(t *testing.T) {
+	middleware := func(next RecordHandlerFunc) RecordHandlerFunc {
+		return func(ctx context.Context, record interface{}, logger nacelle.Logger) error {
+			return fmt.Errorf("rejected")
+		}
+	}
+
+	handler := NewMockKinesisRecordHandlerInitializer()
+	outer := &kinesisRecordHandler{
+		handler:          handler,
+		recordMiddleware: []RecordMiddleware{middleware},
+	}
+
+	err := outer.Handle(context.Background(), testKinesisRecords, nacelle.NewNilLogger())
+	require.EqualError(t, err, "failed to process Kinesis record ev1 (rejected)")
+	mockassert.NotCalled(t, handler.HandleFunc)
+}
+
+func TestRecordMiddlewareTypeMismatch(t *testing.T) {
+	middleware := func(next RecordHandlerFunc) RecordHandlerFunc {
+		return func(ctx context.Context, record interface{}, logger nacelle.Logger) error {
+			return next(ctx, "not a message", logger)
+		}
+	}
+
+	err := invokeRecordHandler(context.Background(), []RecordMiddleware{middleware}, events.SQSMessage{}, nacelle.NewNilLogger(), func(ctx context.Context, message events.SQSMessage, logger nacelle.Logger) error {
+		return nil
+	})
+	require.EqualError(t, err, "record middleware passed a record of type string (expected events.SQSMessage)")
+}
@@ -0,0 +1,189 @@
+package lambdabase
+
+import "time"
+
+type (
+	options struct {
+		listenerHost              string
+		healthTokenName           string
+		panicPolicy               PanicPolicy
+		recoverPanics             bool
+		deadlineMargin            time.Duration
+		drainTimeout              time.Duration
+		loggerFieldNames          map[string]string
+		reportBatchItemFailures   bool
+		recordConcurrency         int
+		codec                     Codec
+		unwrapEnvelopes           bool
+		deaggregateKinesisRecords bool
+		dynamoDBPartitionKey      string
+		invokeMiddleware          []InvokeMiddleware
+		recordMiddleware          []RecordMiddleware
+	}
+
+	// ConfigFunc is a function used to configure an instance of a
+	// Lambda server.
+	ConfigFunc func(*options)
+
+	// PanicPolicy determines how a server behaves after its handler panics.
+	PanicPolicy string
+)
+
+const (
+	// PanicPolicyExit reports the panic to Lambda and stops the server so
+	// that Lambda replaces the execution environment. This is the default.
+	PanicPolicyExit PanicPolicy = "exit"
+
+	// PanicPolicyContinue reports the panic to Lambda as a failed invocation
+	// and continues to serve subsequent invocations.
+	PanicPolicyContinue PanicPolicy = "continue"
+)
+
+// WithListenerHost sets the host on which a server listens for RPC commands
+// when _LAMBDA_SERVER_PORT is set. The default listens on all interfaces.
+// This value can be overridden by the LAMBDA_SERVER_HOST environment
+// variable.
+func WithListenerHost(host string) ConfigFunc {
+	return func(o *options) { o.listenerHost = host }
+}
+
+// WithHealthTokenName sets the name of the health token a server registers
+// while it initializes. The default name is lambda-init. This value can be
+// overridden by the LAMBDA_HEALTH_TOKEN_NAME environment variable.
+func WithHealthTokenName(name string) ConfigFunc {
+	return func(o *options) { o.healthTokenName = name }
+}
+
+// WithPanicPolicy sets how a server behaves after its handler panics. The
+// default policy is PanicPolicyExit. This value can be overridden by the
+// LAMBDA_PANIC_POLICY environment variable.
+func WithPanicPolicy(policy PanicPolicy) ConfigFunc {
+	return func(o *options) { o.panicPolicy = policy }
+}
+
+// WithPanicRecovery sets whether or not a server recovers panics raised by
+// its handler. A recovered panic is logged with its stack trace and the
+// request ID, and is returned to Lambda as an error response. A panic in a
+// per-record handler is logged with the fields of the record and fails only
+// that record, which is reported as a batch item failure when partial batch
+// responses are enabled. The panic policy decides whether the server keeps
+// serving after either kind of panic. This value can be overridden by the
+// LAMBDA_PANIC_RECOVERY environment variable.
+func WithPanicRecovery(enabled bool) ConfigFunc {
+	return func(o *options) { o.recoverPanics = enabled }
+}
+
+// WithDeadlineMargin sets the time before the invocation deadline after which
+// a record server stops starting new records. Records that are not started
+// fail so that Lambda retries them, either as batch item failures or by
+// failing the invocation. Callbacks registered with OnDeadlineApproaching are
+// called once the margin is reached. The default margin is zero, in which
+// case callbacks are not called. This value can be overridden by the
+// LAMBDA_DEADLINE_MARGIN_MS environment variable.
+func WithDeadlineMargin(margin time.Duration) ConfigFunc {
+	return func(o *options) { o.deadlineMargin = margin }
+}
+
+// WithDrainTimeout sets the maximum time the Run method of a stopped server
+// waits for in-flight invocations to complete. Once the timeout elapses, the
+// contexts of the remaining invocations are canceled, their request IDs are
+// logged, and Run returns a *DrainTimeoutError. The default timeout of zero
+// waits indefinitely. This value can be overridden by the
+// LAMBDA_DRAIN_TIMEOUT_MS environment variable.
+func WithDrainTimeout(timeout time.Duration) ConfigFunc {
+	return func(o *options) { o.drainTimeout = timeout }
+}
+
+// WithLoggerFieldNames renames the fields this library attaches to log
+// messages, such as requestId or messageId, to the given names. Fields not in
+// the given map keep their names. This value can be overridden by the
+// LAMBDA_LOGGER_FIELD_NAMES environment variable, which holds a JSON object.
+func WithLoggerFieldNames(names map[string]string) ConfigFunc {
+	return func(o *options) { o.loggerFieldNames = names }
+}
+
+// WithReportBatchItemFailures sets whether or not a record server should
+// report failed records back to Lambda instead of failing the entire batch.
+// SQS record servers continue past a failure and report each failed message.
+// Kinesis and DynamoDB record servers stop at the first failure and report
+// its sequence number as the checkpoint from which Lambda retries. The event
+// source mapping of the function must also enable ReportBatchItemFailures.
+// This value can be overridden by the LAMBDA_REPORT_BATCH_ITEM_FAILURES
+// environment variable.
+func WithReportBatchItemFailures(enabled bool) ConfigFunc {
+	return func(o *options) { o.reportBatchItemFailures = enabled }
+}
+
+// WithRecordConcurrency sets the maximum number of records a record server
+// handles at once. SQS messages are handled in parallel, except for messages
+// of the same FIFO message group. Kinesis and DynamoDB records are handled in
+// parallel only across partition keys so that ordering within a key is kept
+// (see WithDynamoDBPartitionKey). Kafka records are handled in parallel only
+// across topic partitions.
+// The default value of one handles records sequentially. This value can be
+// overridden by the LAMBDA_RECORD_CONCURRENCY environment variable.
+func WithRecordConcurrency(concurrency int) ConfigFunc {
+	return func(o *options) { o.recordConcurrency = concurrency }
+}
+
+// WithCodec sets the codec used by typed record servers to decode record
+// payloads into the handler's value type. The default codec decodes JSON.
+func WithCodec(codec Codec) ConfigFunc {
+	return func(o *options) { o.codec = codec }
+}
+
+// WithEnvelopeUnwrapping sets whether or not a server should also accept
+// notifications that arrive wrapped in the envelope of another event source,
+// such as SNS notifications delivered through an SQS subscription. Wrapped
+// notifications are unwrapped so that the same handler serves either style
+// of subscription.
+func WithEnvelopeUnwrapping(enabled bool) ConfigFunc {
+	return func(o *options) { o.unwrapEnvelopes = enabled }
+}
+
+// WithKinesisDeaggregation sets whether or not a Kinesis record server should
+// expand records aggregated by the Kinesis Producer Library into their user
+// records. Each user record is passed to the handler with its own partition
+// key and data, and records that are not aggregated are passed unchanged.
+func WithKinesisDeaggregation(enabled bool) ConfigFunc {
+	return func(o *options) { o.deaggregateKinesisRecords = enabled }
+}
+
+// WithDynamoDBPartitionKey sets the name of the partition key attribute of
+// the table whose stream a DynamoDB record server consumes. Stream records
+// carry the full primary key of an item without identifying its partition
+// key, so by default the records of a table with a composite primary key are
+// never handled in parallel. The records of a table with a simple primary key
+// are handled in parallel across items without this option.
+func WithDynamoDBPartitionKey(attribute string) ConfigFunc {
+	return func(o *options) { o.dynamoDBPartitionKey = attribute }
+}
+
+// WithInvokeMiddleware appends middleware that wraps each invocation of the
+// server's handler. Middleware is applied in the order given, so the first
+// middleware is the outermost.
+func WithInvokeMiddleware(middleware ...InvokeMiddleware) ConfigFunc {
+	return func(o *options) { o.invokeMiddleware = append(o.invokeMiddleware, middleware...) }
+}
+
+// WithRecordMiddleware appends middleware that wraps each invocation of the
+// per-record handler of a record server. Middleware is applied in the order
+// given, so the first middleware is the outermost. Servers that have no
+// per-record handler ignore record middleware.
+func WithRecordMiddleware(middleware ...RecordMiddleware) ConfigFunc {
+	return func(o *options) { o.recordMiddleware = append(o.recordMiddleware, middleware...) }
+}
+
+func getOptions(configs []ConfigFunc) *options {
+	options := &options{
+		healthTokenName:   defaultHealthTokenName,
+		panicPolicy:       PanicPolicyExit,
+		recordConcurrency: 1,
+		codec:             JSONCodec{},
+	}
+	for _, f := range configs {
+		f(options)
+	}
+
+	return options
+}
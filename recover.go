@@ -0,0 +1,118 @@
+package lambdabase
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"runtime/debug"
+	"sync/atomic"
+
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/aws/aws-lambda-go/lambda/messages"
+	"github.com/go-nacelle/nacelle/v2"
+)
+
+type (
+	// panicState records whether a record of the current invocation
+	// panicked. Its presence in a context enables record-level recovery.
+	panicState struct {
+		panicked int32
+	}
+
+	panicStateKeyType struct{}
+
+	// panicError is returned in place of a record handler that panicked.
+	panicError struct {
+		value interface{}
+	}
+)
+
+var panicStateKey = panicStateKeyType{}
+
+func (e *panicError) Error() string {
+	return fmt.Sprintf("record handler panicked (%v)", e.value)
+}
+
+func getPanicState(ctx context.Context) *panicState {
+	if state, ok := ctx.Value(panicStateKey).(*panicState); ok {
+		return state
+	}
+
+	return nil
+}
+
+// recoverRecordPanic must be deferred directly by the function calling a
+// record handler. A recovered panic is logged with its stack trace and
+// replaces the error returned by the record handler.
+func recoverRecordPanic(state *panicState, logger nacelle.Logger, err *error) {
+	value := recover()
+	if value == nil {
+		return
+	}
+
+	atomic.StoreInt32(&state.panicked, 1)
+
+	logger.ErrorWithFields(map[string]interface{}{
+		"stack": string(debug.Stack()),
+	}, "Record handler panicked (%v)", value)
+
+	*err = &panicError{value: value}
+}
+
+// recoverInvoke wraps the given handler so that a panic during an invocation
+// is logged with the request ID and converted into a Lambda error response.
+// The response is marked as a panic by ShouldExit, which the server replaces
+// according to its panic policy. Record handlers invoked beneath the returned
+// handler recover their own panics so that a single record does not fail the
+// entire batch. The panic policy is applied to a record panic once the
+// invocation completes.
+func (s *Server) recoverInvoke(handler lambda.Handler) lambda.Handler {
+	return LambdaHandlerFunc(func(ctx context.Context, payload []byte) (response []byte, err error) {
+		state := &panicState{}
+
+		defer func() {
+			value := recover()
+			if value == nil {
+				if atomic.LoadInt32(&state.panicked) != 0 {
+					s.handlePanic("record handler panicked during request %s", GetRequestID(ctx))
+				}
+
+				return
+			}
+
+			// A panic raised by a record worker is reported with the
+			// stack of the worker rather than the stack of the batch
+			stack := debug.Stack()
+			if p, ok := value.(*batchPanic); ok {
+				value, stack = p.value, p.stack
+			}
+
+			logger := s.Logger.WithFields(map[string]interface{}{
+				"requestId": GetRequestID(ctx),
+			})
+
+			logger.ErrorWithFields(map[string]interface{}{
+				"stack": string(stack),
+			}, "Lambda handler panicked (%v)", value)
+
+			response = nil
+			err = messages.InvokeResponse_Error{
+				Message:    fmt.Sprintf("%v", value),
+				Type:       panicTypeName(value),
+				StackTrace: stackFrames(4),
+				ShouldExit: true,
+			}
+		}()
+
+		return handler.Invoke(context.WithValue(ctx, panicStateKey, state), payload)
+	})
+}
+
+func panicTypeName(value interface{}) string {
+	valueType := reflect.TypeOf(value)
+	if valueType.Kind() == reflect.Ptr {
+		return valueType.Elem().Name()
+	}
+
+	return valueType.Name()
+}
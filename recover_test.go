@@ -0,0 +1,179 @@
+package lambdabase
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda/messages"
+	"github.com/go-nacelle/config/v3"
+	"github.com/go-nacelle/nacelle/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServerPanicRecovery(t *testing.T) {
+	runtimeAPI := newTestRuntimeAPI()
+	defer runtimeAPI.Close()
+
+	ctx := context.Background()
+	ctx = config.WithConfig(ctx, runtimeAPI.config())
+
+	panicHandler := LambdaHandlerFunc(func(ctx context.Context, payload []byte) ([]byte, error) {
+		if string(payload) == `"panic"` {
+			panic("oops")
+		}
+
+		return testHandler(ctx, payload)
+	})
+
+	logger := &panicRecordingLogger{Logger: nacelle.NewNilLogger()}
+	server := makeLambdaServer(panicHandler, WithPanicRecovery(true), WithPanicPolicy(PanicPolicyContinue))
+	server.Logger = logger
+	err := server.Init(ctx)
+	require.Nil(t, err)
+
+	errs := make(chan error, 1)
+	go func() { errs <- server.Run(ctx) }()
+
+	runtimeAPI.invocations <- testRuntimeAPIInvocation{requestID: "bonk", payload: `"panic"`}
+	result := <-runtimeAPI.results
+	require.Equal(t, "/2018-06-01/runtime/invocation/bonk/error", result.path)
+	require.Equal(t, "string", result.errorType)
+
+	invokeErr := runtimeAPIError{}
+	require.Nil(t, json.Unmarshal([]byte(result.body), &invokeErr))
+	require.Equal(t, "oops", invokeErr.Message)
+	require.NotEmpty(t, invokeErr.StackTrace)
+
+	runtimeAPI.invocations <- testRuntimeAPIInvocation{requestID: "quux", payload: `["foo"]`}
+	result = <-runtimeAPI.results
+	require.Equal(t, "/2018-06-01/runtime/invocation/quux/response", result.path)
+
+	require.Nil(t, server.Stop(ctx))
+	require.Nil(t, <-errs)
+
+	require.Len(t, logger.errorFields, 1)
+	require.Equal(t, "bonk", logger.fields["requestId"])
+	require.Contains(t, logger.errorFields[0]["stack"], "panic")
+}
+
+func TestServerRecordPanicRecovery(t *testing.T) {
+	runtimeAPI := newTestRuntimeAPI()
+	defer runtimeAPI.Close()
+
+	ctx := context.Background()
+	ctx = config.WithConfig(ctx, runtimeAPI.config())
+
+	handler := NewMockSqsMessageHandlerInitializer()
+	handler.HandleFunc.SetDefaultHook(func(ctx context.Context, message events.SQSMessage, logger nacelle.Logger) error {
+		if message.MessageId == "m2" {
+			panic("oops")
+		}
+
+		return nil
+	})
+
+	server := NewSQSRecordServer(handler, WithPanicRecovery(true), WithReportBatchItemFailures(true))
+	server.Logger = nacelle.NewNilLogger()
+	server.Services = nacelle.NewServiceContainer()
+	server.Health = nacelle.NewHealth()
+	require.Nil(t, server.Services.Set("logger", nacelle.NewNilLogger()))
+	require.Nil(t, server.Services.Set("services", server.Services))
+
+	err := server.Init(ctx)
+	require.Nil(t, err)
+
+	errs := make(chan error, 1)
+	go func() { errs <- server.Run(ctx) }()
+
+	runtimeAPI.invocations <- testRuntimeAPIInvocation{requestID: "bonk", payload: testSQSPayload}
+	result := <-runtimeAPI.results
+	require.Equal(t, "/2018-06-01/runtime/invocation/bonk/response", result.path)
+	require.JSONEq(t, `{"batchItemFailures": [{"itemIdentifier": "m2"}]}`, result.body)
+
+	err = <-errs
+	require.EqualError(t, err, "record handler panicked during request bonk")
+}
+
+func TestServerBatchPanicRecovery(t *testing.T) {
+	logger := &panicRecordingLogger{Logger: nacelle.NewNilLogger()}
+	server := &Server{Logger: logger}
+
+	handler := server.recoverInvoke(LambdaHandlerFunc(func(ctx context.Context, payload []byte) ([]byte, error) {
+		panic(&batchPanic{value: "oops", stack: []byte("worker stack")})
+	}))
+
+	_, err := handler.Invoke(context.Background(), nil)
+	invokeErr, ok := err.(messages.InvokeResponse_Error)
+	require.True(t, ok)
+	require.Equal(t, "oops", invokeErr.Message)
+	require.Equal(t, "string", invokeErr.Type)
+
+	require.Len(t, logger.errorFields, 1)
+	require.Equal(t, "worker stack", logger.errorFields[0]["stack"])
+}
+
+func TestRecordPanicRecovery(t *testing.T) {
+	handler := NewMockSqsMessageHandlerInitializer()
+	handler.HandleFunc.SetDefaultHook(func(ctx context.Context, message events.SQSMessage, logger nacelle.Logger) error {
+		if message.MessageId == "m2" {
+			panic("oops")
+		}
+
+		return nil
+	})
+	outer := &sqsMessageHandler{handler: handler, reportBatchItemFailures: true}
+
+	state := &panicState{}
+	ctx := context.WithValue(context.Background(), panicStateKey, state)
+	logger := &panicRecordingLogger{Logger: nacelle.NewNilLogger()}
+
+	response, err := outer.HandleWithResponse(ctx, testSQSMessages, logger)
+	require.Nil(t, err)
+	require.Equal(t, []events.SQSBatchItemFailure{{ItemIdentifier: "m2"}}, response.BatchItemFailures)
+	require.Equal(t, int32(1), state.panicked)
+
+	require.Len(t, logger.errorFields, 1)
+	require.Contains(t, logger.errorFields[0]["stack"], "panic")
+
+	err = outer.Handle(ctx, testSQSMessages, nacelle.NewNilLogger())
+	require.EqualError(t, err, "failed to process SQS message m2 (record handler panicked (oops))")
+}
+
+func TestRecordPanicRecoveryDisabled(t *testing.T) {
+	handler := NewMockSqsMessageHandlerInitializer()
+	handler.HandleFunc.SetDefaultHook(func(ctx context.Context, message events.SQSMessage, logger nacelle.Logger) error {
+		panic("oops")
+	})
+	outer := &sqsMessageHandler{handler: handler}
+
+	require.Panics(t, func() {
+		_ = outer.Handle(context.Background(), testSQSMessages, nacelle.NewNilLogger())
+	})
+}
+
+//
+// Helpers
+
+type panicRecordingLogger struct {
+	nacelle.Logger
+	fields      nacelle.LogFields
+	errorFields []nacelle.LogFields
+}
+
+func (l *panicRecordingLogger) WithFields(fields nacelle.LogFields) nacelle.Logger {
+	if l.fields == nil {
+		l.fields = nacelle.LogFields{}
+	}
+
+	for name, value := range fields {
+		l.fields[name] = value
+	}
+
+	return l
+}
+
+func (l *panicRecordingLogger) ErrorWithFields(fields nacelle.LogFields, format string, args ...interface{}) {
+	l.errorFields = append(l.errorFields, fields)
+}
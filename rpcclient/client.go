@@ -0,0 +1,172 @@
+// Package rpcclient invokes a lambdabase server listening for RPC commands,
+// as the go1.x Lambda runtime does. Each invocation is sent with a populated
+// Lambda context, including the request ID, deadline, and function ARN.
+package rpcclient
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/rpc"
+	"time"
+
+	"github.com/aws/aws-lambda-go/lambda/messages"
+	"github.com/google/uuid"
+)
+
+type (
+	// Client invokes a server over RPC.
+	Client struct {
+		client  *rpc.Client
+		options *options
+	}
+
+	// Response is the result of a single invocation. Error is non-nil if
+	// the handler returned an error or panicked.
+	Response struct {
+		Payload []byte
+		Error   *messages.InvokeResponse_Error
+	}
+
+	options struct {
+		functionARN string
+		timeout     time.Duration
+	}
+
+	// ConfigFunc is a function used to configure a client.
+	ConfigFunc func(*options)
+
+	invokeOptions struct {
+		requestID     string
+		deadline      time.Time
+		functionARN   string
+		traceID       string
+		clientContext []byte
+	}
+
+	// InvokeConfigFunc is a function used to configure a single invocation.
+	InvokeConfigFunc func(*invokeOptions)
+)
+
+const (
+	defaultFunctionARN = "arn:aws:lambda:us-east-1:123456789012:function:lambdabase"
+	defaultTimeout     = time.Minute
+)
+
+// WithFunctionARN sets the default ARN of the invoked function.
+func WithFunctionARN(arn string) ConfigFunc {
+	return func(o *options) { o.functionARN = arn }
+}
+
+// WithTimeout sets the default time between the start of an invocation and
+// its deadline. The default timeout is one minute.
+func WithTimeout(timeout time.Duration) ConfigFunc {
+	return func(o *options) { o.timeout = timeout }
+}
+
+// WithRequestID sets the request ID of an invocation. The default request ID
+// is a random UUID.
+func WithRequestID(requestID string) InvokeConfigFunc {
+	return func(o *invokeOptions) { o.requestID = requestID }
+}
+
+// WithDeadline sets the deadline of an invocation.
+func WithDeadline(deadline time.Time) InvokeConfigFunc {
+	return func(o *invokeOptions) { o.deadline = deadline }
+}
+
+// WithInvokedFunctionARN sets the ARN of the function for an invocation.
+func WithInvokedFunctionARN(arn string) InvokeConfigFunc {
+	return func(o *invokeOptions) { o.functionARN = arn }
+}
+
+// WithTraceID sets the X-Ray trace ID of an invocation.
+func WithTraceID(traceID string) InvokeConfigFunc {
+	return func(o *invokeOptions) { o.traceID = traceID }
+}
+
+// WithClientContext sets the serialized client context of an invocation.
+func WithClientContext(clientContext []byte) InvokeConfigFunc {
+	return func(o *invokeOptions) { o.clientContext = clientContext }
+}
+
+// Dial connects to a server listening for RPC commands on the given TCP
+// address, such as a server run with _LAMBDA_SERVER_PORT set.
+func Dial(address string, configs ...ConfigFunc) (*Client, error) {
+	client, err := rpc.Dial("tcp", address)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Client{client: client, options: getOptions(configs)}, nil
+}
+
+// Invoke calls the server with the given raw payload. The returned error is
+// non-nil only if the server could not be reached. Handler errors are set on
+// the returned response.
+func (c *Client) Invoke(payload []byte, configs ...InvokeConfigFunc) (*Response, error) {
+	options := &invokeOptions{
+		requestID:   uuid.New().String(),
+		deadline:    time.Now().Add(c.options.timeout),
+		functionARN: c.options.functionARN,
+	}
+	for _, f := range configs {
+		f(options)
+	}
+
+	request := &messages.InvokeRequest{
+		Payload:            payload,
+		RequestId:          options.requestID,
+		XAmznTraceId:       options.traceID,
+		InvokedFunctionArn: options.functionARN,
+		ClientContext:      options.clientContext,
+		Deadline: messages.InvokeRequest_Timestamp{
+			Seconds: options.deadline.Unix(),
+			Nanos:   int64(options.deadline.Nanosecond()),
+		},
+	}
+
+	response := &messages.InvokeResponse{}
+	if err := c.client.Call("Function.Invoke", request, response); err != nil {
+		return nil, err
+	}
+
+	return &Response{Payload: response.Payload, Error: response.Error}, nil
+}
+
+// InvokeEvent calls the server with the JSON encoding of the given event,
+// such as an events.SQSEvent value.
+func (c *Client) InvokeEvent(event interface{}, configs ...InvokeConfigFunc) (*Response, error) {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal event (%w)", err)
+	}
+
+	return c.Invoke(payload, configs...)
+}
+
+// Close closes the connection to the server.
+func (c *Client) Close() error {
+	return c.client.Close()
+}
+
+// Decode unmarshals the JSON payload of the response into v. An error is
+// returned if the invocation failed.
+func (r *Response) Decode(v interface{}) error {
+	if r.Error != nil {
+		return fmt.Errorf("lambda function returned %s (%s)", r.Error.Type, r.Error.Message)
+	}
+
+	return json.Unmarshal(r.Payload, v)
+}
+
+func getOptions(configs []ConfigFunc) *options {
+	options := &options{
+		functionARN: defaultFunctionARN,
+		timeout:     defaultTimeout,
+	}
+	for _, f := range configs {
+		f(options)
+	}
+
+	return options
+}
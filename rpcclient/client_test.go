@@ -0,0 +1,98 @@
+package rpcclient
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/rpc"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/aws/aws-lambda-go/lambdacontext"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientInvoke(t *testing.T) {
+	address := startServer(t, func(ctx context.Context, payload []byte) ([]byte, error) {
+		lc, _ := lambdacontext.FromContext(ctx)
+		deadline, _ := ctx.Deadline()
+
+		return json.Marshal(map[string]string{
+			"payload":     string(payload),
+			"requestId":   lc.AwsRequestID,
+			"functionArn": lc.InvokedFunctionArn,
+			"deadline":    deadline.Format(time.RFC3339Nano),
+		})
+	})
+
+	client, err := Dial(address, WithFunctionARN("arn:test"))
+	require.Nil(t, err)
+	defer client.Close()
+
+	deadline := time.Now().Add(time.Hour).Truncate(time.Millisecond)
+	response, err := client.InvokeEvent(map[string]string{"name": "foo"}, WithRequestID("bonk"), WithDeadline(deadline))
+	require.Nil(t, err)
+
+	value := map[string]string{}
+	require.Nil(t, response.Decode(&value))
+	require.Equal(t, map[string]string{
+		"payload":     `{"name":"foo"}`,
+		"requestId":   "bonk",
+		"functionArn": "arn:test",
+		"deadline":    deadline.Format(time.RFC3339Nano),
+	}, value)
+
+	response, err = client.Invoke([]byte(`{}`), WithInvokedFunctionARN("arn:other"))
+	require.Nil(t, err)
+	require.Nil(t, response.Decode(&value))
+	require.Equal(t, "arn:other", value["functionArn"])
+	require.NotEmpty(t, value["requestId"])
+}
+
+func TestClientInvokeError(t *testing.T) {
+	address := startServer(t, func(ctx context.Context, payload []byte) ([]byte, error) {
+		return nil, fmt.Errorf("oops")
+	})
+
+	client, err := Dial(address)
+	require.Nil(t, err)
+	defer client.Close()
+
+	response, err := client.Invoke([]byte(`{}`))
+	require.Nil(t, err)
+	require.NotNil(t, response.Error)
+	require.Equal(t, "oops", response.Error.Message)
+	require.EqualError(t, response.Decode(&struct{}{}), "lambda function returned errorString (oops)")
+}
+
+func TestDialUnreachable(t *testing.T) {
+	listener, err := net.Listen("tcp", "localhost:0")
+	require.Nil(t, err)
+	address := listener.Addr().String()
+	listener.Close()
+
+	_, err = Dial(address)
+	require.NotNil(t, err)
+}
+
+// startServer serves the given handler over RPC as a lambda.Function and
+// returns the address on which it listens.
+func startServer(t *testing.T, handler func(ctx context.Context, payload []byte) ([]byte, error)) string {
+	listener, err := net.Listen("tcp", "localhost:0")
+	require.Nil(t, err)
+	t.Cleanup(func() { listener.Close() })
+
+	server := rpc.NewServer()
+	require.Nil(t, server.Register(lambda.NewFunction(handlerFunc(handler))))
+	go server.Accept(listener)
+
+	return listener.Addr().String()
+}
+
+type handlerFunc func(ctx context.Context, payload []byte) ([]byte, error)
+
+func (f handlerFunc) Invoke(ctx context.Context, payload []byte) ([]byte, error) {
+	return f(ctx, payload)
+}
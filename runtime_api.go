@@ -0,0 +1,161 @@
+package lambdabase
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/aws/aws-lambda-go/lambda/messages"
+)
+
+type (
+	runtimeAPIClient struct {
+		baseURL string
+		client  *http.Client
+	}
+
+	runtimeAPIError struct {
+		Message    string                                      `json:"errorMessage"`
+		Type       string                                      `json:"errorType"`
+		StackTrace []*messages.InvokeResponse_Error_StackFrame `json:"stackTrace,omitempty"`
+	}
+
+	runtimeAPICognitoIdentity struct {
+		CognitoIdentityID     string `json:"cognitoIdentityId"`
+		CognitoIdentityPoolID string `json:"cognitoIdentityPoolId"`
+	}
+)
+
+const (
+	runtimeAPIVersion = "2018-06-01"
+
+	headerRequestID          = "Lambda-Runtime-Aws-Request-Id"
+	headerDeadlineMS         = "Lambda-Runtime-Deadline-Ms"
+	headerInvokedFunctionARN = "Lambda-Runtime-Invoked-Function-Arn"
+	headerTraceID            = "Lambda-Runtime-Trace-Id"
+	headerClientContext      = "Lambda-Runtime-Client-Context"
+	headerCognitoIdentity    = "Lambda-Runtime-Cognito-Identity"
+	headerFunctionErrorType  = "Lambda-Runtime-Function-Error-Type"
+)
+
+func newRuntimeAPIClient(address string) *runtimeAPIClient {
+	return &runtimeAPIClient{
+		baseURL: fmt.Sprintf("http://%s/%s/runtime", address, runtimeAPIVersion),
+		client:  &http.Client{},
+	}
+}
+
+func (c *runtimeAPIClient) next(ctx context.Context) (*messages.InvokeRequest, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/invocation/next", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch next invocation (%w)", err)
+	}
+	defer resp.Body.Close()
+
+	payload, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read next invocation (%w)", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch next invocation (unexpected status %d)", resp.StatusCode)
+	}
+
+	deadlineMS, err := strconv.ParseInt(resp.Header.Get(headerDeadlineMS), 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse invocation deadline (%w)", err)
+	}
+
+	request := &messages.InvokeRequest{
+		Payload:            payload,
+		RequestId:          resp.Header.Get(headerRequestID),
+		XAmznTraceId:       resp.Header.Get(headerTraceID),
+		InvokedFunctionArn: resp.Header.Get(headerInvokedFunctionARN),
+		Deadline: messages.InvokeRequest_Timestamp{
+			Seconds: deadlineMS / 1000,
+			Nanos:   (deadlineMS % 1000) * 1000000,
+		},
+	}
+
+	if clientContext := resp.Header.Get(headerClientContext); clientContext != "" {
+		request.ClientContext = []byte(clientContext)
+	}
+
+	if cognitoIdentity := resp.Header.Get(headerCognitoIdentity); cognitoIdentity != "" {
+		identity := runtimeAPICognitoIdentity{}
+		if err := json.Unmarshal([]byte(cognitoIdentity), &identity); err != nil {
+			return nil, fmt.Errorf("failed to parse cognito identity (%w)", err)
+		}
+
+		request.CognitoIdentityId = identity.CognitoIdentityID
+		request.CognitoIdentityPoolId = identity.CognitoIdentityPoolID
+	}
+
+	return request, nil
+}
+
+func (c *runtimeAPIClient) respond(ctx context.Context, requestID string, payload []byte) error {
+	return c.post(ctx, fmt.Sprintf("/invocation/%s/response", requestID), payload, "")
+}
+
+func (c *runtimeAPIClient) respondError(ctx context.Context, requestID string, invokeErr *messages.InvokeResponse_Error) error {
+	payload, err := json.Marshal(runtimeAPIError{
+		Message:    invokeErr.Message,
+		Type:       invokeErr.Type,
+		StackTrace: invokeErr.StackTrace,
+	})
+	if err != nil {
+		return err
+	}
+
+	return c.post(ctx, fmt.Sprintf("/invocation/%s/error", requestID), payload, invokeErr.Type)
+}
+
+func (c *runtimeAPIClient) initError(ctx context.Context, initErr error) error {
+	payload, err := json.Marshal(runtimeAPIError{
+		Message: initErr.Error(),
+		Type:    "Runtime.InitError",
+	})
+	if err != nil {
+		return err
+	}
+
+	return c.post(ctx, "/init/error", payload, "Runtime.InitError")
+}
+
+func (c *runtimeAPIClient) post(ctx context.Context, path string, payload []byte, errorType string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+path, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	if errorType != "" {
+		req.Header.Set(headerFunctionErrorType, errorType)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to post to %s (%w)", path, err)
+	}
+	defer resp.Body.Close()
+
+	if _, err := io.Copy(io.Discard, resp.Body); err != nil {
+		return fmt.Errorf("failed to post to %s (%w)", path, err)
+	}
+
+	if resp.StatusCode != http.StatusAccepted {
+		return fmt.Errorf("failed to post to %s (unexpected status %d)", path, resp.StatusCode)
+	}
+
+	return nil
+}
@@ -0,0 +1,197 @@
+package lambdabase
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-nacelle/config/v3"
+	"github.com/go-nacelle/nacelle/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServerRuntimeAPIServeAndStop(t *testing.T) {
+	runtimeAPI := newTestRuntimeAPI()
+	defer runtimeAPI.Close()
+
+	ctx := context.Background()
+	ctx = config.WithConfig(ctx, runtimeAPI.config())
+
+	server := makeLambdaServer(testHandler)
+	err := server.Init(ctx)
+	require.Nil(t, err)
+
+	errs := make(chan error, 1)
+	go func() { errs <- server.Run(ctx) }()
+
+	runtimeAPI.invocations <- testRuntimeAPIInvocation{requestID: "bonk", payload: `["foo", "bar", "baz"]`}
+	result := <-runtimeAPI.results
+	require.Equal(t, "/2018-06-01/runtime/invocation/bonk/response", result.path)
+	require.Equal(t, `["foo:bonk","bar:bonk","baz:bonk"]`, result.body)
+
+	runtimeAPI.invocations <- testRuntimeAPIInvocation{requestID: "quux", payload: `[123, 456, 789]`}
+	result = <-runtimeAPI.results
+	require.Equal(t, "/2018-06-01/runtime/invocation/quux/error", result.path)
+	require.Equal(t, "errorString", result.errorType)
+
+	invokeErr := runtimeAPIError{}
+	require.Nil(t, json.Unmarshal([]byte(result.body), &invokeErr))
+	require.Equal(t, "malformed input", invokeErr.Message)
+
+	require.Nil(t, server.Stop(ctx))
+	require.Nil(t, <-errs)
+}
+
+func TestServerRuntimeAPIInitError(t *testing.T) {
+	runtimeAPI := newTestRuntimeAPI()
+	defer runtimeAPI.Close()
+
+	ctx := context.Background()
+	ctx = config.WithConfig(ctx, runtimeAPI.config())
+
+	server := NewServer(&badInitLambdaHandler{})
+	server.Logger = nacelle.NewNilLogger()
+	server.Services = nacelle.NewServiceContainer()
+	server.Health = nacelle.NewHealth()
+
+	err := server.Init(ctx)
+	require.EqualError(t, err, "oops")
+
+	result := <-runtimeAPI.results
+	require.Equal(t, "/2018-06-01/runtime/init/error", result.path)
+	require.Equal(t, "Runtime.InitError", result.errorType)
+}
+
+func TestServerRuntimeAPIPanicPolicy(t *testing.T) {
+	panicHandler := LambdaHandlerFunc(func(ctx context.Context, payload []byte) ([]byte, error) {
+		if string(payload) == `"panic"` {
+			panic("oops")
+		}
+
+		return testHandler(ctx, payload)
+	})
+
+	t.Run("exit", func(t *testing.T) {
+		runtimeAPI := newTestRuntimeAPI()
+		defer runtimeAPI.Close()
+
+		ctx := context.Background()
+		ctx = config.WithConfig(ctx, runtimeAPI.config())
+
+		server := makeLambdaServer(panicHandler)
+		err := server.Init(ctx)
+		require.Nil(t, err)
+
+		errs := make(chan error, 1)
+		go func() { errs <- server.Run(ctx) }()
+
+		runtimeAPI.invocations <- testRuntimeAPIInvocation{requestID: "bonk", payload: `"panic"`}
+		result := <-runtimeAPI.results
+		require.Equal(t, "/2018-06-01/runtime/invocation/bonk/error", result.path)
+
+		err = <-errs
+		require.NotNil(t, err)
+		require.Contains(t, err.Error(), "lambda handler panicked")
+	})
+
+	t.Run("continue", func(t *testing.T) {
+		runtimeAPI := newTestRuntimeAPI()
+		defer runtimeAPI.Close()
+
+		ctx := context.Background()
+		ctx = config.WithConfig(ctx, runtimeAPI.config())
+
+		server := makeLambdaServer(panicHandler, WithPanicPolicy(PanicPolicyContinue))
+		err := server.Init(ctx)
+		require.Nil(t, err)
+
+		errs := make(chan error, 1)
+		go func() { errs <- server.Run(ctx) }()
+
+		runtimeAPI.invocations <- testRuntimeAPIInvocation{requestID: "bonk", payload: `"panic"`}
+		result := <-runtimeAPI.results
+		require.Equal(t, "/2018-06-01/runtime/invocation/bonk/error", result.path)
+
+		runtimeAPI.invocations <- testRuntimeAPIInvocation{requestID: "quux", payload: `["foo"]`}
+		result = <-runtimeAPI.results
+		require.Equal(t, "/2018-06-01/runtime/invocation/quux/response", result.path)
+		require.Equal(t, `["foo:quux"]`, result.body)
+
+		require.Nil(t, server.Stop(ctx))
+		require.Nil(t, <-errs)
+	})
+}
+
+func TestServerMissingTransport(t *testing.T) {
+	ctx := context.Background()
+	ctx = config.WithConfig(ctx, nacelle.NewConfig(nacelle.NewTestEnvSourcer(nil)))
+
+	err := makeLambdaServer(testHandler).Init(ctx)
+	require.NotNil(t, err)
+	require.Contains(t, err.Error(), "one of _lambda_server_port or aws_lambda_runtime_api must be supplied")
+}
+
+//
+// Runtime API
+
+type testRuntimeAPI struct {
+	*httptest.Server
+	invocations chan testRuntimeAPIInvocation
+	results     chan testRuntimeAPIResult
+}
+
+type testRuntimeAPIInvocation struct {
+	requestID string
+	payload   string
+}
+
+type testRuntimeAPIResult struct {
+	path      string
+	body      string
+	errorType string
+}
+
+func newTestRuntimeAPI() *testRuntimeAPI {
+	runtimeAPI := &testRuntimeAPI{
+		invocations: make(chan testRuntimeAPIInvocation, 1),
+		results:     make(chan testRuntimeAPIResult, 1),
+	}
+
+	runtimeAPI.Server = httptest.NewServer(http.HandlerFunc(runtimeAPI.serveHTTP))
+	return runtimeAPI
+}
+
+func (a *testRuntimeAPI) config() *nacelle.Config {
+	return nacelle.NewConfig(nacelle.NewTestEnvSourcer(map[string]string{
+		"aws_lambda_runtime_api": strings.TrimPrefix(a.URL, "http://"),
+	}))
+}
+
+func (a *testRuntimeAPI) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodGet && r.URL.Path == "/2018-06-01/runtime/invocation/next" {
+		select {
+		case invocation := <-a.invocations:
+			w.Header().Set(headerRequestID, invocation.requestID)
+			w.Header().Set(headerDeadlineMS, fmt.Sprintf("%d", time.Now().Add(time.Minute).UnixNano()/int64(time.Millisecond)))
+			_, _ = io.WriteString(w, invocation.payload)
+		case <-r.Context().Done():
+		}
+
+		return
+	}
+
+	body, _ := io.ReadAll(r.Body)
+	w.WriteHeader(http.StatusAccepted)
+
+	a.results <- testRuntimeAPIResult{
+		path:      r.URL.Path,
+		body:      string(body),
+		errorType: r.Header.Get(headerFunctionErrorType),
+	}
+}
@@ -0,0 +1,128 @@
+package lambdabase
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/go-nacelle/nacelle/v2"
+)
+
+type (
+	S3EventHandler interface {
+		Handle(ctx context.Context, batch []events.S3EventRecord, logger nacelle.Logger) error
+	}
+
+	s3EventHandlerInitializer interface {
+		nacelle.Initializer
+		S3EventHandler
+	}
+
+	s3EventHandler struct {
+		Logger          nacelle.Logger            `service:"logger"`
+		Services        *nacelle.ServiceContainer `service:"services"`
+		handler         S3EventHandler
+		unwrapEnvelopes bool
+	}
+)
+
+func NewS3EventServer(handler S3EventHandler, configs ...ConfigFunc) *Server {
+	options := getOptions(configs)
+
+	return NewServer(&s3EventHandler{
+		handler:         handler,
+		unwrapEnvelopes: options.unwrapEnvelopes,
+	}, configs...)
+}
+
+func (h *s3EventHandler) Init(ctx context.Context) error {
+	return doInit(ctx, h.Services, h.handler)
+}
+
+func (h *s3EventHandler) Invoke(ctx context.Context, payload []byte) ([]byte, error) {
+	records, err := h.unmarshalRecords(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	logger := h.Logger.WithFields(map[string]interface{}{
+		"requestId": GetRequestID(ctx),
+	})
+
+	logger.Debug("Received %d S3 records", len(records))
+
+	if err := h.handler.Handle(ctx, records, logger); err != nil {
+		return nil, fmt.Errorf("failed to process S3 event (%w)", err)
+	}
+
+	logger.Debug("S3 event handled successfully")
+	return nil, nil
+}
+
+func (h *s3EventHandler) unmarshalRecords(payload []byte) ([]events.S3EventRecord, error) {
+	if h.unwrapEnvelopes {
+		eventSource, source, err := probeEnvelope(payload)
+		if err != nil {
+			return nil, fmt.Errorf("failed to unmarshal event (%w)", err)
+		}
+
+		switch {
+		case eventSource == eventSourceSQS:
+			event := &events.SQSEvent{}
+			if err := json.Unmarshal(payload, &event); err != nil {
+				return nil, fmt.Errorf("failed to unmarshal event (%w)", err)
+			}
+
+			records := []events.S3EventRecord{}
+			for _, message := range event.Records {
+				unwrapped, err := unwrapS3Notification([]byte(message.Body))
+				if err != nil {
+					return nil, fmt.Errorf("failed to unwrap S3 event from SQS message %s (%w)", message.MessageId, err)
+				}
+
+				records = append(records, unwrapped...)
+			}
+
+			return records, nil
+
+		case eventSource == eventSourceSNS:
+			event := &events.SNSEvent{}
+			if err := json.Unmarshal(payload, &event); err != nil {
+				return nil, fmt.Errorf("failed to unmarshal event (%w)", err)
+			}
+
+			records := []events.S3EventRecord{}
+			for _, record := range event.Records {
+				unwrapped, err := unwrapS3Notification([]byte(record.SNS.Message))
+				if err != nil {
+					return nil, fmt.Errorf("failed to unwrap S3 event from SNS notification %s (%w)", record.SNS.MessageID, err)
+				}
+
+				records = append(records, unwrapped...)
+			}
+
+			return records, nil
+
+		case source == eventBridgeSourceS3:
+			event := events.CloudWatchEvent{}
+			if err := json.Unmarshal(payload, &event); err != nil {
+				return nil, fmt.Errorf("failed to unmarshal event (%w)", err)
+			}
+
+			record, err := convertEventBridgeS3Record(event)
+			if err != nil {
+				return nil, fmt.Errorf("failed to unwrap S3 event from EventBridge event %s (%w)", event.ID, err)
+			}
+
+			return []events.S3EventRecord{record}, nil
+		}
+	}
+
+	event := &events.S3Event{}
+	if err := json.Unmarshal(payload, &event); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal event (%w)", err)
+	}
+
+	return event.Records, nil
+}
@@ -0,0 +1,583 @@
+// Code generated by go-mockgen 1.3.5; DO NOT EDIT.
+
+package lambdabase
+
+import (
+	"context"
+	"sync"
+
+	events "github.com/aws/aws-lambda-go/events"
+	v2 "github.com/go-nacelle/log/v2"
+)
+
+// MockS3EventHandlerInitializer is a mock implementation of the
+// s3EventHandlerInitializer interface (from the package
+// github.com/go-nacelle/lambdabase) used for unit testing.
+type MockS3EventHandlerInitializer struct {
+	// HandleFunc is an instance of a mock function object controlling the
+	// behavior of the method Handle.
+	HandleFunc *S3EventHandlerInitializerHandleFunc
+	// InitFunc is an instance of a mock function object controlling the
+	// behavior of the method Init.
+	InitFunc *S3EventHandlerInitializerInitFunc
+}
+
+// NewMockS3EventHandlerInitializer creates a new mock of the
+// s3EventHandlerInitializer interface. All methods return zero values for
+// all results, unless overwritten.
+func NewMockS3EventHandlerInitializer() *MockS3EventHandlerInitializer {
+	return &MockS3EventHandlerInitializer{
+		HandleFunc: &S3EventHandlerInitializerHandleFunc{
+			defaultHook: func(context.Context, []events.S3EventRecord, v2.Logger) (r0 error) {
+				return
+			},
+		},
+		InitFunc: &S3EventHandlerInitializerInitFunc{
+			defaultHook: func(context.Context) (r0 error) {
+				return
+			},
+		},
+	}
+}
+
+// NewStrictMockS3EventHandlerInitializer creates a new mock of the
+// s3EventHandlerInitializer interface. All methods panic on invocation,
+// unless overwritten.
+func NewStrictMockS3EventHandlerInitializer() *MockS3EventHandlerInitializer {
+	return &MockS3EventHandlerInitializer{
+		HandleFunc: &S3EventHandlerInitializerHandleFunc{
+			defaultHook: func(context.Context, []events.S3EventRecord, v2.Logger) error {
+				panic("unexpected invocation of MockS3EventHandlerInitializer.Handle")
+			},
+		},
+		InitFunc: &S3EventHandlerInitializerInitFunc{
+			defaultHook: func(context.Context) error {
+				panic("unexpected invocation of MockS3EventHandlerInitializer.Init")
+			},
+		},
+	}
+}
+
+// surrogateMockS3EventHandlerInitializer is a copy of the
+// s3EventHandlerInitializer interface (from the package
+// github.com/go-nacelle/lambdabase). It is redefined here as it is
+// unexported in the source package.
+type surrogateMockS3EventHandlerInitializer interface {
+	Handle(context.Context, []events.S3EventRecord, v2.Logger) error
+	Init(context.Context) error
+}
+
+// NewMockS3EventHandlerInitializerFrom creates a new mock of the
+// MockS3EventHandlerInitializer interface. All methods delegate to the
+// given implementation, unless overwritten.
+func NewMockS3EventHandlerInitializerFrom(i surrogateMockS3EventHandlerInitializer) *MockS3EventHandlerInitializer {
+	return &MockS3EventHandlerInitializer{
+		HandleFunc: &S3EventHandlerInitializerHandleFunc{
+			defaultHook: i.Handle,
+		},
+		InitFunc: &S3EventHandlerInitializerInitFunc{
+			defaultHook: i.Init,
+		},
+	}
+}
+
+// S3EventHandlerInitializerHandleFunc describes the behavior when the
+// Handle method of the parent MockS3EventHandlerInitializer instance is
+// invoked.
+type S3EventHandlerInitializerHandleFunc struct {
+	defaultHook func(context.Context, []events.S3EventRecord, v2.Logger) error
+	hooks       []func(context.Context, []events.S3EventRecord, v2.Logger) error
+	history     []S3EventHandlerInitializerHandleFuncCall
+	mutex       sync.Mutex
+}
+
+// Handle delegates to the next hook function in the queue and stores the
+// parameter and result values of this invocation.
+func (m *MockS3EventHandlerInitializer) Handle(v0 context.Context, v1 []events.S3EventRecord, v2 v2.Logger) error {
+	r0 := m.HandleFunc.nextHook()(v0, v1, v2)
+	m.HandleFunc.appendCall(S3EventHandlerInitializerHandleFuncCall{v0, v1, v2, r0})
+	return r0
+}
+
+// SetDefaultHook sets function that is called when the Handle method of the
+// parent MockS3EventHandlerInitializer instance is invoked and the hook
+// queue is empty.
+func (f *S3EventHandlerInitializerHandleFunc) SetDefaultHook(hook func(context.Context, []events.S3EventRecord, v2.Logger) error) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// Handle method of the parent MockS3EventHandlerInitializer instance
+// invokes the hook at the front of the queue and discards it. After the
+// queue is empty, the default hook function is invoked for any future
+// action.
+func (f *S3EventHandlerInitializerHandleFunc) PushHook(hook func(context.Context, []events.S3EventRecord, v2.Logger) error) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultHook with a function that returns the
+// given values.
+func (f *S3EventHandlerInitializerHandleFunc) SetDefaultReturn(r0 error) {
+	f.SetDefaultHook(func(context.Context, []events.S3EventRecord, v2.Logger) error {
+		return r0
+	})
+}
+
+// PushReturn calls PushHook with a function that returns the given values.
+func (f *S3EventHandlerInitializerHandleFunc) PushReturn(r0 error) {
+	f.PushHook(func(context.Context, []events.S3EventRecord, v2.Logger) error {
+		return r0
+	})
+}
+
+func (f *S3EventHandlerInitializerHandleFunc) nextHook() func(context.Context, []events.S3EventRecord, v2.Logger) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *S3EventHandlerInitializerHandleFunc) appendCall(r0 S3EventHandlerInitializerHandleFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of S3EventHandlerInitializerHandleFuncCall
+// objects describing the invocations of this function.
+func (f *S3EventHandlerInitializerHandleFunc) History() []S3EventHandlerInitializerHandleFuncCall {
+	f.mutex.Lock()
+	history := make([]S3EventHandlerInitializerHandleFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// S3EventHandlerInitializerHandleFuncCall is an object that describes an
+// invocation of method Handle on an instance of
+// MockS3EventHandlerInitializer.
+type S3EventHandlerInitializerHandleFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 []events.S3EventRecord
+	// Arg2 is the value of the 3rd argument passed to this method
+	// invocation.
+	Arg2 v2.Logger
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c S3EventHandlerInitializerHandleFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1, c.Arg2}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c S3EventHandlerInitializerHandleFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0}
+}
+
+// S3EventHandlerInitializerInitFunc describes the behavior when the Init
+// method of the parent MockS3EventHandlerInitializer instance is invoked.
+type S3EventHandlerInitializerInitFunc struct {
+	defaultHook func(context.Context) error
+	hooks       []func(context.Context) error
+	history     []S3EventHandlerInitializerInitFuncCall
+	mutex       sync.Mutex
+}
+
+// Init delegates to the next hook function in the queue and stores the
+// parameter and result values of this invocation.
+func (m *MockS3EventHandlerInitializer) Init(v0 context.Context) error {
+	r0 := m.InitFunc.nextHook()(v0)
+	m.InitFunc.appendCall(S3EventHandlerInitializerInitFuncCall{v0, r0})
+	return r0
+}
+
+// SetDefaultHook sets function that is called when the Init method of the
+// parent MockS3EventHandlerInitializer instance is invoked and the hook
+// queue is empty.
+func (f *S3EventHandlerInitializerInitFunc) SetDefaultHook(hook func(context.Context) error) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// Init method of the parent MockS3EventHandlerInitializer instance invokes
+// the hook at the front of the queue and discards it. After the queue is
+// empty, the default hook function is invoked for any future action.
+func (f *S3EventHandlerInitializerInitFunc) PushHook(hook func(context.Context) error) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultHook with a function that returns the
+// given values.
+func (f *S3EventHandlerInitializerInitFunc) SetDefaultReturn(r0 error) {
+	f.SetDefaultHook(func(context.Context) error {
+		return r0
+	})
+}
+
+// PushReturn calls PushHook with a function that returns the given values.
+func (f *S3EventHandlerInitializerInitFunc) PushReturn(r0 error) {
+	f.PushHook(func(context.Context) error {
+		return r0
+	})
+}
+
+func (f *S3EventHandlerInitializerInitFunc) nextHook() func(context.Context) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *S3EventHandlerInitializerInitFunc) appendCall(r0 S3EventHandlerInitializerInitFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of S3EventHandlerInitializerInitFuncCall
+// objects describing the invocations of this function.
+func (f *S3EventHandlerInitializerInitFunc) History() []S3EventHandlerInitializerInitFuncCall {
+	f.mutex.Lock()
+	history := make([]S3EventHandlerInitializerInitFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// S3EventHandlerInitializerInitFuncCall is an object that describes an
+// invocation of method Init on an instance of
+// MockS3EventHandlerInitializer.
+type S3EventHandlerInitializerInitFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c S3EventHandlerInitializerInitFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c S3EventHandlerInitializerInitFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0}
+}
+
+// MockS3RecordHandlerInitializer is a mock implementation of the
+// s3RecordHandlerInitializer interface (from the package
+// github.com/go-nacelle/lambdabase) used for unit testing.
+type MockS3RecordHandlerInitializer struct {
+	// HandleFunc is an instance of a mock function object controlling the
+	// behavior of the method Handle.
+	HandleFunc *S3RecordHandlerInitializerHandleFunc
+	// InitFunc is an instance of a mock function object controlling the
+	// behavior of the method Init.
+	InitFunc *S3RecordHandlerInitializerInitFunc
+}
+
+// NewMockS3RecordHandlerInitializer creates a new mock of the
+// s3RecordHandlerInitializer interface. All methods return zero values
+// for all results, unless overwritten.
+func NewMockS3RecordHandlerInitializer() *MockS3RecordHandlerInitializer {
+	return &MockS3RecordHandlerInitializer{
+		HandleFunc: &S3RecordHandlerInitializerHandleFunc{
+			defaultHook: func(context.Context, events.S3EventRecord, v2.Logger) (r0 error) {
+				return
+			},
+		},
+		InitFunc: &S3RecordHandlerInitializerInitFunc{
+			defaultHook: func(context.Context) (r0 error) {
+				return
+			},
+		},
+	}
+}
+
+// NewStrictMockS3RecordHandlerInitializer creates a new mock of the
+// s3RecordHandlerInitializer interface. All methods panic on invocation,
+// unless overwritten.
+func NewStrictMockS3RecordHandlerInitializer() *MockS3RecordHandlerInitializer {
+	return &MockS3RecordHandlerInitializer{
+		HandleFunc: &S3RecordHandlerInitializerHandleFunc{
+			defaultHook: func(context.Context, events.S3EventRecord, v2.Logger) error {
+				panic("unexpected invocation of MockS3RecordHandlerInitializer.Handle")
+			},
+		},
+		InitFunc: &S3RecordHandlerInitializerInitFunc{
+			defaultHook: func(context.Context) error {
+				panic("unexpected invocation of MockS3RecordHandlerInitializer.Init")
+			},
+		},
+	}
+}
+
+// surrogateMockS3RecordHandlerInitializer is a copy of the
+// s3RecordHandlerInitializer interface (from the package
+// github.com/go-nacelle/lambdabase). It is redefined here as it is
+// unexported in the source package.
+type surrogateMockS3RecordHandlerInitializer interface {
+	Handle(context.Context, events.S3EventRecord, v2.Logger) error
+	Init(context.Context) error
+}
+
+// NewMockS3RecordHandlerInitializerFrom creates a new mock of the
+// MockS3RecordHandlerInitializer interface. All methods delegate to the
+// given implementation, unless overwritten.
+func NewMockS3RecordHandlerInitializerFrom(i surrogateMockS3RecordHandlerInitializer) *MockS3RecordHandlerInitializer {
+	return &MockS3RecordHandlerInitializer{
+		HandleFunc: &S3RecordHandlerInitializerHandleFunc{
+			defaultHook: i.Handle,
+		},
+		InitFunc: &S3RecordHandlerInitializerInitFunc{
+			defaultHook: i.Init,
+		},
+	}
+}
+
+// S3RecordHandlerInitializerHandleFunc describes the behavior when the
+// Handle method of the parent MockS3RecordHandlerInitializer instance is
+// invoked.
+type S3RecordHandlerInitializerHandleFunc struct {
+	defaultHook func(context.Context, events.S3EventRecord, v2.Logger) error
+	hooks       []func(context.Context, events.S3EventRecord, v2.Logger) error
+	history     []S3RecordHandlerInitializerHandleFuncCall
+	mutex       sync.Mutex
+}
+
+// Handle delegates to the next hook function in the queue and stores the
+// parameter and result values of this invocation.
+func (m *MockS3RecordHandlerInitializer) Handle(v0 context.Context, v1 events.S3EventRecord, v2 v2.Logger) error {
+	r0 := m.HandleFunc.nextHook()(v0, v1, v2)
+	m.HandleFunc.appendCall(S3RecordHandlerInitializerHandleFuncCall{v0, v1, v2, r0})
+	return r0
+}
+
+// SetDefaultHook sets function that is called when the Handle method of the
+// parent MockS3RecordHandlerInitializer instance is invoked and the hook
+// queue is empty.
+func (f *S3RecordHandlerInitializerHandleFunc) SetDefaultHook(hook func(context.Context, events.S3EventRecord, v2.Logger) error) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// Handle method of the parent MockS3RecordHandlerInitializer instance
+// invokes the hook at the front of the queue and discards it. After the
+// queue is empty, the default hook function is invoked for any future
+// action.
+func (f *S3RecordHandlerInitializerHandleFunc) PushHook(hook func(context.Context, events.S3EventRecord, v2.Logger) error) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultHook with a function that returns the
+// given values.
+func (f *S3RecordHandlerInitializerHandleFunc) SetDefaultReturn(r0 error) {
+	f.SetDefaultHook(func(context.Context, events.S3EventRecord, v2.Logger) error {
+		return r0
+	})
+}
+
+// PushReturn calls PushHook with a function that returns the given values.
+func (f *S3RecordHandlerInitializerHandleFunc) PushReturn(r0 error) {
+	f.PushHook(func(context.Context, events.S3EventRecord, v2.Logger) error {
+		return r0
+	})
+}
+
+func (f *S3RecordHandlerInitializerHandleFunc) nextHook() func(context.Context, events.S3EventRecord, v2.Logger) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *S3RecordHandlerInitializerHandleFunc) appendCall(r0 S3RecordHandlerInitializerHandleFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of S3RecordHandlerInitializerHandleFuncCall
+// objects describing the invocations of this function.
+func (f *S3RecordHandlerInitializerHandleFunc) History() []S3RecordHandlerInitializerHandleFuncCall {
+	f.mutex.Lock()
+	history := make([]S3RecordHandlerInitializerHandleFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// S3RecordHandlerInitializerHandleFuncCall is an object that describes an
+// invocation of method Handle on an instance of
+// MockS3RecordHandlerInitializer.
+type S3RecordHandlerInitializerHandleFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 events.S3EventRecord
+	// Arg2 is the value of the 3rd argument passed to this method
+	// invocation.
+	Arg2 v2.Logger
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c S3RecordHandlerInitializerHandleFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1, c.Arg2}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c S3RecordHandlerInitializerHandleFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0}
+}
+
+// S3RecordHandlerInitializerInitFunc describes the behavior when the Init
+// method of the parent MockS3RecordHandlerInitializer instance is
+// invoked.
+type S3RecordHandlerInitializerInitFunc struct {
+	defaultHook func(context.Context) error
+	hooks       []func(context.Context) error
+	history     []S3RecordHandlerInitializerInitFuncCall
+	mutex       sync.Mutex
+}
+
+// Init delegates to the next hook function in the queue and stores the
+// parameter and result values of this invocation.
+func (m *MockS3RecordHandlerInitializer) Init(v0 context.Context) error {
+	r0 := m.InitFunc.nextHook()(v0)
+	m.InitFunc.appendCall(S3RecordHandlerInitializerInitFuncCall{v0, r0})
+	return r0
+}
+
+// SetDefaultHook sets function that is called when the Init method of the
+// parent MockS3RecordHandlerInitializer instance is invoked and the hook
+// queue is empty.
+func (f *S3RecordHandlerInitializerInitFunc) SetDefaultHook(hook func(context.Context) error) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// Init method of the parent MockS3RecordHandlerInitializer instance
+// invokes the hook at the front of the queue and discards it. After the
+// queue is empty, the default hook function is invoked for any future
+// action.
+func (f *S3RecordHandlerInitializerInitFunc) PushHook(hook func(context.Context) error) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultHook with a function that returns the
+// given values.
+func (f *S3RecordHandlerInitializerInitFunc) SetDefaultReturn(r0 error) {
+	f.SetDefaultHook(func(context.Context) error {
+		return r0
+	})
+}
+
+// PushReturn calls PushHook with a function that returns the given values.
+func (f *S3RecordHandlerInitializerInitFunc) PushReturn(r0 error) {
+	f.PushHook(func(context.Context) error {
+		return r0
+	})
+}
+
+func (f *S3RecordHandlerInitializerInitFunc) nextHook() func(context.Context) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *S3RecordHandlerInitializerInitFunc) appendCall(r0 S3RecordHandlerInitializerInitFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of S3RecordHandlerInitializerInitFuncCall
+// objects describing the invocations of this function.
+func (f *S3RecordHandlerInitializerInitFunc) History() []S3RecordHandlerInitializerInitFuncCall {
+	f.mutex.Lock()
+	history := make([]S3RecordHandlerInitializerInitFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// S3RecordHandlerInitializerInitFuncCall is an object that describes an
+// invocation of method Init on an instance of
+// MockS3RecordHandlerInitializer.
+type S3RecordHandlerInitializerInitFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c S3RecordHandlerInitializerInitFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c S3RecordHandlerInitializerInitFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0}
+}
@@ -0,0 +1,58 @@
+package lambdabase
+
+import (
+	"context"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/go-nacelle/nacelle/v2"
+)
+
+type (
+	S3RecordHandler interface {
+		Handle(ctx context.Context, record events.S3EventRecord, logger nacelle.Logger) error
+	}
+
+	s3RecordHandlerInitializer interface {
+		nacelle.Initializer
+		S3RecordHandler
+	}
+
+	s3RecordHandler struct {
+		Services         *nacelle.ServiceContainer `service:"services"`
+		handler          S3RecordHandler
+		recordMiddleware []RecordMiddleware
+	}
+)
+
+func NewS3RecordServer(handler S3RecordHandler, configs ...ConfigFunc) *Server {
+	options := getOptions(configs)
+
+	return NewS3EventServer(&s3RecordHandler{
+		handler:          handler,
+		recordMiddleware: options.recordMiddleware,
+	}, configs...)
+}
+
+func (s *s3RecordHandler) Init(ctx context.Context) error {
+	return doInit(ctx, s.Services, s.handler)
+}
+
+func (h *s3RecordHandler) Handle(ctx context.Context, records []events.S3EventRecord, logger nacelle.Logger) error {
+	for _, record := range records {
+		recordLogger := logger.WithFields(map[string]interface{}{
+			"bucket":    record.S3.Bucket.Name,
+			"key":       record.S3.Object.URLDecodedKey,
+			"versionId": record.S3.Object.VersionID,
+			"eventName": record.EventName,
+		})
+
+		recordLogger.Debug("Handling record")
+
+		if err := invokeRecordHandler(ctx, h.recordMiddleware, record, recordLogger, h.handler.Handle); err != nil {
+			return newRecordError("S3", "record", record.S3.Bucket.Name+"/"+record.S3.Object.URLDecodedKey, err)
+		}
+	}
+
+	logger.Debug("S3 record handled successfully")
+	return nil
+}
@@ -0,0 +1,370 @@
+package lambdabase
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+	mockassert "github.com/derision-test/go-mockgen/testutil/assert"
+	"github.com/go-nacelle/config/v3"
+	"github.com/go-nacelle/nacelle/v2"
+	"github.com/stretchr/testify/require"
+)
+
+var testS3Payload = `{
+	"Records": [
+		{
+			"eventVersion": "2.1",
+			"eventSource": "aws:s3",
+			"awsRegion": "us-east-1",
+			"eventName": "ObjectCreated:Put",
+			"s3": {
+				"bucket": {
+					"name": "bucket",
+					"arn": "arn:aws:s3:::bucket"
+				},
+				"object": {
+					"key": "path/to/my+file%3F.txt",
+					"size": 1024,
+					"versionId": "v1"
+				}
+			}
+		}
+	]
+}`
+
+var testS3Records = []events.S3EventRecord{
+	{
+		EventVersion: "2.1",
+		EventSource:  "aws:s3",
+		AWSRegion:    "us-east-1",
+		EventName:    "ObjectCreated:Put",
+		S3: events.S3Entity{
+			Bucket: events.S3Bucket{
+				Name: "bucket",
+				Arn:  "arn:aws:s3:::bucket",
+			},
+			Object: events.S3Object{
+				Key:           "path/to/my+file%3F.txt",
+				URLDecodedKey: "path/to/my file?.txt",
+				Size:          1024,
+				VersionID:     "v1",
+			},
+		},
+	},
+}
+
+var testS3OverSQSPayload = `{
+	"Records": [
+		{
+			"messageId": "m1",
+			"eventSource": "aws:sqs",
+			"body": "{\"Records\": [{\"eventSource\": \"aws:s3\", \"eventName\": \"ObjectCreated:Put\", \"s3\": {\"bucket\": {\"name\": \"bucket\"}, \"object\": {\"key\": \"a+b.txt\"}}}]}"
+		},
+		{
+			"messageId": "m2",
+			"eventSource": "aws:sqs",
+			"body": "{\"Service\": \"Amazon S3\", \"Event\": \"s3:TestEvent\", \"Bucket\": \"bucket\"}"
+		},
+		{
+			"messageId": "m3",
+			"eventSource": "aws:sqs",
+			"body": "{\"Type\": \"Notification\", \"MessageId\": \"n1\", \"Message\": \"{\\\"Records\\\": [{\\\"eventSource\\\": \\\"aws:s3\\\", \\\"eventName\\\": \\\"ObjectRemoved:Delete\\\", \\\"s3\\\": {\\\"bucket\\\": {\\\"name\\\": \\\"bucket\\\"}, \\\"object\\\": {\\\"key\\\": \\\"c.txt\\\"}}}]}\"}"
+		}
+	]
+}`
+
+var testS3OverSNSPayload = `{
+	"Records": [
+		{
+			"EventSource": "aws:sns",
+			"Sns": {
+				"Type": "Notification",
+				"MessageId": "n1",
+				"Message": "{\"Records\": [{\"eventSource\": \"aws:s3\", \"eventName\": \"ObjectCreated:Copy\", \"s3\": {\"bucket\": {\"name\": \"bucket\"}, \"object\": {\"key\": \"d.txt\"}}}]}"
+			}
+		}
+	]
+}`
+
+var testS3EventBridgePayload = `{
+	"version": "0",
+	"id": "e1",
+	"detail-type": "Object Created",
+	"source": "aws.s3",
+	"account": "123456789012",
+	"time": "2021-11-12T00:00:00Z",
+	"region": "us-east-1",
+	"resources": ["arn:aws:s3:::bucket"],
+	"detail": {
+		"version": "0",
+		"bucket": {"name": "bucket"},
+		"object": {
+			"key": "my file.txt",
+			"size": 5,
+			"etag": "b1946ac92492d2347c6235b4d2611184",
+			"version-id": "v2",
+			"sequencer": "00617F08299329D189"
+		},
+		"request-id": "r1",
+		"requester": "123456789012",
+		"source-ip-address": "1.2.3.4",
+		"reason": "PutObject"
+	}
+}`
+
+func TestS3EventInit(t *testing.T) {
+	handler := NewMockS3EventHandlerInitializer()
+	outer := &s3EventHandler{
+		handler:  handler,
+		Logger:   nacelle.NewNilLogger(),
+		Services: nacelle.NewServiceContainer(),
+	}
+
+	ctx := context.Background()
+
+	cfg := nacelle.NewConfig(nacelle.NewTestEnvSourcer(nil))
+	ctx = config.WithConfig(ctx, cfg)
+
+	err := outer.Init(ctx)
+	require.Nil(t, err)
+	mockassert.CalledOnceWith(t, handler.InitFunc, mockassert.Values(ctx))
+}
+
+func TestS3EventBadInjection(t *testing.T) {
+	handler := &badInjectionS3EventHandler{}
+	outer := &s3EventHandler{
+		handler:  handler,
+		Logger:   nacelle.NewNilLogger(),
+		Services: makeBadContainer(),
+	}
+
+	ctx := context.Background()
+
+	cfg := nacelle.NewConfig(nacelle.NewTestEnvSourcer(nil))
+	ctx = config.WithConfig(ctx, cfg)
+
+	err := outer.Init(ctx)
+	require.NotNil(t, err)
+	require.Contains(t, err.Error(), "ServiceA")
+}
+
+func TestS3EventInitError(t *testing.T) {
+	handler := NewMockS3EventHandlerInitializer()
+	handler.InitFunc.SetDefaultReturn(fmt.Errorf("oops"))
+	outer := &s3EventHandler{
+		handler:  handler,
+		Logger:   nacelle.NewNilLogger(),
+		Services: makeBadContainer(),
+	}
+
+	ctx := context.Background()
+
+	cfg := nacelle.NewConfig(nacelle.NewTestEnvSourcer(nil))
+	ctx = config.WithConfig(ctx, cfg)
+
+	err := outer.Init(ctx)
+	require.EqualError(t, err, "oops")
+}
+
+func TestS3RecordInit(t *testing.T) {
+	handler := NewMockS3RecordHandlerInitializer()
+	outer := &s3RecordHandler{
+		handler:  handler,
+		Services: nacelle.NewServiceContainer(),
+	}
+
+	ctx := context.Background()
+
+	cfg := nacelle.NewConfig(nacelle.NewTestEnvSourcer(nil))
+	ctx = config.WithConfig(ctx, cfg)
+
+	err := outer.Init(ctx)
+	require.Nil(t, err)
+	mockassert.CalledOnceWith(t, handler.InitFunc, mockassert.Values(ctx))
+}
+
+func TestS3RecordBadInjection(t *testing.T) {
+	handler := &badInjectionS3RecordHandler{}
+	outer := &s3RecordHandler{
+		handler:  handler,
+		Services: makeBadContainer(),
+	}
+
+	ctx := context.Background()
+
+	cfg := nacelle.NewConfig(nacelle.NewTestEnvSourcer(nil))
+	ctx = config.WithConfig(ctx, cfg)
+
+	err := outer.Init(ctx)
+	require.NotNil(t, err)
+	require.Contains(t, err.Error(), "ServiceA")
+}
+
+func TestS3RecordInitError(t *testing.T) {
+	handler := NewMockS3RecordHandlerInitializer()
+	handler.InitFunc.SetDefaultReturn(fmt.Errorf("oops"))
+	outer := &s3RecordHandler{
+		handler:  handler,
+		Services: nacelle.NewServiceContainer(),
+	}
+
+	ctx := context.Background()
+
+	cfg := nacelle.NewConfig(nacelle.NewTestEnvSourcer(nil))
+	ctx = config.WithConfig(ctx, cfg)
+
+	err := outer.Init(ctx)
+	require.EqualError(t, err, "oops")
+}
+
+func TestS3EventInvoke(t *testing.T) {
+	handler := NewMockS3EventHandlerInitializer()
+	outer := &s3EventHandler{
+		handler: handler,
+		Logger:  nacelle.NewNilLogger(),
+	}
+
+	response, err := outer.Invoke(context.Background(), []byte(testS3Payload))
+	require.Nil(t, err)
+	require.Nil(t, response)
+	mockassert.CalledOnceWith(t, handler.HandleFunc, mockassert.Values(mockassert.Skip, testS3Records))
+}
+
+func TestS3EventInvokeError(t *testing.T) {
+	handler := NewMockS3EventHandlerInitializer()
+	outer := &s3EventHandler{
+		handler: handler,
+		Logger:  nacelle.NewNilLogger(),
+	}
+
+	handler.HandleFunc.SetDefaultReturn(fmt.Errorf("oops"))
+	_, err := outer.Invoke(context.Background(), []byte(testS3Payload))
+	require.EqualError(t, err, "failed to process S3 event (oops)")
+}
+
+func TestS3EventInvokeUnwrapsSQS(t *testing.T) {
+	handler := NewMockS3EventHandlerInitializer()
+	outer := &s3EventHandler{
+		handler:         handler,
+		Logger:          nacelle.NewNilLogger(),
+		unwrapEnvelopes: true,
+	}
+
+	_, err := outer.Invoke(context.Background(), []byte(testS3OverSQSPayload))
+	require.Nil(t, err)
+	mockassert.CalledOnce(t, handler.HandleFunc)
+
+	// The test event carries no records
+	records := handler.HandleFunc.History()[0].Arg1
+	require.Len(t, records, 2)
+	require.Equal(t, "ObjectCreated:Put", records[0].EventName)
+	require.Equal(t, "a b.txt", records[0].S3.Object.URLDecodedKey)
+	require.Equal(t, "ObjectRemoved:Delete", records[1].EventName)
+	require.Equal(t, "c.txt", records[1].S3.Object.URLDecodedKey)
+
+	// Direct S3 deliveries are still accepted when unwrapping is enabled
+	_, err = outer.Invoke(context.Background(), []byte(testS3Payload))
+	require.Nil(t, err)
+	require.Equal(t, testS3Records, handler.HandleFunc.History()[1].Arg1)
+}
+
+func TestS3EventInvokeUnwrapsSNS(t *testing.T) {
+	handler := NewMockS3EventHandlerInitializer()
+	outer := &s3EventHandler{
+		handler:         handler,
+		Logger:          nacelle.NewNilLogger(),
+		unwrapEnvelopes: true,
+	}
+
+	_, err := outer.Invoke(context.Background(), []byte(testS3OverSNSPayload))
+	require.Nil(t, err)
+
+	records := handler.HandleFunc.History()[0].Arg1
+	require.Len(t, records, 1)
+	require.Equal(t, "ObjectCreated:Copy", records[0].EventName)
+	require.Equal(t, "d.txt", records[0].S3.Object.URLDecodedKey)
+}
+
+func TestS3EventInvokeUnwrapsEventBridge(t *testing.T) {
+	handler := NewMockS3EventHandlerInitializer()
+	outer := &s3EventHandler{
+		handler:         handler,
+		Logger:          nacelle.NewNilLogger(),
+		unwrapEnvelopes: true,
+	}
+
+	_, err := outer.Invoke(context.Background(), []byte(testS3EventBridgePayload))
+	require.Nil(t, err)
+
+	records := handler.HandleFunc.History()[0].Arg1
+	require.Len(t, records, 1)
+	require.Equal(t, "aws:s3", records[0].EventSource)
+	require.Equal(t, "us-east-1", records[0].AWSRegion)
+	require.Equal(t, "ObjectCreated:PutObject", records[0].EventName)
+	require.Equal(t, "123456789012", records[0].PrincipalID.PrincipalID)
+	require.Equal(t, "1.2.3.4", records[0].RequestParameters.SourceIPAddress)
+	require.Equal(t, events.S3Bucket{Name: "bucket", Arn: "arn:aws:s3:::bucket"}, records[0].S3.Bucket)
+	require.Equal(t, events.S3Object{
+		Key:           "my+file.txt",
+		URLDecodedKey: "my file.txt",
+		Size:          5,
+		ETag:          "b1946ac92492d2347c6235b4d2611184",
+		VersionID:     "v2",
+		Sequencer:     "00617F08299329D189",
+	}, records[0].S3.Object)
+}
+
+func TestS3EventInvokeUnwrapDisabled(t *testing.T) {
+	handler := NewMockS3EventHandlerInitializer()
+	outer := &s3EventHandler{
+		handler: handler,
+		Logger:  nacelle.NewNilLogger(),
+	}
+
+	_, err := outer.Invoke(context.Background(), []byte(testS3OverSQSPayload))
+	require.Nil(t, err)
+	require.Equal(t, events.S3Entity{}, handler.HandleFunc.History()[0].Arg1[0].S3)
+}
+
+func TestS3RecordHandle(t *testing.T) {
+	handler := NewMockS3RecordHandlerInitializer()
+	outer := &s3RecordHandler{handler: handler}
+
+	err := outer.Handle(context.Background(), testS3Records, nacelle.NewNilLogger())
+	require.Nil(t, err)
+
+	for _, record := range testS3Records {
+		mockassert.CalledOnceWith(t, handler.HandleFunc, mockassert.Values(mockassert.Skip, record))
+	}
+}
+
+func TestS3RecordHandleError(t *testing.T) {
+	handler := NewMockS3RecordHandlerInitializer()
+	handler.HandleFunc.PushReturn(fmt.Errorf("oops"))
+	outer := &s3RecordHandler{handler: handler}
+
+	err := outer.Handle(context.Background(), testS3Records, nacelle.NewNilLogger())
+	require.EqualError(t, err, "failed to process S3 record bucket/path/to/my file?.txt (oops)")
+	mockassert.CalledN(t, handler.HandleFunc, 1)
+}
+
+//
+// Bad Injection
+
+type badInjectionS3EventHandler struct {
+	ServiceA *A `service:"A"`
+}
+
+func (i *badInjectionS3EventHandler) Handle(ctx context.Context, records []events.S3EventRecord, logger nacelle.Logger) error {
+	return nil
+}
+
+type badInjectionS3RecordHandler struct {
+	ServiceA *A `service:"A"`
+}
+
+func (i *badInjectionS3RecordHandler) Handle(ctx context.Context, record events.S3EventRecord, logger nacelle.Logger) error {
+	return nil
+}
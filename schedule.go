@@ -0,0 +1,50 @@
+package lambdabase
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/go-nacelle/nacelle/v2"
+)
+
+type (
+	ScheduleHandler interface {
+		Handle(ctx context.Context, scheduledTime time.Time, logger nacelle.Logger) error
+	}
+
+	scheduleHandlerInitializer interface {
+		nacelle.Initializer
+		ScheduleHandler
+	}
+
+	scheduleHandler struct {
+		Services *nacelle.ServiceContainer `service:"services"`
+		handler  ScheduleHandler
+	}
+)
+
+const (
+	scheduleSource     = "aws.events"
+	scheduleDetailType = "Scheduled Event"
+)
+
+func NewScheduleServer(handler ScheduleHandler, configs ...ConfigFunc) *Server {
+	return NewEventBridgeServer([]EventBridgeRoute{
+		NewEventBridgeRoute[json.RawMessage](scheduleSource, scheduleDetailType, &scheduleHandler{
+			handler: handler,
+		}),
+	}, configs...)
+}
+
+func (h *scheduleHandler) Init(ctx context.Context) error {
+	return doInit(ctx, h.Services, h.handler)
+}
+
+func (h *scheduleHandler) Handle(ctx context.Context, detail json.RawMessage, event events.CloudWatchEvent, logger nacelle.Logger) error {
+	// The resources of a scheduled event name the rule that triggered it
+	return h.handler.Handle(ctx, event.Time, logger.WithFields(map[string]interface{}{
+		"resources": event.Resources,
+	}))
+}
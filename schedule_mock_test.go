@@ -0,0 +1,298 @@
+// Code generated by go-mockgen 1.3.5; DO NOT EDIT.
+
+package lambdabase
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	v2 "github.com/go-nacelle/log/v2"
+)
+
+// MockScheduleHandlerInitializer is a mock implementation of the
+// scheduleHandlerInitializer interface (from the package
+// github.com/go-nacelle/lambdabase) used for unit testing.
+type MockScheduleHandlerInitializer struct {
+	// HandleFunc is an instance of a mock function object controlling the
+	// behavior of the method Handle.
+	HandleFunc *ScheduleHandlerInitializerHandleFunc
+	// InitFunc is an instance of a mock function object controlling the
+	// behavior of the method Init.
+	InitFunc *ScheduleHandlerInitializerInitFunc
+}
+
+// NewMockScheduleHandlerInitializer creates a new mock of the
+// scheduleHandlerInitializer interface. All methods return zero values
+// for all results, unless overwritten.
+func NewMockScheduleHandlerInitializer() *MockScheduleHandlerInitializer {
+	return &MockScheduleHandlerInitializer{
+		HandleFunc: &ScheduleHandlerInitializerHandleFunc{
+			defaultHook: func(context.Context, time.Time, v2.Logger) (r0 error) {
+				return
+			},
+		},
+		InitFunc: &ScheduleHandlerInitializerInitFunc{
+			defaultHook: func(context.Context) (r0 error) {
+				return
+			},
+		},
+	}
+}
+
+// NewStrictMockScheduleHandlerInitializer creates a new mock of the
+// scheduleHandlerInitializer interface. All methods panic on invocation,
+// unless overwritten.
+func NewStrictMockScheduleHandlerInitializer() *MockScheduleHandlerInitializer {
+	return &MockScheduleHandlerInitializer{
+		HandleFunc: &ScheduleHandlerInitializerHandleFunc{
+			defaultHook: func(context.Context, time.Time, v2.Logger) error {
+				panic("unexpected invocation of MockScheduleHandlerInitializer.Handle")
+			},
+		},
+		InitFunc: &ScheduleHandlerInitializerInitFunc{
+			defaultHook: func(context.Context) error {
+				panic("unexpected invocation of MockScheduleHandlerInitializer.Init")
+			},
+		},
+	}
+}
+
+// surrogateMockScheduleHandlerInitializer is a copy of the
+// scheduleHandlerInitializer interface (from the package
+// github.com/go-nacelle/lambdabase). It is redefined here as it is
+// unexported in the source package.
+type surrogateMockScheduleHandlerInitializer interface {
+	Handle(context.Context, time.Time, v2.Logger) error
+	Init(context.Context) error
+}
+
+// NewMockScheduleHandlerInitializerFrom creates a new mock of the
+// MockScheduleHandlerInitializer interface. All methods delegate to the
+// given implementation, unless overwritten.
+func NewMockScheduleHandlerInitializerFrom(i surrogateMockScheduleHandlerInitializer) *MockScheduleHandlerInitializer {
+	return &MockScheduleHandlerInitializer{
+		HandleFunc: &ScheduleHandlerInitializerHandleFunc{
+			defaultHook: i.Handle,
+		},
+		InitFunc: &ScheduleHandlerInitializerInitFunc{
+			defaultHook: i.Init,
+		},
+	}
+}
+
+// ScheduleHandlerInitializerHandleFunc describes the behavior when the
+// Handle method of the parent MockScheduleHandlerInitializer instance is
+// invoked.
+type ScheduleHandlerInitializerHandleFunc struct {
+	defaultHook func(context.Context, time.Time, v2.Logger) error
+	hooks       []func(context.Context, time.Time, v2.Logger) error
+	history     []ScheduleHandlerInitializerHandleFuncCall
+	mutex       sync.Mutex
+}
+
+// Handle delegates to the next hook function in the queue and stores the
+// parameter and result values of this invocation.
+func (m *MockScheduleHandlerInitializer) Handle(v0 context.Context, v1 time.Time, v2 v2.Logger) error {
+	r0 := m.HandleFunc.nextHook()(v0, v1, v2)
+	m.HandleFunc.appendCall(ScheduleHandlerInitializerHandleFuncCall{v0, v1, v2, r0})
+	return r0
+}
+
+// SetDefaultHook sets function that is called when the Handle method of the
+// parent MockScheduleHandlerInitializer instance is invoked and the hook
+// queue is empty.
+func (f *ScheduleHandlerInitializerHandleFunc) SetDefaultHook(hook func(context.Context, time.Time, v2.Logger) error) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// Handle method of the parent MockScheduleHandlerInitializer instance
+// invokes the hook at the front of the queue and discards it. After the
+// queue is empty, the default hook function is invoked for any future
+// action.
+func (f *ScheduleHandlerInitializerHandleFunc) PushHook(hook func(context.Context, time.Time, v2.Logger) error) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultHook with a function that returns the
+// given values.
+func (f *ScheduleHandlerInitializerHandleFunc) SetDefaultReturn(r0 error) {
+	f.SetDefaultHook(func(context.Context, time.Time, v2.Logger) error {
+		return r0
+	})
+}
+
+// PushReturn calls PushHook with a function that returns the given values.
+func (f *ScheduleHandlerInitializerHandleFunc) PushReturn(r0 error) {
+	f.PushHook(func(context.Context, time.Time, v2.Logger) error {
+		return r0
+	})
+}
+
+func (f *ScheduleHandlerInitializerHandleFunc) nextHook() func(context.Context, time.Time, v2.Logger) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *ScheduleHandlerInitializerHandleFunc) appendCall(r0 ScheduleHandlerInitializerHandleFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of ScheduleHandlerInitializerHandleFuncCall
+// objects describing the invocations of this function.
+func (f *ScheduleHandlerInitializerHandleFunc) History() []ScheduleHandlerInitializerHandleFuncCall {
+	f.mutex.Lock()
+	history := make([]ScheduleHandlerInitializerHandleFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// ScheduleHandlerInitializerHandleFuncCall is an object that describes an
+// invocation of method Handle on an instance of
+// MockScheduleHandlerInitializer.
+type ScheduleHandlerInitializerHandleFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 time.Time
+	// Arg2 is the value of the 3rd argument passed to this method
+	// invocation.
+	Arg2 v2.Logger
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c ScheduleHandlerInitializerHandleFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1, c.Arg2}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c ScheduleHandlerInitializerHandleFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0}
+}
+
+// ScheduleHandlerInitializerInitFunc describes the behavior when the Init
+// method of the parent MockScheduleHandlerInitializer instance is
+// invoked.
+type ScheduleHandlerInitializerInitFunc struct {
+	defaultHook func(context.Context) error
+	hooks       []func(context.Context) error
+	history     []ScheduleHandlerInitializerInitFuncCall
+	mutex       sync.Mutex
+}
+
+// Init delegates to the next hook function in the queue and stores the
+// parameter and result values of this invocation.
+func (m *MockScheduleHandlerInitializer) Init(v0 context.Context) error {
+	r0 := m.InitFunc.nextHook()(v0)
+	m.InitFunc.appendCall(ScheduleHandlerInitializerInitFuncCall{v0, r0})
+	return r0
+}
+
+// SetDefaultHook sets function that is called when the Init method of the
+// parent MockScheduleHandlerInitializer instance is invoked and the hook
+// queue is empty.
+func (f *ScheduleHandlerInitializerInitFunc) SetDefaultHook(hook func(context.Context) error) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// Init method of the parent MockScheduleHandlerInitializer instance
+// invokes the hook at the front of the queue and discards it. After the
+// queue is empty, the default hook function is invoked for any future
+// action.
+func (f *ScheduleHandlerInitializerInitFunc) PushHook(hook func(context.Context) error) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultHook with a function that returns the
+// given values.
+func (f *ScheduleHandlerInitializerInitFunc) SetDefaultReturn(r0 error) {
+	f.SetDefaultHook(func(context.Context) error {
+		return r0
+	})
+}
+
+// PushReturn calls PushHook with a function that returns the given values.
+func (f *ScheduleHandlerInitializerInitFunc) PushReturn(r0 error) {
+	f.PushHook(func(context.Context) error {
+		return r0
+	})
+}
+
+func (f *ScheduleHandlerInitializerInitFunc) nextHook() func(context.Context) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *ScheduleHandlerInitializerInitFunc) appendCall(r0 ScheduleHandlerInitializerInitFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of ScheduleHandlerInitializerInitFuncCall
+// objects describing the invocations of this function.
+func (f *ScheduleHandlerInitializerInitFunc) History() []ScheduleHandlerInitializerInitFuncCall {
+	f.mutex.Lock()
+	history := make([]ScheduleHandlerInitializerInitFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// ScheduleHandlerInitializerInitFuncCall is an object that describes an
+// invocation of method Init on an instance of
+// MockScheduleHandlerInitializer.
+type ScheduleHandlerInitializerInitFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c ScheduleHandlerInitializerInitFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c ScheduleHandlerInitializerInitFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0}
+}
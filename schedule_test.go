@@ -0,0 +1,114 @@
+package lambdabase
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	mockassert "github.com/derision-test/go-mockgen/testutil/assert"
+	"github.com/go-nacelle/config/v3"
+	"github.com/go-nacelle/nacelle/v2"
+	"github.com/stretchr/testify/require"
+)
+
+var testSchedulePayload = `{
+	"version": "0",
+	"id": "e1",
+	"detail-type": "Scheduled Event",
+	"source": "aws.events",
+	"account": "123456789012",
+	"time": "2021-11-12T00:05:00Z",
+	"region": "us-east-1",
+	"resources": ["arn:aws:events:us-east-1:123456789012:rule/every-five-minutes"],
+	"detail": {}
+}`
+
+func TestScheduleInit(t *testing.T) {
+	handler := NewMockScheduleHandlerInitializer()
+	outer := &scheduleHandler{
+		handler:  handler,
+		Services: nacelle.NewServiceContainer(),
+	}
+
+	ctx := context.Background()
+
+	cfg := nacelle.NewConfig(nacelle.NewTestEnvSourcer(nil))
+	ctx = config.WithConfig(ctx, cfg)
+
+	err := outer.Init(ctx)
+	require.Nil(t, err)
+	mockassert.CalledOnceWith(t, handler.InitFunc, mockassert.Values(ctx))
+}
+
+func TestScheduleBadInjection(t *testing.T) {
+	handler := &badInjectionScheduleHandler{}
+	outer := &scheduleHandler{
+		handler:  handler,
+		Services: makeBadContainer(),
+	}
+
+	ctx := context.Background()
+
+	cfg := nacelle.NewConfig(nacelle.NewTestEnvSourcer(nil))
+	ctx = config.WithConfig(ctx, cfg)
+
+	err := outer.Init(ctx)
+	require.NotNil(t, err)
+	require.Contains(t, err.Error(), "ServiceA")
+}
+
+func TestScheduleInitError(t *testing.T) {
+	handler := NewMockScheduleHandlerInitializer()
+	handler.InitFunc.SetDefaultReturn(fmt.Errorf("oops"))
+	outer := &scheduleHandler{
+		handler:  handler,
+		Services: nacelle.NewServiceContainer(),
+	}
+
+	ctx := context.Background()
+
+	cfg := nacelle.NewConfig(nacelle.NewTestEnvSourcer(nil))
+	ctx = config.WithConfig(ctx, cfg)
+
+	err := outer.Init(ctx)
+	require.EqualError(t, err, "oops")
+}
+
+func TestScheduleInvoke(t *testing.T) {
+	handler := NewMockScheduleHandlerInitializer()
+	server := NewScheduleServer(handler)
+	outer := server.handler.(*eventBridgeHandler)
+	outer.Logger = nacelle.NewNilLogger()
+
+	_, err := outer.Invoke(context.Background(), []byte(testSchedulePayload))
+	require.Nil(t, err)
+	mockassert.CalledOnceWith(t, handler.HandleFunc, mockassert.Values(mockassert.Skip, time.Date(2021, 11, 12, 0, 5, 0, 0, time.UTC)))
+
+	// Events other than scheduled events are rejected
+	_, err = outer.Invoke(context.Background(), []byte(testEventBridgePayload))
+	require.NotNil(t, err)
+	mockassert.CalledOnce(t, handler.HandleFunc)
+}
+
+func TestScheduleInvokeError(t *testing.T) {
+	handler := NewMockScheduleHandlerInitializer()
+	handler.HandleFunc.SetDefaultReturn(fmt.Errorf("oops"))
+	server := NewScheduleServer(handler)
+	outer := server.handler.(*eventBridgeHandler)
+	outer.Logger = nacelle.NewNilLogger()
+
+	_, err := outer.Invoke(context.Background(), []byte(testSchedulePayload))
+	require.EqualError(t, err, "failed to process EventBridge event e1 (oops)")
+}
+
+//
+// Bad Injection
+
+type badInjectionScheduleHandler struct {
+	ServiceA *A `service:"A"`
+}
+
+func (i *badInjectionScheduleHandler) Handle(ctx context.Context, scheduledTime time.Time, logger nacelle.Logger) error {
+	return nil
+}
@@ -6,8 +6,10 @@ import (
 	"net"
 	"net/rpc"
 	"sync"
+	"time"
 
 	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/aws/aws-lambda-go/lambda/messages"
 	"github.com/go-nacelle/config/v3"
 	"github.com/go-nacelle/nacelle/v2"
 	"github.com/go-nacelle/process/v2"
@@ -24,9 +26,23 @@ type (
 		handler      Handler
 		listener     net.Listener
 		server       *rpc.Server
+		runtimeAPI   *runtimeAPIClient
+		function     *lambda.Function
+		pollCtx      context.Context
+		cancelPoll   func()
 		once         *sync.Once
 		healthToken  healthToken
 		healthStatus *process.HealthComponentStatus
+		options      *options
+		inFlight     *inFlightInvocations
+		exitErr      error
+		exitMutex    sync.Mutex
+	}
+
+	// rpcFunction is the receiver of the RPC commands sent to a server. It
+	// exposes the same methods as lambda.Function.
+	rpcFunction struct {
+		server *Server
 	}
 
 	Handler interface {
@@ -41,43 +57,50 @@ func (f LambdaHandlerFunc) Invoke(ctx context.Context, payload []byte) ([]byte,
 	return f(ctx, payload)
 }
 
-func NewServer(handler Handler) *Server {
+func NewServer(handler Handler, configs ...ConfigFunc) *Server {
 	return &Server{
 		handler:     handler,
 		once:        &sync.Once{},
-		healthToken: healthToken(uuid.New().String()),
+		healthToken: healthToken{id: uuid.New().String()},
+		options:     getOptions(configs),
+		inFlight:    newInFlightInvocations(),
 	}
 }
 
 func (s *Server) Init(ctx context.Context) error {
+	serverConfig := &Config{}
+	if err := config.LoadFromContext(ctx, serverConfig); err != nil {
+		return err
+	}
+	serverConfig.apply(s.options)
+
+	s.healthToken.name = s.options.healthTokenName
 	healthStatus, err := s.Health.Register(s.healthToken)
 	if err != nil {
 		return err
 	}
 	s.healthStatus = healthStatus
 
-	serverConfig := &Config{}
-	if err := config.LoadFromContext(ctx, serverConfig); err != nil {
-		return err
-	}
+	s.Logger = newFieldNameLogger(s.Logger, s.options.loggerFieldNames)
 
-	if err := service.Inject(ctx, s.Services, s.handler); err != nil {
-		return err
+	if serverConfig.LambdaServerPort == nil {
+		return s.initRuntimeAPI(ctx, serverConfig.RuntimeAPI)
 	}
 
-	if err := s.handler.Init(ctx); err != nil {
+	if err := s.initHandler(ctx); err != nil {
 		return err
 	}
 
-	listener, err := makeListener("", serverConfig.LambdaServerPort)
+	listener, err := makeListener(s.options.listenerHost, *serverConfig.LambdaServerPort)
 	if err != nil {
 		return err
 	}
 
+	s.function = s.makeFunction()
 	server := rpc.NewServer()
 
-	if err := server.Register(lambda.NewFunction(s.handler)); err != nil {
-		return fmt.Errorf("failed to register RPC (%s)", err.Error())
+	if err := server.RegisterName("Function", &rpcFunction{server: s}); err != nil {
+		return fmt.Errorf("failed to register RPC (%w)", err)
 	}
 
 	s.server = server
@@ -85,9 +108,80 @@ func (s *Server) Init(ctx context.Context) error {
 	return nil
 }
 
+func (s *Server) initRuntimeAPI(ctx context.Context, address string) error {
+	s.runtimeAPI = newRuntimeAPIClient(address)
+
+	if err := s.initHandler(ctx); err != nil {
+		if reportErr := s.runtimeAPI.initError(ctx, err); reportErr != nil {
+			s.Logger.Error("Failed to report init error to runtime API (%s)", reportErr.Error())
+		}
+
+		return err
+	}
+
+	s.function = s.makeFunction()
+	s.pollCtx, s.cancelPoll = context.WithCancel(context.Background())
+	return nil
+}
+
+func (s *Server) initHandler(ctx context.Context) error {
+	services := s.Services
+	if len(s.options.loggerFieldNames) > 0 {
+		var err error
+		if services, err = overlayLogger(services, s.Logger); err != nil {
+			return err
+		}
+	}
+
+	if err := service.Inject(ctx, services, s.handler); err != nil {
+		return err
+	}
+
+	return s.handler.Init(ctx)
+}
+
+// overlayLogger returns a copy of the given container in which the logger
+// service is replaced with the given logger. The container's reference to
+// itself is also replaced so that handlers which inject their own
+// dependencies receive the same logger.
+func overlayLogger(services *nacelle.ServiceContainer, logger nacelle.Logger) (*nacelle.ServiceContainer, error) {
+	services, err := services.WithValues(map[interface{}]interface{}{"logger": logger})
+	if err != nil {
+		return nil, err
+	}
+
+	return services.WithValues(map[interface{}]interface{}{"services": services})
+}
+
+func (s *Server) makeFunction() *lambda.Function {
+	handler := reportErrors(s.trackDeadline(applyInvokeMiddleware(s.options.invokeMiddleware, s.handler)))
+	if s.options.recoverPanics {
+		handler = s.recoverInvoke(handler)
+	}
+
+	return lambda.NewFunction(s.trackInFlight(handler))
+}
+
 func (s *Server) Run(ctx context.Context) error {
+	var err error
+	if s.runtimeAPI != nil {
+		err = s.runRuntimeAPI(ctx)
+	} else {
+		err = s.runRPC(ctx)
+	}
+
+	if exitErr := s.exitError(); exitErr != nil {
+		return exitErr
+	}
+
+	return err
+}
+
+func (s *Server) runRPC(ctx context.Context) error {
 	defer s.close()
 	wg := sync.WaitGroup{}
+	conns := map[net.Conn]struct{}{}
+	connsMutex := sync.Mutex{}
 
 	s.healthStatus.Update(true)
 
@@ -103,19 +197,162 @@ func (s *Server) Run(ctx context.Context) error {
 			return err
 		}
 
+		connsMutex.Lock()
+		conns[conn] = struct{}{}
+		connsMutex.Unlock()
+
 		wg.Add(1)
 
 		go func() {
 			defer wg.Done()
 			s.server.ServeConn(conn)
+
+			connsMutex.Lock()
+			delete(conns, conn)
+			connsMutex.Unlock()
 		}()
 	}
 
-	s.Logger.Info("Draining lambda server")
-	wg.Wait()
+	// Idle connections would otherwise keep the server running until the
+	// client disconnects. Expiring the read deadline stops each connection
+	// from reading new requests, and the RPC server closes the connection
+	// once the responses of the requests already read are sent.
+	connsMutex.Lock()
+	for conn := range conns {
+		_ = conn.SetReadDeadline(time.Now())
+	}
+	connsMutex.Unlock()
+
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		wg.Wait()
+	}()
+
+	return s.drain(stopped)
+}
+
+func (s *Server) runRuntimeAPI(ctx context.Context) error {
+	defer s.close()
+
+	s.healthStatus.Update(true)
+
+	errs := make(chan error, 1)
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		errs <- s.pollRuntimeAPI(ctx)
+	}()
+
+	select {
+	case err := <-errs:
+		return err
+	case <-s.pollCtx.Done():
+	}
+
+	// An invocation received from the runtime API before polling stopped is
+	// awaited along with the tracked invocations.
+	if err := s.drain(stopped); err != nil {
+		return err
+	}
+
+	return <-errs
+}
+
+func (s *Server) pollRuntimeAPI(ctx context.Context) error {
+	for {
+		request, err := s.runtimeAPI.next(s.pollCtx)
+		if err != nil {
+			if s.pollCtx.Err() != nil {
+				break
+			}
+
+			return err
+		}
+
+		if err := s.invokeRuntimeAPI(ctx, request); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
+func (s *Server) invokeRuntimeAPI(ctx context.Context, request *messages.InvokeRequest) error {
+	response := &messages.InvokeResponse{}
+	if err := s.invoke(request, response); err != nil {
+		return err
+	}
+
+	if response.Error == nil {
+		return s.runtimeAPI.respond(ctx, request.RequestId, response.Payload)
+	}
+
+	return s.runtimeAPI.respondError(ctx, request.RequestId, response.Error)
+}
+
+// invoke calls the function of the server. A panic reported by the function,
+// either by recoverInvoke or by the lambda library when panic recovery is
+// disabled, is marked by ShouldExit. The marker is replaced according to the
+// panic policy so that the go1.x runtime keeps the process alive when the
+// policy is PanicPolicyContinue.
+func (s *Server) invoke(request *messages.InvokeRequest, response *messages.InvokeResponse) error {
+	if err := s.function.Invoke(request, response); err != nil {
+		return err
+	}
+
+	if response.Error != nil && response.Error.ShouldExit {
+		response.Error.ShouldExit = s.options.panicPolicy == PanicPolicyExit
+		s.handlePanic("lambda handler panicked (%s)", response.Error.Message)
+	}
+
+	return nil
+}
+
+// handlePanic stops the server if the panic policy is PanicPolicyExit, and
+// the Run method of the server returns an error with the given message once
+// the in-flight invocations complete. Otherwise, the panic is logged.
+func (s *Server) handlePanic(format string, args ...interface{}) {
+	err := fmt.Errorf(format, args...)
+	if s.options.panicPolicy != PanicPolicyExit {
+		s.Logger.Error("Continuing after panic (%s)", err.Error())
+		return
+	}
+
+	s.exitMutex.Lock()
+	if s.exitErr == nil {
+		s.exitErr = err
+	}
+	s.exitMutex.Unlock()
+
+	s.close()
+}
+
+func (s *Server) exitError() error {
+	s.exitMutex.Lock()
+	defer s.exitMutex.Unlock()
+
+	return s.exitErr
+}
+
+func (f *rpcFunction) Ping(request *messages.PingRequest, response *messages.PingResponse) error {
+	return f.server.function.Ping(request, response)
+}
+
+func (f *rpcFunction) Invoke(request *messages.InvokeRequest, response *messages.InvokeResponse) error {
+	return f.server.invoke(request, response)
+}
+
+// Addr returns the address on which the server listens for RPC commands. The
+// return value is nil before Init or when the server polls the Runtime API.
+func (s *Server) Addr() net.Addr {
+	if s.listener == nil {
+		return nil
+	}
+
+	return s.listener.Addr()
+}
+
 func (s *Server) Stop(ctx context.Context) error {
 	s.close()
 	return nil
@@ -123,12 +360,15 @@ func (s *Server) Stop(ctx context.Context) error {
 
 func (s *Server) close() {
 	s.once.Do(func() {
-		if s.listener == nil {
-			return
+		if s.listener != nil {
+			s.Logger.Info("Closing lambda listener")
+			s.listener.Close()
 		}
 
-		s.Logger.Info("Closing lambda listener")
-		s.listener.Close()
+		if s.cancelPoll != nil {
+			s.Logger.Info("Closing lambda runtime API poller")
+			s.cancelPoll()
+		}
 	})
 }
 
@@ -103,6 +103,79 @@ func TestServerInitError(t *testing.T) {
 	require.EqualError(t, err, "oops")
 }
 
+func TestServerOptionsFromConfig(t *testing.T) {
+	ctx := context.Background()
+	ctx = config.WithConfig(ctx, nacelle.NewConfig(nacelle.NewTestEnvSourcer(map[string]string{
+		"_lambda_server_port":      "0",
+		"lambda_server_host":       "127.0.0.1",
+		"lambda_health_token_name": "from-config",
+	})))
+
+	server := makeLambdaServer(testHandler, WithListenerHost("localhost"), WithHealthTokenName("from-option"))
+	err := server.Init(ctx)
+	require.Nil(t, err)
+	defer server.Stop(ctx)
+
+	require.Equal(t, "127.0.0.1", server.listener.Addr().(*net.TCPAddr).IP.String())
+	require.Equal(t, "from-config", server.healthToken.String())
+
+	_, ok := server.Health.Get(server.healthToken)
+	require.True(t, ok)
+}
+
+func TestServerOptionsDefaults(t *testing.T) {
+	ctx := context.Background()
+	ctx = config.WithConfig(ctx, testConfig)
+
+	server := makeLambdaServer(testHandler, WithHealthTokenName("from-option"))
+	err := server.Init(ctx)
+	require.Nil(t, err)
+	defer server.Stop(ctx)
+
+	require.Equal(t, "from-option", server.healthToken.String())
+	require.Equal(t, PanicPolicyExit, server.options.panicPolicy)
+
+	server = makeLambdaServer(testHandler)
+	err = server.Init(ctx)
+	require.Nil(t, err)
+	defer server.Stop(ctx)
+
+	require.Equal(t, defaultHealthTokenName, server.healthToken.String())
+}
+
+func TestServerUnknownPanicPolicy(t *testing.T) {
+	ctx := context.Background()
+	ctx = config.WithConfig(ctx, nacelle.NewConfig(nacelle.NewTestEnvSourcer(map[string]string{
+		"_lambda_server_port": "0",
+		"lambda_panic_policy": "shrug",
+	})))
+
+	err := makeLambdaServer(testHandler).Init(ctx)
+	require.NotNil(t, err)
+	require.Contains(t, err.Error(), `unknown lambda_panic_policy "shrug"`)
+}
+
+func TestServerLoggerFieldNames(t *testing.T) {
+	ctx := context.Background()
+	ctx = config.WithConfig(ctx, testConfig)
+
+	logger := &fieldRecordingLogger{Logger: nacelle.NewNilLogger()}
+	handler := &loggerLambdaHandler{}
+
+	server := NewServer(handler, WithLoggerFieldNames(map[string]string{"requestId": "request_id"}))
+	server.Logger = logger
+	server.Services = nacelle.NewServiceContainer()
+	server.Health = nacelle.NewHealth()
+	require.Nil(t, server.Services.Set("logger", logger))
+
+	err := server.Init(ctx)
+	require.Nil(t, err)
+	defer server.Stop(ctx)
+
+	handler.Logger.WithFields(nacelle.LogFields{"requestId": "bonk", "other": "quux"})
+	require.Equal(t, []nacelle.LogFields{{"request_id": "bonk", "other": "quux"}}, logger.fields)
+}
+
 //
 // Helpers
 
@@ -114,8 +187,8 @@ func (h *wrappedHandler) Init(ctx context.Context) error {
 	return nil
 }
 
-func makeLambdaServer(handler lambda.Handler) *Server {
-	server := NewServer(&wrappedHandler{Handler: handler})
+func makeLambdaServer(handler lambda.Handler, configs ...ConfigFunc) *Server {
+	server := NewServer(&wrappedHandler{Handler: handler}, configs...)
 	server.Logger = nacelle.NewNilLogger()
 	server.Services = nacelle.NewServiceContainer()
 	server.Health = nacelle.NewHealth()
@@ -126,6 +199,28 @@ func getDynamicPort(listener net.Listener) int {
 	return listener.Addr().(*net.TCPAddr).Port
 }
 
+type fieldRecordingLogger struct {
+	nacelle.Logger
+	fields []nacelle.LogFields
+}
+
+func (l *fieldRecordingLogger) WithFields(fields nacelle.LogFields) nacelle.Logger {
+	l.fields = append(l.fields, fields)
+	return l
+}
+
+type loggerLambdaHandler struct {
+	Logger nacelle.Logger `service:"logger"`
+}
+
+func (h *loggerLambdaHandler) Init(context.Context) error {
+	return nil
+}
+
+func (h *loggerLambdaHandler) Invoke(context.Context, []byte) ([]byte, error) {
+	return nil, nil
+}
+
 //
 // Bad Injection
 
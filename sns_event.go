@@ -0,0 +1,86 @@
+package lambdabase
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/go-nacelle/nacelle/v2"
+)
+
+type (
+	SNSEventHandler interface {
+		Handle(ctx context.Context, batch []events.SNSEventRecord, logger nacelle.Logger) error
+	}
+
+	snsEventHandlerInitializer interface {
+		nacelle.Initializer
+		SNSEventHandler
+	}
+
+	snsEventHandler struct {
+		Logger          nacelle.Logger            `service:"logger"`
+		Services        *nacelle.ServiceContainer `service:"services"`
+		handler         SNSEventHandler
+		unwrapEnvelopes bool
+	}
+)
+
+func NewSNSEventServer(handler SNSEventHandler, configs ...ConfigFunc) *Server {
+	options := getOptions(configs)
+
+	return NewServer(&snsEventHandler{
+		handler:         handler,
+		unwrapEnvelopes: options.unwrapEnvelopes,
+	}, configs...)
+}
+
+func (h *snsEventHandler) Init(ctx context.Context) error {
+	return doInit(ctx, h.Services, h.handler)
+}
+
+func (h *snsEventHandler) Invoke(ctx context.Context, payload []byte) ([]byte, error) {
+	records, err := h.unmarshalRecords(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	logger := h.Logger.WithFields(map[string]interface{}{
+		"requestId": GetRequestID(ctx),
+	})
+
+	logger.Debug("Received %d SNS records", len(records))
+
+	if err := h.handler.Handle(ctx, records, logger); err != nil {
+		return nil, fmt.Errorf("failed to process SNS event (%w)", err)
+	}
+
+	logger.Debug("SNS event handled successfully")
+	return nil, nil
+}
+
+func (h *snsEventHandler) unmarshalRecords(payload []byte) ([]events.SNSEventRecord, error) {
+	if h.unwrapEnvelopes {
+		eventSource, _, err := probeEnvelope(payload)
+		if err != nil {
+			return nil, fmt.Errorf("failed to unmarshal event (%w)", err)
+		}
+
+		if eventSource == eventSourceSQS {
+			event := &events.SQSEvent{}
+			if err := json.Unmarshal(payload, &event); err != nil {
+				return nil, fmt.Errorf("failed to unmarshal event (%w)", err)
+			}
+
+			return unwrapSQSSNSRecords(event.Records)
+		}
+	}
+
+	event := &events.SNSEvent{}
+	if err := json.Unmarshal(payload, &event); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal event (%w)", err)
+	}
+
+	return event.Records, nil
+}
@@ -0,0 +1,583 @@
+// Code generated by go-mockgen 1.3.5; DO NOT EDIT.
+
+package lambdabase
+
+import (
+	"context"
+	"sync"
+
+	events "github.com/aws/aws-lambda-go/events"
+	v2 "github.com/go-nacelle/log/v2"
+)
+
+// MockSnsEventHandlerInitializer is a mock implementation of the
+// snsEventHandlerInitializer interface (from the package
+// github.com/go-nacelle/lambdabase) used for unit testing.
+type MockSnsEventHandlerInitializer struct {
+	// HandleFunc is an instance of a mock function object controlling the
+	// behavior of the method Handle.
+	HandleFunc *SnsEventHandlerInitializerHandleFunc
+	// InitFunc is an instance of a mock function object controlling the
+	// behavior of the method Init.
+	InitFunc *SnsEventHandlerInitializerInitFunc
+}
+
+// NewMockSnsEventHandlerInitializer creates a new mock of the
+// snsEventHandlerInitializer interface. All methods return zero values for
+// all results, unless overwritten.
+func NewMockSnsEventHandlerInitializer() *MockSnsEventHandlerInitializer {
+	return &MockSnsEventHandlerInitializer{
+		HandleFunc: &SnsEventHandlerInitializerHandleFunc{
+			defaultHook: func(context.Context, []events.SNSEventRecord, v2.Logger) (r0 error) {
+				return
+			},
+		},
+		InitFunc: &SnsEventHandlerInitializerInitFunc{
+			defaultHook: func(context.Context) (r0 error) {
+				return
+			},
+		},
+	}
+}
+
+// NewStrictMockSnsEventHandlerInitializer creates a new mock of the
+// snsEventHandlerInitializer interface. All methods panic on invocation,
+// unless overwritten.
+func NewStrictMockSnsEventHandlerInitializer() *MockSnsEventHandlerInitializer {
+	return &MockSnsEventHandlerInitializer{
+		HandleFunc: &SnsEventHandlerInitializerHandleFunc{
+			defaultHook: func(context.Context, []events.SNSEventRecord, v2.Logger) error {
+				panic("unexpected invocation of MockSnsEventHandlerInitializer.Handle")
+			},
+		},
+		InitFunc: &SnsEventHandlerInitializerInitFunc{
+			defaultHook: func(context.Context) error {
+				panic("unexpected invocation of MockSnsEventHandlerInitializer.Init")
+			},
+		},
+	}
+}
+
+// surrogateMockSnsEventHandlerInitializer is a copy of the
+// snsEventHandlerInitializer interface (from the package
+// github.com/go-nacelle/lambdabase). It is redefined here as it is
+// unexported in the source package.
+type surrogateMockSnsEventHandlerInitializer interface {
+	Handle(context.Context, []events.SNSEventRecord, v2.Logger) error
+	Init(context.Context) error
+}
+
+// NewMockSnsEventHandlerInitializerFrom creates a new mock of the
+// MockSnsEventHandlerInitializer interface. All methods delegate to the
+// given implementation, unless overwritten.
+func NewMockSnsEventHandlerInitializerFrom(i surrogateMockSnsEventHandlerInitializer) *MockSnsEventHandlerInitializer {
+	return &MockSnsEventHandlerInitializer{
+		HandleFunc: &SnsEventHandlerInitializerHandleFunc{
+			defaultHook: i.Handle,
+		},
+		InitFunc: &SnsEventHandlerInitializerInitFunc{
+			defaultHook: i.Init,
+		},
+	}
+}
+
+// SnsEventHandlerInitializerHandleFunc describes the behavior when the
+// Handle method of the parent MockSnsEventHandlerInitializer instance is
+// invoked.
+type SnsEventHandlerInitializerHandleFunc struct {
+	defaultHook func(context.Context, []events.SNSEventRecord, v2.Logger) error
+	hooks       []func(context.Context, []events.SNSEventRecord, v2.Logger) error
+	history     []SnsEventHandlerInitializerHandleFuncCall
+	mutex       sync.Mutex
+}
+
+// Handle delegates to the next hook function in the queue and stores the
+// parameter and result values of this invocation.
+func (m *MockSnsEventHandlerInitializer) Handle(v0 context.Context, v1 []events.SNSEventRecord, v2 v2.Logger) error {
+	r0 := m.HandleFunc.nextHook()(v0, v1, v2)
+	m.HandleFunc.appendCall(SnsEventHandlerInitializerHandleFuncCall{v0, v1, v2, r0})
+	return r0
+}
+
+// SetDefaultHook sets function that is called when the Handle method of the
+// parent MockSnsEventHandlerInitializer instance is invoked and the hook
+// queue is empty.
+func (f *SnsEventHandlerInitializerHandleFunc) SetDefaultHook(hook func(context.Context, []events.SNSEventRecord, v2.Logger) error) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// Handle method of the parent MockSnsEventHandlerInitializer instance
+// invokes the hook at the front of the queue and discards it. After the
+// queue is empty, the default hook function is invoked for any future
+// action.
+func (f *SnsEventHandlerInitializerHandleFunc) PushHook(hook func(context.Context, []events.SNSEventRecord, v2.Logger) error) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultHook with a function that returns the
+// given values.
+func (f *SnsEventHandlerInitializerHandleFunc) SetDefaultReturn(r0 error) {
+	f.SetDefaultHook(func(context.Context, []events.SNSEventRecord, v2.Logger) error {
+		return r0
+	})
+}
+
+// PushReturn calls PushHook with a function that returns the given values.
+func (f *SnsEventHandlerInitializerHandleFunc) PushReturn(r0 error) {
+	f.PushHook(func(context.Context, []events.SNSEventRecord, v2.Logger) error {
+		return r0
+	})
+}
+
+func (f *SnsEventHandlerInitializerHandleFunc) nextHook() func(context.Context, []events.SNSEventRecord, v2.Logger) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *SnsEventHandlerInitializerHandleFunc) appendCall(r0 SnsEventHandlerInitializerHandleFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of SnsEventHandlerInitializerHandleFuncCall
+// objects describing the invocations of this function.
+func (f *SnsEventHandlerInitializerHandleFunc) History() []SnsEventHandlerInitializerHandleFuncCall {
+	f.mutex.Lock()
+	history := make([]SnsEventHandlerInitializerHandleFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// SnsEventHandlerInitializerHandleFuncCall is an object that describes an
+// invocation of method Handle on an instance of
+// MockSnsEventHandlerInitializer.
+type SnsEventHandlerInitializerHandleFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 []events.SNSEventRecord
+	// Arg2 is the value of the 3rd argument passed to this method
+	// invocation.
+	Arg2 v2.Logger
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c SnsEventHandlerInitializerHandleFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1, c.Arg2}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c SnsEventHandlerInitializerHandleFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0}
+}
+
+// SnsEventHandlerInitializerInitFunc describes the behavior when the Init
+// method of the parent MockSnsEventHandlerInitializer instance is invoked.
+type SnsEventHandlerInitializerInitFunc struct {
+	defaultHook func(context.Context) error
+	hooks       []func(context.Context) error
+	history     []SnsEventHandlerInitializerInitFuncCall
+	mutex       sync.Mutex
+}
+
+// Init delegates to the next hook function in the queue and stores the
+// parameter and result values of this invocation.
+func (m *MockSnsEventHandlerInitializer) Init(v0 context.Context) error {
+	r0 := m.InitFunc.nextHook()(v0)
+	m.InitFunc.appendCall(SnsEventHandlerInitializerInitFuncCall{v0, r0})
+	return r0
+}
+
+// SetDefaultHook sets function that is called when the Init method of the
+// parent MockSnsEventHandlerInitializer instance is invoked and the hook
+// queue is empty.
+func (f *SnsEventHandlerInitializerInitFunc) SetDefaultHook(hook func(context.Context) error) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// Init method of the parent MockSnsEventHandlerInitializer instance invokes
+// the hook at the front of the queue and discards it. After the queue is
+// empty, the default hook function is invoked for any future action.
+func (f *SnsEventHandlerInitializerInitFunc) PushHook(hook func(context.Context) error) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultHook with a function that returns the
+// given values.
+func (f *SnsEventHandlerInitializerInitFunc) SetDefaultReturn(r0 error) {
+	f.SetDefaultHook(func(context.Context) error {
+		return r0
+	})
+}
+
+// PushReturn calls PushHook with a function that returns the given values.
+func (f *SnsEventHandlerInitializerInitFunc) PushReturn(r0 error) {
+	f.PushHook(func(context.Context) error {
+		return r0
+	})
+}
+
+func (f *SnsEventHandlerInitializerInitFunc) nextHook() func(context.Context) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *SnsEventHandlerInitializerInitFunc) appendCall(r0 SnsEventHandlerInitializerInitFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of SnsEventHandlerInitializerInitFuncCall
+// objects describing the invocations of this function.
+func (f *SnsEventHandlerInitializerInitFunc) History() []SnsEventHandlerInitializerInitFuncCall {
+	f.mutex.Lock()
+	history := make([]SnsEventHandlerInitializerInitFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// SnsEventHandlerInitializerInitFuncCall is an object that describes an
+// invocation of method Init on an instance of
+// MockSnsEventHandlerInitializer.
+type SnsEventHandlerInitializerInitFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c SnsEventHandlerInitializerInitFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c SnsEventHandlerInitializerInitFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0}
+}
+
+// MockSnsRecordHandlerInitializer is a mock implementation of the
+// snsRecordHandlerInitializer interface (from the package
+// github.com/go-nacelle/lambdabase) used for unit testing.
+type MockSnsRecordHandlerInitializer struct {
+	// HandleFunc is an instance of a mock function object controlling the
+	// behavior of the method Handle.
+	HandleFunc *SnsRecordHandlerInitializerHandleFunc
+	// InitFunc is an instance of a mock function object controlling the
+	// behavior of the method Init.
+	InitFunc *SnsRecordHandlerInitializerInitFunc
+}
+
+// NewMockSnsRecordHandlerInitializer creates a new mock of the
+// snsRecordHandlerInitializer interface. All methods return zero values
+// for all results, unless overwritten.
+func NewMockSnsRecordHandlerInitializer() *MockSnsRecordHandlerInitializer {
+	return &MockSnsRecordHandlerInitializer{
+		HandleFunc: &SnsRecordHandlerInitializerHandleFunc{
+			defaultHook: func(context.Context, events.SNSEventRecord, v2.Logger) (r0 error) {
+				return
+			},
+		},
+		InitFunc: &SnsRecordHandlerInitializerInitFunc{
+			defaultHook: func(context.Context) (r0 error) {
+				return
+			},
+		},
+	}
+}
+
+// NewStrictMockSnsRecordHandlerInitializer creates a new mock of the
+// snsRecordHandlerInitializer interface. All methods panic on invocation,
+// unless overwritten.
+func NewStrictMockSnsRecordHandlerInitializer() *MockSnsRecordHandlerInitializer {
+	return &MockSnsRecordHandlerInitializer{
+		HandleFunc: &SnsRecordHandlerInitializerHandleFunc{
+			defaultHook: func(context.Context, events.SNSEventRecord, v2.Logger) error {
+				panic("unexpected invocation of MockSnsRecordHandlerInitializer.Handle")
+			},
+		},
+		InitFunc: &SnsRecordHandlerInitializerInitFunc{
+			defaultHook: func(context.Context) error {
+				panic("unexpected invocation of MockSnsRecordHandlerInitializer.Init")
+			},
+		},
+	}
+}
+
+// surrogateMockSnsRecordHandlerInitializer is a copy of the
+// snsRecordHandlerInitializer interface (from the package
+// github.com/go-nacelle/lambdabase). It is redefined here as it is
+// unexported in the source package.
+type surrogateMockSnsRecordHandlerInitializer interface {
+	Handle(context.Context, events.SNSEventRecord, v2.Logger) error
+	Init(context.Context) error
+}
+
+// NewMockSnsRecordHandlerInitializerFrom creates a new mock of the
+// MockSnsRecordHandlerInitializer interface. All methods delegate to the
+// given implementation, unless overwritten.
+func NewMockSnsRecordHandlerInitializerFrom(i surrogateMockSnsRecordHandlerInitializer) *MockSnsRecordHandlerInitializer {
+	return &MockSnsRecordHandlerInitializer{
+		HandleFunc: &SnsRecordHandlerInitializerHandleFunc{
+			defaultHook: i.Handle,
+		},
+		InitFunc: &SnsRecordHandlerInitializerInitFunc{
+			defaultHook: i.Init,
+		},
+	}
+}
+
+// SnsRecordHandlerInitializerHandleFunc describes the behavior when the
+// Handle method of the parent MockSnsRecordHandlerInitializer instance is
+// invoked.
+type SnsRecordHandlerInitializerHandleFunc struct {
+	defaultHook func(context.Context, events.SNSEventRecord, v2.Logger) error
+	hooks       []func(context.Context, events.SNSEventRecord, v2.Logger) error
+	history     []SnsRecordHandlerInitializerHandleFuncCall
+	mutex       sync.Mutex
+}
+
+// Handle delegates to the next hook function in the queue and stores the
+// parameter and result values of this invocation.
+func (m *MockSnsRecordHandlerInitializer) Handle(v0 context.Context, v1 events.SNSEventRecord, v2 v2.Logger) error {
+	r0 := m.HandleFunc.nextHook()(v0, v1, v2)
+	m.HandleFunc.appendCall(SnsRecordHandlerInitializerHandleFuncCall{v0, v1, v2, r0})
+	return r0
+}
+
+// SetDefaultHook sets function that is called when the Handle method of the
+// parent MockSnsRecordHandlerInitializer instance is invoked and the hook
+// queue is empty.
+func (f *SnsRecordHandlerInitializerHandleFunc) SetDefaultHook(hook func(context.Context, events.SNSEventRecord, v2.Logger) error) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// Handle method of the parent MockSnsRecordHandlerInitializer instance
+// invokes the hook at the front of the queue and discards it. After the
+// queue is empty, the default hook function is invoked for any future
+// action.
+func (f *SnsRecordHandlerInitializerHandleFunc) PushHook(hook func(context.Context, events.SNSEventRecord, v2.Logger) error) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultHook with a function that returns the
+// given values.
+func (f *SnsRecordHandlerInitializerHandleFunc) SetDefaultReturn(r0 error) {
+	f.SetDefaultHook(func(context.Context, events.SNSEventRecord, v2.Logger) error {
+		return r0
+	})
+}
+
+// PushReturn calls PushHook with a function that returns the given values.
+func (f *SnsRecordHandlerInitializerHandleFunc) PushReturn(r0 error) {
+	f.PushHook(func(context.Context, events.SNSEventRecord, v2.Logger) error {
+		return r0
+	})
+}
+
+func (f *SnsRecordHandlerInitializerHandleFunc) nextHook() func(context.Context, events.SNSEventRecord, v2.Logger) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *SnsRecordHandlerInitializerHandleFunc) appendCall(r0 SnsRecordHandlerInitializerHandleFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of SnsRecordHandlerInitializerHandleFuncCall
+// objects describing the invocations of this function.
+func (f *SnsRecordHandlerInitializerHandleFunc) History() []SnsRecordHandlerInitializerHandleFuncCall {
+	f.mutex.Lock()
+	history := make([]SnsRecordHandlerInitializerHandleFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// SnsRecordHandlerInitializerHandleFuncCall is an object that describes an
+// invocation of method Handle on an instance of
+// MockSnsRecordHandlerInitializer.
+type SnsRecordHandlerInitializerHandleFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 events.SNSEventRecord
+	// Arg2 is the value of the 3rd argument passed to this method
+	// invocation.
+	Arg2 v2.Logger
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c SnsRecordHandlerInitializerHandleFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1, c.Arg2}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c SnsRecordHandlerInitializerHandleFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0}
+}
+
+// SnsRecordHandlerInitializerInitFunc describes the behavior when the Init
+// method of the parent MockSnsRecordHandlerInitializer instance is
+// invoked.
+type SnsRecordHandlerInitializerInitFunc struct {
+	defaultHook func(context.Context) error
+	hooks       []func(context.Context) error
+	history     []SnsRecordHandlerInitializerInitFuncCall
+	mutex       sync.Mutex
+}
+
+// Init delegates to the next hook function in the queue and stores the
+// parameter and result values of this invocation.
+func (m *MockSnsRecordHandlerInitializer) Init(v0 context.Context) error {
+	r0 := m.InitFunc.nextHook()(v0)
+	m.InitFunc.appendCall(SnsRecordHandlerInitializerInitFuncCall{v0, r0})
+	return r0
+}
+
+// SetDefaultHook sets function that is called when the Init method of the
+// parent MockSnsRecordHandlerInitializer instance is invoked and the hook
+// queue is empty.
+func (f *SnsRecordHandlerInitializerInitFunc) SetDefaultHook(hook func(context.Context) error) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// Init method of the parent MockSnsRecordHandlerInitializer instance
+// invokes the hook at the front of the queue and discards it. After the
+// queue is empty, the default hook function is invoked for any future
+// action.
+func (f *SnsRecordHandlerInitializerInitFunc) PushHook(hook func(context.Context) error) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultHook with a function that returns the
+// given values.
+func (f *SnsRecordHandlerInitializerInitFunc) SetDefaultReturn(r0 error) {
+	f.SetDefaultHook(func(context.Context) error {
+		return r0
+	})
+}
+
+// PushReturn calls PushHook with a function that returns the given values.
+func (f *SnsRecordHandlerInitializerInitFunc) PushReturn(r0 error) {
+	f.PushHook(func(context.Context) error {
+		return r0
+	})
+}
+
+func (f *SnsRecordHandlerInitializerInitFunc) nextHook() func(context.Context) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *SnsRecordHandlerInitializerInitFunc) appendCall(r0 SnsRecordHandlerInitializerInitFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of SnsRecordHandlerInitializerInitFuncCall
+// objects describing the invocations of this function.
+func (f *SnsRecordHandlerInitializerInitFunc) History() []SnsRecordHandlerInitializerInitFuncCall {
+	f.mutex.Lock()
+	history := make([]SnsRecordHandlerInitializerInitFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// SnsRecordHandlerInitializerInitFuncCall is an object that describes an
+// invocation of method Init on an instance of
+// MockSnsRecordHandlerInitializer.
+type SnsRecordHandlerInitializerInitFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c SnsRecordHandlerInitializerInitFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c SnsRecordHandlerInitializerInitFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0}
+}
@@ -0,0 +1,57 @@
+package lambdabase
+
+import (
+	"context"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/go-nacelle/nacelle/v2"
+)
+
+type (
+	SNSRecordHandler interface {
+		Handle(ctx context.Context, record events.SNSEventRecord, logger nacelle.Logger) error
+	}
+
+	snsRecordHandlerInitializer interface {
+		nacelle.Initializer
+		SNSRecordHandler
+	}
+
+	snsRecordHandler struct {
+		Services         *nacelle.ServiceContainer `service:"services"`
+		handler          SNSRecordHandler
+		recordMiddleware []RecordMiddleware
+	}
+)
+
+func NewSNSRecordServer(handler SNSRecordHandler, configs ...ConfigFunc) *Server {
+	options := getOptions(configs)
+
+	return NewSNSEventServer(&snsRecordHandler{
+		handler:          handler,
+		recordMiddleware: options.recordMiddleware,
+	}, configs...)
+}
+
+func (s *snsRecordHandler) Init(ctx context.Context) error {
+	return doInit(ctx, s.Services, s.handler)
+}
+
+func (h *snsRecordHandler) Handle(ctx context.Context, records []events.SNSEventRecord, logger nacelle.Logger) error {
+	for _, record := range records {
+		recordLogger := logger.WithFields(map[string]interface{}{
+			"messageId": record.SNS.MessageID,
+			"topicArn":  record.SNS.TopicArn,
+			"subject":   record.SNS.Subject,
+		})
+
+		recordLogger.Debug("Handling record")
+
+		if err := invokeRecordHandler(ctx, h.recordMiddleware, record, recordLogger, h.handler.Handle); err != nil {
+			return newRecordError("SNS", "record", record.SNS.MessageID, err)
+		}
+	}
+
+	logger.Debug("SNS record handled successfully")
+	return nil
+}
@@ -0,0 +1,260 @@
+package lambdabase
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+	mockassert "github.com/derision-test/go-mockgen/testutil/assert"
+	"github.com/go-nacelle/config/v3"
+	"github.com/go-nacelle/nacelle/v2"
+	"github.com/stretchr/testify/require"
+)
+
+var testSNSPayload = `{
+	"Records": [
+		{
+			"EventSource": "aws:sns",
+			"EventVersion": "1.0",
+			"EventSubscriptionArn": "arn:aws:sns:us-east-1:123456789012:topic:sub",
+			"Sns": {
+				"Type": "Notification",
+				"MessageId": "n1",
+				"TopicArn": "arn:aws:sns:us-east-1:123456789012:topic",
+				"Subject": "greeting",
+				"Message": "foo"
+			}
+		}
+	]
+}`
+
+var testSNSOverSQSPayload = `{
+	"Records": [
+		{
+			"messageId": "m1",
+			"eventSource": "aws:sqs",
+			"body": "{\"Type\": \"Notification\", \"MessageId\": \"n1\", \"TopicArn\": \"arn:aws:sns:us-east-1:123456789012:topic\", \"Subject\": \"greeting\", \"Message\": \"foo\"}"
+		}
+	]
+}`
+
+var testSNSRecords = []events.SNSEventRecord{
+	{
+		EventSource:          "aws:sns",
+		EventVersion:         "1.0",
+		EventSubscriptionArn: "arn:aws:sns:us-east-1:123456789012:topic:sub",
+		SNS: events.SNSEntity{
+			Type:      "Notification",
+			MessageID: "n1",
+			TopicArn:  "arn:aws:sns:us-east-1:123456789012:topic",
+			Subject:   "greeting",
+			Message:   "foo",
+		},
+	},
+}
+
+func TestSNSEventInit(t *testing.T) {
+	handler := NewMockSnsEventHandlerInitializer()
+	outer := &snsEventHandler{
+		handler:  handler,
+		Logger:   nacelle.NewNilLogger(),
+		Services: nacelle.NewServiceContainer(),
+	}
+
+	ctx := context.Background()
+
+	cfg := nacelle.NewConfig(nacelle.NewTestEnvSourcer(nil))
+	ctx = config.WithConfig(ctx, cfg)
+
+	err := outer.Init(ctx)
+	require.Nil(t, err)
+	mockassert.CalledOnceWith(t, handler.InitFunc, mockassert.Values(ctx))
+}
+
+func TestSNSEventBadInjection(t *testing.T) {
+	handler := &badInjectionSNSEventHandler{}
+	outer := &snsEventHandler{
+		handler:  handler,
+		Logger:   nacelle.NewNilLogger(),
+		Services: makeBadContainer(),
+	}
+
+	ctx := context.Background()
+
+	cfg := nacelle.NewConfig(nacelle.NewTestEnvSourcer(nil))
+	ctx = config.WithConfig(ctx, cfg)
+
+	err := outer.Init(ctx)
+	require.NotNil(t, err)
+	require.Contains(t, err.Error(), "ServiceA")
+}
+
+func TestSNSEventInitError(t *testing.T) {
+	handler := NewMockSnsEventHandlerInitializer()
+	handler.InitFunc.SetDefaultReturn(fmt.Errorf("oops"))
+	outer := &snsEventHandler{
+		handler:  handler,
+		Logger:   nacelle.NewNilLogger(),
+		Services: makeBadContainer(),
+	}
+
+	ctx := context.Background()
+
+	cfg := nacelle.NewConfig(nacelle.NewTestEnvSourcer(nil))
+	ctx = config.WithConfig(ctx, cfg)
+
+	err := outer.Init(ctx)
+	require.EqualError(t, err, "oops")
+}
+
+func TestSNSRecordInit(t *testing.T) {
+	handler := NewMockSnsRecordHandlerInitializer()
+	outer := &snsRecordHandler{
+		handler:  handler,
+		Services: nacelle.NewServiceContainer(),
+	}
+
+	ctx := context.Background()
+
+	cfg := nacelle.NewConfig(nacelle.NewTestEnvSourcer(nil))
+	ctx = config.WithConfig(ctx, cfg)
+
+	err := outer.Init(ctx)
+	require.Nil(t, err)
+	mockassert.CalledOnceWith(t, handler.InitFunc, mockassert.Values(ctx))
+}
+
+func TestSNSRecordBadInjection(t *testing.T) {
+	handler := &badInjectionSNSRecordHandler{}
+	outer := &snsRecordHandler{
+		handler:  handler,
+		Services: makeBadContainer(),
+	}
+
+	ctx := context.Background()
+
+	cfg := nacelle.NewConfig(nacelle.NewTestEnvSourcer(nil))
+	ctx = config.WithConfig(ctx, cfg)
+
+	err := outer.Init(ctx)
+	require.NotNil(t, err)
+	require.Contains(t, err.Error(), "ServiceA")
+}
+
+func TestSNSRecordInitError(t *testing.T) {
+	handler := NewMockSnsRecordHandlerInitializer()
+	handler.InitFunc.SetDefaultReturn(fmt.Errorf("oops"))
+	outer := &snsRecordHandler{
+		handler:  handler,
+		Services: nacelle.NewServiceContainer(),
+	}
+
+	ctx := context.Background()
+
+	cfg := nacelle.NewConfig(nacelle.NewTestEnvSourcer(nil))
+	ctx = config.WithConfig(ctx, cfg)
+
+	err := outer.Init(ctx)
+	require.EqualError(t, err, "oops")
+}
+
+func TestSNSEventInvoke(t *testing.T) {
+	handler := NewMockSnsEventHandlerInitializer()
+	outer := &snsEventHandler{
+		handler: handler,
+		Logger:  nacelle.NewNilLogger(),
+	}
+
+	response, err := outer.Invoke(context.Background(), []byte(testSNSPayload))
+	require.Nil(t, err)
+	require.Nil(t, response)
+	mockassert.CalledOnceWith(t, handler.HandleFunc, mockassert.Values(mockassert.Skip, testSNSRecords))
+}
+
+func TestSNSEventInvokeError(t *testing.T) {
+	handler := NewMockSnsEventHandlerInitializer()
+	outer := &snsEventHandler{
+		handler: handler,
+		Logger:  nacelle.NewNilLogger(),
+	}
+
+	handler.HandleFunc.SetDefaultReturn(fmt.Errorf("oops"))
+	_, err := outer.Invoke(context.Background(), []byte(testSNSPayload))
+	require.EqualError(t, err, "failed to process SNS event (oops)")
+}
+
+func TestSNSEventInvokeUnwrapsSQS(t *testing.T) {
+	handler := NewMockSnsEventHandlerInitializer()
+	outer := &snsEventHandler{
+		handler:         handler,
+		Logger:          nacelle.NewNilLogger(),
+		unwrapEnvelopes: true,
+	}
+
+	_, err := outer.Invoke(context.Background(), []byte(testSNSOverSQSPayload))
+	require.Nil(t, err)
+	mockassert.CalledOnce(t, handler.HandleFunc)
+
+	records := handler.HandleFunc.History()[0].Arg1
+	require.Len(t, records, 1)
+	require.Equal(t, testSNSRecords[0].SNS, records[0].SNS)
+
+	// Direct SNS deliveries are still accepted when unwrapping is enabled
+	_, err = outer.Invoke(context.Background(), []byte(testSNSPayload))
+	require.Nil(t, err)
+	require.Equal(t, testSNSRecords, handler.HandleFunc.History()[1].Arg1)
+}
+
+func TestSNSEventInvokeUnwrapDisabled(t *testing.T) {
+	handler := NewMockSnsEventHandlerInitializer()
+	outer := &snsEventHandler{
+		handler: handler,
+		Logger:  nacelle.NewNilLogger(),
+	}
+
+	_, err := outer.Invoke(context.Background(), []byte(testSNSOverSQSPayload))
+	require.Nil(t, err)
+	require.Equal(t, events.SNSEntity{}, handler.HandleFunc.History()[0].Arg1[0].SNS)
+}
+
+func TestSNSRecordHandle(t *testing.T) {
+	handler := NewMockSnsRecordHandlerInitializer()
+	outer := &snsRecordHandler{handler: handler}
+
+	err := outer.Handle(context.Background(), testSNSRecords, nacelle.NewNilLogger())
+	require.Nil(t, err)
+
+	for _, record := range testSNSRecords {
+		mockassert.CalledOnceWith(t, handler.HandleFunc, mockassert.Values(mockassert.Skip, record))
+	}
+}
+
+func TestSNSRecordHandleError(t *testing.T) {
+	handler := NewMockSnsRecordHandlerInitializer()
+	handler.HandleFunc.PushReturn(fmt.Errorf("oops"))
+	outer := &snsRecordHandler{handler: handler}
+
+	err := outer.Handle(context.Background(), testSNSRecords, nacelle.NewNilLogger())
+	require.EqualError(t, err, "failed to process SNS record n1 (oops)")
+	mockassert.CalledN(t, handler.HandleFunc, 1)
+}
+
+//
+// Bad Injection
+
+type badInjectionSNSEventHandler struct {
+	ServiceA *A `service:"A"`
+}
+
+func (i *badInjectionSNSEventHandler) Handle(ctx context.Context, records []events.SNSEventRecord, logger nacelle.Logger) error {
+	return nil
+}
+
+type badInjectionSNSRecordHandler struct {
+	ServiceA *A `service:"A"`
+}
+
+func (i *badInjectionSNSRecordHandler) Handle(ctx context.Context, record events.SNSEventRecord, logger nacelle.Logger) error {
+	return nil
+}
@@ -14,6 +14,10 @@ type (
 		Handle(ctx context.Context, batch []events.SQSMessage, logger nacelle.Logger) error
 	}
 
+	SQSEventResponseHandler interface {
+		HandleWithResponse(ctx context.Context, batch []events.SQSMessage, logger nacelle.Logger) (*events.SQSEventResponse, error)
+	}
+
 	sqsEventHandlerInitializer interface {
 		nacelle.Initializer
 		SQSEventHandler
@@ -26,10 +30,10 @@ type (
 	}
 )
 
-func NewSQSEventServer(handler SQSEventHandler) *Server {
+func NewSQSEventServer(handler SQSEventHandler, configs ...ConfigFunc) *Server {
 	return NewServer(&sqsEventHandler{
 		handler: handler,
-	})
+	}, configs...)
 }
 
 func (h *sqsEventHandler) Init(ctx context.Context) error {
@@ -39,7 +43,7 @@ func (h *sqsEventHandler) Init(ctx context.Context) error {
 func (h *sqsEventHandler) Invoke(ctx context.Context, payload []byte) ([]byte, error) {
 	event := &events.SQSEvent{}
 	if err := json.Unmarshal(payload, &event); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal event (%s)", err.Error())
+		return nil, fmt.Errorf("failed to unmarshal event (%w)", err)
 	}
 
 	logger := h.Logger.WithFields(map[string]interface{}{
@@ -48,10 +52,35 @@ func (h *sqsEventHandler) Invoke(ctx context.Context, payload []byte) ([]byte, e
 
 	logger.Debug("Received %d SQS messages", len(event.Records))
 
+	if responseHandler, ok := h.handler.(SQSEventResponseHandler); ok {
+		return h.invokeWithResponse(ctx, responseHandler, event.Records, logger)
+	}
+
 	if err := h.handler.Handle(ctx, event.Records, logger); err != nil {
-		return nil, fmt.Errorf("failed to process SQS event (%s)", err.Error())
+		return nil, fmt.Errorf("failed to process SQS event (%w)", err)
 	}
 
 	logger.Debug("SQS event handled successfully")
 	return nil, nil
 }
+
+func (h *sqsEventHandler) invokeWithResponse(ctx context.Context, handler SQSEventResponseHandler, batch []events.SQSMessage, logger nacelle.Logger) ([]byte, error) {
+	response, err := handler.HandleWithResponse(ctx, batch, logger)
+	if err != nil {
+		return nil, fmt.Errorf("failed to process SQS event (%w)", err)
+	}
+
+	if response == nil {
+		logger.Debug("SQS event handled successfully")
+		return nil, nil
+	}
+
+	logger.Debug("SQS event handled with %d failed messages", len(response.BatchItemFailures))
+
+	serialized, err := json.Marshal(response)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal SQS event response (%w)", err)
+	}
+
+	return serialized, nil
+}
@@ -2,7 +2,6 @@ package lambdabase
 
 import (
 	"context"
-	"fmt"
 
 	"github.com/aws/aws-lambda-go/events"
 	"github.com/go-nacelle/nacelle/v2"
@@ -19,35 +18,95 @@ type (
 	}
 
 	sqsMessageHandler struct {
-		Logger   nacelle.Logger            `service:"logger"`
-		Services *nacelle.ServiceContainer `service:"services"`
-		handler  SQSMessageHandler
+		Logger                  nacelle.Logger            `service:"logger"`
+		Services                *nacelle.ServiceContainer `service:"services"`
+		handler                 SQSMessageHandler
+		reportBatchItemFailures bool
+		recordConcurrency       int
+		recordMiddleware        []RecordMiddleware
 	}
 )
 
-func NewSQSRecordServer(handler SQSMessageHandler) *Server {
+func NewSQSRecordServer(handler SQSMessageHandler, configs ...ConfigFunc) *Server {
+	options := getOptions(configs)
+
 	return NewSQSEventServer(&sqsMessageHandler{
-		handler: handler,
-	})
+		handler:                 handler,
+		reportBatchItemFailures: options.reportBatchItemFailures,
+		recordConcurrency:       options.recordConcurrency,
+		recordMiddleware:        options.recordMiddleware,
+	}, configs...)
 }
 
 func (s *sqsMessageHandler) Init(ctx context.Context) error {
+	recordConcurrency, err := loadRecordConcurrency(ctx, s.recordConcurrency)
+	if err != nil {
+		return err
+	}
+	s.recordConcurrency = recordConcurrency
+
+	reportBatchItemFailures, err := loadReportBatchItemFailures(ctx, s.reportBatchItemFailures)
+	if err != nil {
+		return err
+	}
+	s.reportBatchItemFailures = reportBatchItemFailures
+
 	return doInit(ctx, s.Services, s.handler)
 }
 
 func (h *sqsMessageHandler) Handle(ctx context.Context, batch []events.SQSMessage, logger nacelle.Logger) error {
-	for _, message := range batch {
+	errs := h.handleMessages(ctx, batch, logger, true)
+
+	if i := firstFailure(errs); i >= 0 {
+		return newRecordError("SQS", "message", batch[i].MessageId, errs[i])
+	}
+
+	logger.Debug("SQS message handled successfully")
+	return nil
+}
+
+func (h *sqsMessageHandler) HandleWithResponse(ctx context.Context, batch []events.SQSMessage, logger nacelle.Logger) (*events.SQSEventResponse, error) {
+	if !h.reportBatchItemFailures {
+		return nil, h.Handle(ctx, batch, logger)
+	}
+
+	response := &events.SQSEventResponse{
+		BatchItemFailures: []events.SQSBatchItemFailure{},
+	}
+
+	for i, err := range h.handleMessages(ctx, batch, logger, false) {
+		if err == nil {
+			continue
+		}
+
 		messageLogger := logger.WithFields(map[string]interface{}{
-			"messageId": message.MessageId,
+			"messageId": batch[i].MessageId,
 		})
 
-		logger.Debug("Handling message")
+		messageLogger.Error("Failed to process SQS message (%s)", err.Error())
 
-		if err := h.handler.Handle(ctx, message, messageLogger); err != nil {
-			return fmt.Errorf("failed to process SQS message %s (%s)", message.MessageId, err.Error())
-		}
+		response.BatchItemFailures = append(response.BatchItemFailures, events.SQSBatchItemFailure{
+			ItemIdentifier: batch[i].MessageId,
+		})
 	}
 
-	logger.Debug("SQS message handled successfully")
-	return nil
+	return response, nil
+}
+
+func (h *sqsMessageHandler) handleMessages(ctx context.Context, batch []events.SQSMessage, logger nacelle.Logger, haltOnError bool) []error {
+	// Messages of a FIFO queue must be handled in order within their message
+	// group. Messages of a standard queue have no group and are independent.
+	keys := make([]string, 0, len(batch))
+	for _, message := range batch {
+		keys = append(keys, message.Attributes["MessageGroupId"])
+	}
+
+	return processBatch(keys, h.recordConcurrency, haltOnError, func(i int) error {
+		messageLogger := logger.WithFields(map[string]interface{}{
+			"messageId": batch[i].MessageId,
+		})
+
+		messageLogger.Debug("Handling message")
+		return invokeRecordHandler(ctx, h.recordMiddleware, batch[i], messageLogger, h.handler.Handle)
+	})
 }
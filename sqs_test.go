@@ -159,6 +159,21 @@ func TestSQSEventInvokeError(t *testing.T) {
 	require.EqualError(t, err, "failed to process SQS event (oops)")
 }
 
+func TestSQSEventInvokeWithResponse(t *testing.T) {
+	handler := NewMockSqsMessageHandlerInitializer()
+	handler.HandleFunc.PushReturn(nil)
+	handler.HandleFunc.PushReturn(fmt.Errorf("oops"))
+	outer := &sqsEventHandler{
+		handler: &sqsMessageHandler{handler: handler, reportBatchItemFailures: true},
+		Logger:  nacelle.NewNilLogger(),
+	}
+
+	response, err := outer.Invoke(context.Background(), []byte(testSQSPayload))
+	require.Nil(t, err)
+	require.JSONEq(t, `{"batchItemFailures": [{"itemIdentifier": "m2"}]}`, string(response))
+	mockassert.CalledN(t, handler.HandleFunc, 3)
+}
+
 func TestSQSMessageHandle(t *testing.T) {
 	handler := NewMockSqsMessageHandlerInitializer()
 	outer := &sqsMessageHandler{handler: handler}
@@ -182,6 +197,107 @@ func TestSQSMessageHandleError(t *testing.T) {
 	mockassert.CalledN(t, handler.HandleFunc, 2)
 }
 
+func TestSQSMessageHandleWithResponse(t *testing.T) {
+	handler := NewMockSqsMessageHandlerInitializer()
+	handler.HandleFunc.PushReturn(fmt.Errorf("oops"))
+	handler.HandleFunc.PushReturn(nil)
+	handler.HandleFunc.PushReturn(fmt.Errorf("oops"))
+	outer := &sqsMessageHandler{handler: handler, reportBatchItemFailures: true}
+
+	response, err := outer.HandleWithResponse(context.Background(), testSQSMessages, nacelle.NewNilLogger())
+	require.Nil(t, err)
+	require.Equal(t, []events.SQSBatchItemFailure{{ItemIdentifier: "m1"}, {ItemIdentifier: "m3"}}, response.BatchItemFailures)
+	mockassert.CalledN(t, handler.HandleFunc, 3)
+}
+
+func TestSQSMessageHandleWithResponseDisabled(t *testing.T) {
+	handler := NewMockSqsMessageHandlerInitializer()
+	handler.HandleFunc.PushReturn(nil)
+	handler.HandleFunc.PushReturn(fmt.Errorf("oops"))
+	outer := &sqsMessageHandler{handler: handler}
+
+	response, err := outer.HandleWithResponse(context.Background(), testSQSMessages, nacelle.NewNilLogger())
+	require.EqualError(t, err, "failed to process SQS message m2 (oops)")
+	require.Nil(t, response)
+	mockassert.CalledN(t, handler.HandleFunc, 2)
+}
+
+func TestSQSMessageHandleConcurrent(t *testing.T) {
+	handler := NewMockSqsMessageHandlerInitializer()
+	handler.HandleFunc.SetDefaultHook(func(ctx context.Context, message events.SQSMessage, logger nacelle.Logger) error {
+		if message.MessageId == "m2" {
+			return fmt.Errorf("oops")
+		}
+
+		return nil
+	})
+	outer := &sqsMessageHandler{handler: handler, reportBatchItemFailures: true, recordConcurrency: 3}
+
+	response, err := outer.HandleWithResponse(context.Background(), testSQSMessages, nacelle.NewNilLogger())
+	require.Nil(t, err)
+	require.Equal(t, []events.SQSBatchItemFailure{{ItemIdentifier: "m2"}}, response.BatchItemFailures)
+	mockassert.CalledN(t, handler.HandleFunc, 3)
+}
+
+func TestTypedSQSMessageHandle(t *testing.T) {
+	handler := &testTypedSQSMessageHandler{}
+	outer := &typedSQSMessageHandler[testTypedValue]{handler: handler, codec: JSONCodec{}}
+
+	message := events.SQSMessage{MessageId: "m1", Body: `{"name": "foo", "count": 3}`}
+	err := outer.Handle(context.Background(), message, nacelle.NewNilLogger())
+	require.Nil(t, err)
+	require.Equal(t, []testTypedValue{{Name: "foo", Count: 3}}, handler.values)
+}
+
+func TestTypedSQSMessageHandleDecodeError(t *testing.T) {
+	handler := &testTypedSQSMessageHandler{}
+	outer := &sqsMessageHandler{
+		handler:                 &typedSQSMessageHandler[testTypedValue]{handler: handler, codec: JSONCodec{}},
+		reportBatchItemFailures: true,
+	}
+
+	batch := []events.SQSMessage{
+		{MessageId: "m1", Body: `{"name": "foo"}`},
+		{MessageId: "m2", Body: `not json`},
+		{MessageId: "m3", Body: `{"name": "baz"}`},
+	}
+
+	response, err := outer.HandleWithResponse(context.Background(), batch, nacelle.NewNilLogger())
+	require.Nil(t, err)
+	require.Equal(t, []events.SQSBatchItemFailure{{ItemIdentifier: "m2"}}, response.BatchItemFailures)
+	require.Equal(t, []testTypedValue{{Name: "foo"}, {Name: "baz"}}, handler.values)
+}
+
+func TestTypedSQSMessageHandleCodec(t *testing.T) {
+	handler := &testTypedSQSMessageHandler{}
+	codec := CodecFunc(func(data []byte, v interface{}) error {
+		v.(*testTypedValue).Name = string(data)
+		return nil
+	})
+	outer := &typedSQSMessageHandler[testTypedValue]{handler: handler, codec: codec}
+
+	err := outer.Handle(context.Background(), events.SQSMessage{MessageId: "m1", Body: "foo"}, nacelle.NewNilLogger())
+	require.Nil(t, err)
+	require.Equal(t, []testTypedValue{{Name: "foo"}}, handler.values)
+}
+
+//
+// Typed
+
+type testTypedValue struct {
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
+
+type testTypedSQSMessageHandler struct {
+	values []testTypedValue
+}
+
+func (h *testTypedSQSMessageHandler) Handle(ctx context.Context, value testTypedValue, message events.SQSMessage, logger nacelle.Logger) error {
+	h.values = append(h.values, value)
+	return nil
+}
+
 //
 // Bad Injection
 
@@ -0,0 +1,42 @@
+package lambdabase
+
+import (
+	"context"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/go-nacelle/nacelle/v2"
+)
+
+type (
+	TypedSQSMessageHandler[T any] interface {
+		Handle(ctx context.Context, value T, message events.SQSMessage, logger nacelle.Logger) error
+	}
+
+	typedSQSMessageHandler[T any] struct {
+		Services *nacelle.ServiceContainer `service:"services"`
+		handler  TypedSQSMessageHandler[T]
+		codec    Codec
+	}
+)
+
+func NewTypedSQSServer[T any](handler TypedSQSMessageHandler[T], configs ...ConfigFunc) *Server {
+	options := getOptions(configs)
+
+	return NewSQSRecordServer(&typedSQSMessageHandler[T]{
+		handler: handler,
+		codec:   options.codec,
+	}, configs...)
+}
+
+func (h *typedSQSMessageHandler[T]) Init(ctx context.Context) error {
+	return doInit(ctx, h.Services, h.handler)
+}
+
+func (h *typedSQSMessageHandler[T]) Handle(ctx context.Context, message events.SQSMessage, logger nacelle.Logger) error {
+	var value T
+	if err := h.codec.Unmarshal([]byte(message.Body), &value); err != nil {
+		return &DecodeError{Part: "SQS message body", Err: err}
+	}
+
+	return h.handler.Handle(ctx, value, message, logger)
+}